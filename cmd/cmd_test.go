@@ -1,11 +1,14 @@
 package cmd
 
 import (
+	"os"
+	"path/filepath"
 	"reflect"
 	"testing"
 
 	"github.com/hbbtekademy/go-fileconv/pkg/fileconv"
 	"github.com/hbbtekademy/go-fileconv/pkg/param"
+	"github.com/hbbtekademy/go-fileconv/pkg/param/transformparam"
 	"github.com/spf13/cobra"
 )
 
@@ -67,12 +70,12 @@ func TestGetJsonReadFlags(t *testing.T) {
 	tests := []struct {
 		name          string
 		setFlags      func(cmd *cobra.Command)
-		expectedFlags *json2ParquetFlags
+		expectedFlags *jsonReadFlags
 	}{
 		{
 			name:     "TC1",
 			setFlags: func(cmd *cobra.Command) {},
-			expectedFlags: &json2ParquetFlags{
+			expectedFlags: &jsonReadFlags{
 				disableAutodetect: false,
 				compression:       "auto",
 				convStr2Int:       false,
@@ -87,7 +90,13 @@ func TestGetJsonReadFlags(t *testing.T) {
 				sampleSize:        20480,
 				timestampformat:   "iso",
 				unionByName:       false,
+				maxRejects:        -1,
 				columns:           param.Columns{},
+				flattenSep:        "_",
+				flattenMaxDepth:   -1,
+				flattenArrays:     "keep",
+				flattenCollision:  "error",
+				recordsCarry:      []string{},
 			},
 		},
 		{
@@ -108,8 +117,16 @@ func TestGetJsonReadFlags(t *testing.T) {
 				cmd.Flags().Set("ignore-errors", "true")
 				cmd.Flags().Set("union-by-name", "true")
 				cmd.Flags().Set("columns", "key1:INTEGER,key:2:VARCHAR")
+				cmd.Flags().Set("rejects", "rejects.json")
+				cmd.Flags().Set("max-rejects", "10")
+				cmd.Flags().Set("flatten-sep", ".")
+				cmd.Flags().Set("flatten-max-depth", "2")
+				cmd.Flags().Set("flatten-arrays", "explode")
+				cmd.Flags().Set("flatten-collision", "rename")
+				cmd.Flags().Set("records-path", "$.data.items[*]")
+				cmd.Flags().Set("records-carry", "meta.generated_at,meta.source")
 			},
-			expectedFlags: &json2ParquetFlags{
+			expectedFlags: &jsonReadFlags{
 				disableAutodetect: true,
 				compression:       "gzip",
 				convStr2Int:       true,
@@ -128,6 +145,97 @@ func TestGetJsonReadFlags(t *testing.T) {
 					{Name: "key1", Type: "INTEGER"},
 					{Name: "key:2", Type: "VARCHAR"},
 				},
+				rejects:          "rejects.json",
+				maxRejects:       10,
+				flatten:          true,
+				flattenSep:       ".",
+				flattenMaxDepth:  2,
+				flattenArrays:    "explode",
+				flattenCollision: "rename",
+				recordsPath:      "$.data.items[*]",
+				recordsCarry:     []string{"meta.generated_at", "meta.source"},
+			},
+		},
+		{
+			name: "TC3",
+			setFlags: func(cmd *cobra.Command) {
+				cmd.Flags().Set("schema-file", "../testdata/schema/iris_schema.json")
+			},
+			expectedFlags: &jsonReadFlags{
+				compression:      "auto",
+				dateformat:       "iso",
+				format:           "array",
+				maxDepth:         -1,
+				maxObjSize:       16777216,
+				records:          "auto",
+				sampleSize:       20480,
+				timestampformat:  "iso",
+				maxRejects:       -1,
+				flattenSep:       "_",
+				flattenMaxDepth:  -1,
+				flattenArrays:    "keep",
+				flattenCollision: "error",
+				recordsCarry:     []string{},
+				columns: param.Columns{
+					{Name: "sepal_length", Type: "DOUBLE"},
+					{Name: "sepal_width", Type: "DOUBLE"},
+					{Name: "petal_length", Type: "DECIMAL(4,1)"},
+					{Name: "petal_width", Type: "FLOAT"},
+					{Name: "species", Type: "VARCHAR"},
+				},
+			},
+		},
+		{
+			name: "TC4",
+			setFlags: func(cmd *cobra.Command) {
+				cmd.Flags().Set("records-path", "$.data.items[*]")
+			},
+			expectedFlags: &jsonReadFlags{
+				compression:      "auto",
+				dateformat:       "iso",
+				format:           "auto",
+				maxDepth:         -1,
+				maxObjSize:       16777216,
+				records:          "auto",
+				sampleSize:       20480,
+				timestampformat:  "iso",
+				maxRejects:       -1,
+				columns:          param.Columns{},
+				flattenSep:       "_",
+				flattenMaxDepth:  -1,
+				flattenArrays:    "keep",
+				flattenCollision: "error",
+				recordsPath:      "$.data.items[*]",
+				recordsCarry:     []string{},
+			},
+		},
+		{
+			name: "TC5",
+			setFlags: func(cmd *cobra.Command) {
+				cmd.Flags().Set("jsonschema-file", "../testdata/schema/iris_jsonschema.json")
+			},
+			expectedFlags: &jsonReadFlags{
+				compression:      "auto",
+				dateformat:       "iso",
+				format:           "array",
+				maxDepth:         -1,
+				maxObjSize:       16777216,
+				records:          "auto",
+				sampleSize:       20480,
+				timestampformat:  "iso",
+				maxRejects:       -1,
+				flattenSep:       "_",
+				flattenMaxDepth:  -1,
+				flattenArrays:    "keep",
+				flattenCollision: "error",
+				recordsCarry:     []string{},
+				columns: param.Columns{
+					{Name: "sepalLength", Type: "DOUBLE"},
+					{Name: "sepalWidth", Type: "DOUBLE"},
+					{Name: "petalLength", Type: "DOUBLE"},
+					{Name: "petalWidth", Type: "DOUBLE"},
+					{Name: "species", Type: "VARCHAR"},
+				},
 			},
 		},
 	}
@@ -155,12 +263,12 @@ func TestGetCsvReadFlags(t *testing.T) {
 	tests := []struct {
 		name          string
 		setFlags      func(cmd *cobra.Command)
-		expectedFlags *csv2ParquetFlags
+		expectedFlags *csvReadFlags
 	}{
 		{
 			name:     "TC1",
 			setFlags: func(cmd *cobra.Command) {},
-			expectedFlags: &csv2ParquetFlags{
+			expectedFlags: &csvReadFlags{
 				allVarchar:         false,
 				disableQuotedNulls: false,
 				disableAutodetect:  false,
@@ -189,6 +297,7 @@ func TestGetCsvReadFlags(t *testing.T) {
 				timestampformat:    "",
 				types:              param.Columns{},
 				unionByName:        false,
+				maxRejects:         -1,
 			},
 		},
 		{
@@ -222,8 +331,10 @@ func TestGetCsvReadFlags(t *testing.T) {
 				cmd.Flags().Set("null-padding", "true")
 				cmd.Flags().Set("parallel", "true")
 				cmd.Flags().Set("union-by-name", "true")
+				cmd.Flags().Set("rejects", "rejects.csv")
+				cmd.Flags().Set("max-rejects", "5")
 			},
-			expectedFlags: &csv2ParquetFlags{
+			expectedFlags: &csvReadFlags{
 				allVarchar:         true,
 				disableQuotedNulls: true,
 				disableAutodetect:  true,
@@ -257,6 +368,67 @@ func TestGetCsvReadFlags(t *testing.T) {
 					{Name: "col3", Type: "VARCHAR"},
 				},
 				unionByName: true,
+				rejects:     "rejects.csv",
+				maxRejects:  5,
+			},
+		},
+		{
+			name: "TC3",
+			setFlags: func(cmd *cobra.Command) {
+				cmd.Flags().Set("schema-file", "../testdata/schema/iris_schema.yaml")
+			},
+			expectedFlags: &csvReadFlags{
+				disableAutodetect:  true,
+				autoTypeCandidates: []string{},
+				columns: []param.Column{
+					{Name: "sepal_length", Type: "DOUBLE"},
+					{Name: "sepal_width", Type: "DOUBLE"},
+					{Name: "petal_length", Type: "DECIMAL(4,1)"},
+					{Name: "petal_width", Type: "FLOAT"},
+					{Name: "species", Type: "VARCHAR"},
+				},
+				compression:      "auto",
+				decimalSeparator: ".",
+				delim:            ",",
+				escape:           `"`,
+				forceNotNull:     []string{},
+				maxLineSize:      2097152,
+				names:            []string{},
+				nullStr:          []string{},
+				quote:            `"`,
+				sampleSize:       20480,
+				types:            param.Columns{},
+				maxRejects:       -1,
+			},
+		},
+		{
+			name: "TC4",
+			setFlags: func(cmd *cobra.Command) {
+				cmd.Flags().Set("schema-file", "../testdata/schema/iris_schema.yaml")
+				cmd.Flags().Set("schema-file-types", "true")
+			},
+			expectedFlags: &csvReadFlags{
+				disableAutodetect:  false,
+				autoTypeCandidates: []string{},
+				columns:            param.Columns{},
+				compression:        "auto",
+				decimalSeparator:   ".",
+				delim:              ",",
+				escape:             `"`,
+				forceNotNull:       []string{},
+				maxLineSize:        2097152,
+				names:              []string{},
+				nullStr:            []string{},
+				quote:              `"`,
+				sampleSize:         20480,
+				types: []param.Column{
+					{Name: "sepal_length", Type: "DOUBLE"},
+					{Name: "sepal_width", Type: "DOUBLE"},
+					{Name: "petal_length", Type: "DECIMAL(4,1)"},
+					{Name: "petal_width", Type: "FLOAT"},
+					{Name: "species", Type: "VARCHAR"},
+				},
+				maxRejects: -1,
 			},
 		},
 	}
@@ -279,6 +451,98 @@ func TestGetCsvReadFlags(t *testing.T) {
 	}
 }
 
+func TestGetJsonReadFlagsErr(t *testing.T) {
+	tests := []struct {
+		name        string
+		setFlags    func(cmd *cobra.Command)
+		expectedErr string
+	}{
+		{
+			name: "TC1",
+			setFlags: func(cmd *cobra.Command) {
+				cmd.Flags().Set("jsonschema-file", "../testdata/schema/iris_jsonschema.json")
+				cmd.Flags().Set("schema-file", "../testdata/schema/iris_schema.json")
+			},
+			expectedErr: "jsonschema-file flag cannot be used together with the columns or schema-file flags",
+		},
+		{
+			name: "TC2",
+			setFlags: func(cmd *cobra.Command) {
+				cmd.Flags().Set("jsonschema-file", "../testdata/schema/iris_schema.json")
+			},
+			expectedErr: "invalid json schema file: ../testdata/schema/iris_schema.json. error: the root of the json schema must be an object, or an array of objects, with properties",
+		},
+		{
+			name: "TC3",
+			setFlags: func(cmd *cobra.Command) {
+				cmd.Flags().Set("records-carry", "meta.generated_at")
+			},
+			expectedErr: "records-carry flag requires the records-path flag",
+		},
+	}
+
+	mockCmd := &cobra.Command{}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCmd.ResetFlags()
+			registerJson2ParquetFlags(mockCmd)
+
+			tc.setFlags(mockCmd)
+			_, err := getJsonReadFlags(mockCmd.LocalFlags())
+			if err == nil || err.Error() != tc.expectedErr {
+				t.Fatalf("expected error: %s but got: %v", tc.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestGetCsvReadFlagsErr(t *testing.T) {
+	tests := []struct {
+		name        string
+		setFlags    func(cmd *cobra.Command)
+		expectedErr string
+	}{
+		{
+			name: "TC1",
+			setFlags: func(cmd *cobra.Command) {
+				cmd.Flags().Set("schema-file", "../testdata/schema/iris_schema.yaml")
+				cmd.Flags().Set("columns", "col1:BIGINT")
+			},
+			expectedErr: "schema-file and columns flags cannot be used together",
+		},
+		{
+			name: "TC2",
+			setFlags: func(cmd *cobra.Command) {
+				cmd.Flags().Set("schema-file", "../testdata/schema/iris_schema.yaml")
+				cmd.Flags().Set("schema-file-types", "true")
+				cmd.Flags().Set("types", "col1:BIGINT")
+			},
+			expectedErr: "schema-file and types flags cannot be used together",
+		},
+		{
+			name: "TC3",
+			setFlags: func(cmd *cobra.Command) {
+				cmd.Flags().Set("types", "col1:BIGNT")
+			},
+			expectedErr: "invalid types flag. error: invalid type: BIGNT. error: unknown type: BIGNT",
+		},
+	}
+
+	mockCmd := &cobra.Command{}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCmd.ResetFlags()
+			registerCsv2ParquetFlags(mockCmd)
+
+			tc.setFlags(mockCmd)
+			_, err := getCsvReadFlags(mockCmd.LocalFlags())
+			if err == nil || err.Error() != tc.expectedErr {
+				t.Fatalf("expected error: %s but got: %v", tc.expectedErr, err)
+			}
+		})
+	}
+}
+
 func TestGetDuckDBConfig(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -313,3 +577,811 @@ func TestGetDuckDBConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestGetTransformFlags(t *testing.T) {
+	zero, one, ten := float64(0), float64(1), float64(10)
+	tests := []struct {
+		name          string
+		setFlags      func(cmd *cobra.Command)
+		expectedFlags *transformFlags
+	}{
+		{
+			name:     "TC1",
+			setFlags: func(cmd *cobra.Command) {},
+			expectedFlags: &transformFlags{
+				selectCols: []string{},
+				exclude:    []string{},
+				where:      "",
+				limit:      0,
+			},
+		},
+		{
+			name: "TC2",
+			setFlags: func(cmd *cobra.Command) {
+				cmd.PersistentFlags().Set(TRANSFORM_SELECT, "species,sepal_length")
+				cmd.PersistentFlags().Set(TRANSFORM_EXCLUDE, "sepal_length")
+				cmd.PersistentFlags().Set(TRANSFORM_WHERE, "species = 'setosa'")
+				cmd.PersistentFlags().Set(TRANSFORM_LIMIT, "10")
+			},
+			expectedFlags: &transformFlags{
+				selectCols: []string{"species", "sepal_length"},
+				exclude:    []string{"sepal_length"},
+				where:      "species = 'setosa'",
+				limit:      10,
+			},
+		},
+		{
+			name: "TC3",
+			setFlags: func(cmd *cobra.Command) {
+				cmd.PersistentFlags().Set(TRANSFORM_MAPPING, "../testdata/mapping/iris_mapping.yaml")
+			},
+			expectedFlags: &transformFlags{
+				selectCols: []string{},
+				exclude:    []string{},
+				where:      "",
+				limit:      0,
+				mapping: &transformparam.Mapping{
+					Columns: []transformparam.ColumnMapping{
+						{Name: "species", Rename: "iris_species", Default: "unknown"},
+						{Name: "sepal_length", Cast: "DECIMAL(4,1)", Default: 0},
+					},
+					Computed: []transformparam.ComputedColumn{
+						{Name: "petal_ratio", Expr: "petal_length / petal_width", Cast: "DOUBLE"},
+					},
+				},
+			},
+		}, {
+			name: "TC4",
+			setFlags: func(cmd *cobra.Command) {
+				cmd.PersistentFlags().Set(TRANSFORM_RULES, "../testdata/rules/iris_rules.yaml")
+				cmd.PersistentFlags().Set(TRANSFORM_REPORT, "report.json")
+			},
+			expectedFlags: &transformFlags{
+				selectCols: []string{},
+				exclude:    []string{},
+				where:      "",
+				limit:      0,
+				rules: &transformparam.Rules{
+					Rules: []transformparam.Rule{
+						{Check: transformparam.NotNull, Column: "species"},
+						{Name: "known_species", Check: transformparam.AllowedValues, Column: "species",
+							Values: []any{"Iris-setosa", "Iris-versicolor", "Iris-virginica"}},
+						{Check: transformparam.Range, Column: "sepal_length", Min: &zero, Max: &ten, Severity: transformparam.SeverityWarn},
+						{Check: transformparam.RowCount, Min: &one},
+					},
+				},
+				rulesReport: "report.json",
+			},
+		},
+	}
+
+	mockCmd := &cobra.Command{}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCmd.ResetFlags()
+			registerGlobalFlags(mockCmd)
+
+			tc.setFlags(mockCmd)
+			actual, err := getTransformFlags(mockCmd)
+			if err != nil {
+				t.Fatalf("failed getting transform flags. error: %v", err)
+			}
+
+			if !reflect.DeepEqual(tc.expectedFlags, actual) {
+				t.Fatalf("expected: %v but got: %v", tc.expectedFlags, actual)
+			}
+		})
+	}
+}
+
+func TestGetCsvWriteFlags(t *testing.T) {
+	tests := []struct {
+		name          string
+		setFlags      func(cmd *cobra.Command)
+		expectedFlags *csvWriteFlags
+	}{
+		{
+			name:     "TC1",
+			setFlags: func(cmd *cobra.Command) {},
+			expectedFlags: &csvWriteFlags{
+				delim:           ",",
+				quote:           `"`,
+				escape:          `"`,
+				disableHeader:   false,
+				nullStr:         "",
+				dateformat:      "",
+				timestampformat: "",
+				forceQuote:      []string{},
+				compression:     "auto",
+			},
+		},
+		{
+			name: "TC2",
+			setFlags: func(cmd *cobra.Command) {
+				cmd.PersistentFlags().Set(CSV_DELIM, "|")
+				cmd.PersistentFlags().Set(CSV_QUOTE, "'")
+				cmd.PersistentFlags().Set(CSV_ESCAPE, "\\")
+				cmd.PersistentFlags().Set(CSV_DISABLE_HEADER, "true")
+				cmd.PersistentFlags().Set(CSV_NULLSTR, "NULL")
+				cmd.PersistentFlags().Set(CSV_DATEFORMAT, "%d")
+				cmd.PersistentFlags().Set(CSV_TIMESTAMPFORMAT, "%d")
+				cmd.PersistentFlags().Set(CSV_FORCE_QUOTE, "col1,col2")
+				cmd.PersistentFlags().Set(CSV_COMPRESSION, "gzip")
+			},
+			expectedFlags: &csvWriteFlags{
+				delim:           "|",
+				quote:           "'",
+				escape:          "\\",
+				disableHeader:   true,
+				nullStr:         "NULL",
+				dateformat:      "%d",
+				timestampformat: "%d",
+				forceQuote:      []string{"col1", "col2"},
+				compression:     "gzip",
+			},
+		},
+	}
+
+	mockCmd := &cobra.Command{}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCmd.ResetFlags()
+			registerCsvWriteFlags(mockCmd)
+
+			tc.setFlags(mockCmd)
+			actual, err := getCsvWriteFlags(mockCmd.PersistentFlags())
+			if err != nil {
+				t.Fatalf("failed getting csv write flags. error: %v", err)
+			}
+			if !reflect.DeepEqual(actual, tc.expectedFlags) {
+				t.Fatalf("expected:\n%#v\nbut got:\n%#v", tc.expectedFlags, actual)
+			}
+		})
+	}
+}
+
+func TestGetPqReadFlags(t *testing.T) {
+	tests := []struct {
+		name          string
+		setFlags      func(cmd *cobra.Command)
+		expectedFlags *pqReadFlags
+	}{
+		{
+			name:          "TC1",
+			setFlags:      func(cmd *cobra.Command) {},
+			expectedFlags: &pqReadFlags{},
+		},
+		{
+			name: "TC2",
+			setFlags: func(cmd *cobra.Command) {
+				cmd.Flags().Set("binary-as-string", "true")
+				cmd.Flags().Set("filename", "true")
+				cmd.Flags().Set("file-row-number", "true")
+				cmd.Flags().Set("hive-partitioning", "true")
+				cmd.Flags().Set("union-by-name", "true")
+			},
+			expectedFlags: &pqReadFlags{
+				binaryAsString:   true,
+				filename:         true,
+				fileRowNum:       true,
+				hivePartitioning: true,
+				unionByName:      true,
+			},
+		},
+	}
+
+	mockCmd := &cobra.Command{}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCmd.ResetFlags()
+			registerParquet2CsvFlags(mockCmd)
+
+			tc.setFlags(mockCmd)
+			actual, err := getPqReadFlags(mockCmd.LocalFlags())
+			if err != nil {
+				t.Fatalf("failed getting parquet read flags. error: %v", err)
+			}
+			if !reflect.DeepEqual(actual, tc.expectedFlags) {
+				t.Fatalf("expected:\n%#v\nbut got:\n%#v", tc.expectedFlags, actual)
+			}
+		})
+	}
+}
+
+func TestGetJsonWriteFlags(t *testing.T) {
+	tests := []struct {
+		name          string
+		setFlags      func(cmd *cobra.Command)
+		expectedFlags *jsonWriteFlags
+	}{
+		{
+			name:     "TC1",
+			setFlags: func(cmd *cobra.Command) {},
+			expectedFlags: &jsonWriteFlags{
+				format:          "newline_delimited",
+				compression:     "auto",
+				dateformat:      "",
+				timestampformat: "",
+				nestedAsString:  false,
+				unflattenSep:    "_",
+				unflattenGroups: []string{},
+			},
+		},
+		{
+			name: "TC2",
+			setFlags: func(cmd *cobra.Command) {
+				cmd.PersistentFlags().Set(JSON_FORMAT, "array")
+				cmd.PersistentFlags().Set(JSON_COMPRESSION, "gzip")
+				cmd.PersistentFlags().Set(JSON_DATEFORMAT, "%d")
+				cmd.PersistentFlags().Set(JSON_TIMESTAMPFORMAT, "%d")
+				cmd.PersistentFlags().Set(JSON_NESTED_AS_STRING, "true")
+			},
+			expectedFlags: &jsonWriteFlags{
+				format:          "array",
+				compression:     "gzip",
+				dateformat:      "%d",
+				timestampformat: "%d",
+				nestedAsString:  true,
+				unflattenSep:    "_",
+				unflattenGroups: []string{},
+			},
+		},
+		{
+			name: "TC3",
+			setFlags: func(cmd *cobra.Command) {
+				cmd.PersistentFlags().Set(JSON_UNFLATTEN_SEP, ".")
+				cmd.PersistentFlags().Set(JSON_UNFLATTEN_GROUPS, "address,address.geo")
+			},
+			expectedFlags: &jsonWriteFlags{
+				format:          "newline_delimited",
+				compression:     "auto",
+				unflatten:       true,
+				unflattenSep:    ".",
+				unflattenGroups: []string{"address", "address.geo"},
+			},
+		},
+	}
+
+	mockCmd := &cobra.Command{}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCmd.ResetFlags()
+			registerJsonWriteFlags(mockCmd)
+
+			tc.setFlags(mockCmd)
+			actual, err := getJsonWriteFlags(mockCmd.PersistentFlags())
+			if err != nil {
+				t.Fatalf("failed getting json write flags. error: %v", err)
+			}
+			if !reflect.DeepEqual(actual, tc.expectedFlags) {
+				t.Fatalf("expected:\n%#v\nbut got:\n%#v", tc.expectedFlags, actual)
+			}
+		})
+	}
+}
+
+func TestGetConvertPrefixedReadFlags(t *testing.T) {
+	tests := []struct {
+		name            string
+		setFlags        func(cmd *cobra.Command)
+		expectedPqFlags *pqReadFlags
+	}{
+		{
+			name:            "TC1",
+			setFlags:        func(cmd *cobra.Command) {},
+			expectedPqFlags: &pqReadFlags{},
+		},
+		{
+			name: "TC2",
+			setFlags: func(cmd *cobra.Command) {
+				cmd.Flags().Set("in-pq-filename", "true")
+				cmd.Flags().Set("in-pq-union-by-name", "true")
+				cmd.Flags().Set("in-csv-filename", "false")
+			},
+			expectedPqFlags: &pqReadFlags{
+				filename:    true,
+				unionByName: true,
+			},
+		},
+	}
+
+	mockCmd := &cobra.Command{}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCmd.ResetFlags()
+			registerConvertFlags(mockCmd)
+
+			tc.setFlags(mockCmd)
+			actual, err := getPqReadFlags(unprefixedFlags(mockCmd.Flags(), IN_PQ_PREFIX))
+			if err != nil {
+				t.Fatalf("failed getting parquet read flags. error: %v", err)
+			}
+			if !reflect.DeepEqual(actual, tc.expectedPqFlags) {
+				t.Fatalf("expected:\n%#v\nbut got:\n%#v", tc.expectedPqFlags, actual)
+			}
+		})
+	}
+}
+
+func TestGetFormatFlag(t *testing.T) {
+	tests := []struct {
+		name           string
+		format         string
+		expectedFormat fileconv.Format
+		expectedErr    bool
+	}{
+		{name: "TC1", format: "csv", expectedFormat: fileconv.Csv},
+		{name: "TC2", format: "json", expectedFormat: fileconv.Json},
+		{name: "TC3", format: "parquet", expectedFormat: fileconv.Parquet},
+		{name: "TC4", format: "xml", expectedErr: true},
+	}
+
+	mockCmd := &cobra.Command{}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCmd.ResetFlags()
+			registerConvertFlags(mockCmd)
+			mockCmd.Flags().Set("from", tc.format)
+
+			actual, err := getFormatFlag(mockCmd.Flags(), "from")
+			if tc.expectedErr {
+				if err == nil {
+					t.Fatalf("expected error for format: %s", tc.format)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("failed getting format flag. error: %v", err)
+			}
+			if actual != tc.expectedFormat {
+				t.Fatalf("expected: %s but got: %s", tc.expectedFormat, actual)
+			}
+		})
+	}
+}
+
+func TestLoadJobManifest(t *testing.T) {
+	manifest, err := loadJobManifest("../testdata/jobs/jobs.yaml", map[string]string{"out": "/tmp/out"})
+	if err != nil {
+		t.Fatalf("failed loading job manifest. error: %v", err)
+	}
+
+	expectedJobs := []jobSpec{
+		{
+			Name:   "iris",
+			From:   "csv",
+			To:     "parquet",
+			Source: "../testdata/csv/iris150.csv",
+			Dest:   "/tmp/out/iris.parquet",
+			Read:   map[string]any{"header": true},
+			Write:  map[string]any{"compression": "zstd"},
+			Transform: map[string]any{
+				"select": []any{"species", "sepal_length"},
+				"where":  "species = 'Iris-setosa'",
+			},
+			DuckDBConfig: []string{"SET threads TO 1"},
+		},
+		{
+			Name:         "iris-json",
+			From:         "csv",
+			To:           "json",
+			Source:       "../testdata/csv/iris150.csv",
+			Dest:         "/tmp/out/iris.json",
+			Read:         map[string]any{"header": true, "delim": ","},
+			Write:        map[string]any{"compression": "zstd", "format": "array"},
+			Transform:    map[string]any{},
+			DuckDBConfig: []string{},
+		},
+	}
+
+	if !reflect.DeepEqual(expectedJobs, manifest.Jobs) {
+		t.Fatalf("expected: %+v but got: %+v", expectedJobs, manifest.Jobs)
+	}
+
+	jobs, err := manifest.jobs()
+	if err != nil {
+		t.Fatalf("failed validating jobs. error: %v", err)
+	}
+	if len(jobs) != 2 || jobs[0].sink.Format() != fileconv.Parquet || jobs[1].sink.Format() != fileconv.Json {
+		t.Fatalf("expected parquet and json jobs but got: %v", jobs)
+	}
+}
+
+func TestJobManifestJobs(t *testing.T) {
+	tests := []struct {
+		name        string
+		jobs        []jobSpec
+		expectedErr string
+	}{
+		{
+			name: "TC1",
+			jobs: []jobSpec{
+				{Name: "j1", From: "csv", To: "parquet", Source: "in.csv", Dest: "out.parquet", Read: map[string]any{"headr": true}},
+				{Name: "j2", From: "xml", To: "parquet", Source: "in.xml", Dest: "out.parquet"},
+				{Name: "j3", From: "csv", To: "parquet", Source: "in.csv", Dest: "out.parquet", Write: map[string]any{"row-group-size": 10}},
+				{Name: "j3", From: "csv", To: "parquet", Source: "in.csv", Dest: "out.parquet"},
+				{Name: "j4", From: "csv", To: "csv", Source: "-", Dest: "out.csv"},
+				{Name: "j5", From: "json", To: "csv", Source: "in.json", Dest: "out.csv", Transform: map[string]any{"limit": "ten"}},
+			},
+			expectedErr: `job j1: invalid read. error: unknown option: headr
+job j2: invalid from. error: invalid format: xml. must be one of (csv, json, parquet)
+job j3: invalid write. error: unknown option: row-group-size
+job j3: duplicate job name
+job j4: stdin and stdout are not supported in jobs
+job j5: invalid transform. error: invalid value for option: limit. error: invalid argument "ten" for "--limit" flag: strconv.ParseInt: parsing "ten": invalid syntax`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			manifest := &jobManifest{Jobs: tc.jobs}
+			_, err := manifest.jobs()
+			if err == nil || err.Error() != tc.expectedErr {
+				t.Fatalf("expected error:\n%s\nbut got:\n%v", tc.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestApplyConfig(t *testing.T) {
+	configDir := t.TempDir()
+	err := os.WriteFile(filepath.Join(configDir, CONFIG_FILE), []byte(`profiles:
+  default:
+    pq-compression: zstd
+    duckdb-config:
+      - SET threads TO 4
+  semicolon:
+    delim: ";"
+    pq-compression: gzip
+    pq-partition-by: [col1, col2]
+  typo:
+    pq-compresion: zstd
+  describe:
+    describe-format: json
+`), 0600)
+	if err != nil {
+		t.Fatalf("failed writing config file. error: %v", err)
+	}
+
+	tests := []struct {
+		name                string
+		args                []string
+		env                 map[string]string
+		expectedCompression string
+		expectedPartitionBy []string
+		expectedDelim       string
+		expectedConfigs     []fileconv.DuckDBConfig
+		expectedDescribe    bool
+		expectedErr         string
+	}{
+		{
+			name:                "TC1",
+			args:                []string{},
+			expectedCompression: "zstd",
+			expectedPartitionBy: []string{},
+			expectedDelim:       ",",
+			expectedConfigs:     []fileconv.DuckDBConfig{"SET threads TO 4"},
+		},
+		{
+			name:                "TC2",
+			args:                []string{"--profile", "semicolon"},
+			expectedCompression: "gzip",
+			expectedPartitionBy: []string{"col1", "col2"},
+			expectedDelim:       ";",
+			expectedConfigs:     []fileconv.DuckDBConfig{},
+		},
+		{
+			name:                "TC3",
+			args:                []string{"--pq-compression", "snappy"},
+			env:                 map[string]string{"FILECONV_PROFILE": "semicolon", "FILECONV_PQ_COMPRESSION": "uncompressed", "FILECONV_DELIM": "|"},
+			expectedCompression: "snappy",
+			expectedPartitionBy: []string{"col1", "col2"},
+			expectedDelim:       "|",
+			expectedConfigs:     []fileconv.DuckDBConfig{},
+		},
+		{
+			name:                "TC6",
+			args:                []string{"--profile", "describe"},
+			expectedCompression: "snappy",
+			expectedPartitionBy: []string{},
+			expectedDelim:       ",",
+			expectedConfigs:     []fileconv.DuckDBConfig{},
+		},
+		{
+			name:                "TC7",
+			args:                []string{},
+			env:                 map[string]string{"FILECONV_DESCRIBE_FORMAT": "yaml"},
+			expectedCompression: "zstd",
+			expectedPartitionBy: []string{},
+			expectedDelim:       ",",
+			expectedConfigs:     []fileconv.DuckDBConfig{"SET threads TO 4"},
+		},
+		{
+			name:                "TC8",
+			args:                []string{"--profile", "describe", "--describe-format", "yaml"},
+			expectedCompression: "snappy",
+			expectedPartitionBy: []string{},
+			expectedDelim:       ",",
+			expectedConfigs:     []fileconv.DuckDBConfig{},
+			expectedDescribe:    true,
+		},
+		{
+			name:        "TC4",
+			args:        []string{"--profile", "missing"},
+			expectedErr: "profile: missing not found in config file: " + filepath.Join(configDir, CONFIG_FILE),
+		},
+		{
+			name:        "TC5",
+			args:        []string{"--profile", "typo"},
+			expectedErr: "invalid profile: typo in config file: " + filepath.Join(configDir, CONFIG_FILE) + ". error: unknown flag: pq-compresion",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			for k, v := range tc.env {
+				t.Setenv(k, v)
+			}
+
+			mockRootCmd := &cobra.Command{}
+			registerGlobalFlags(mockRootCmd)
+			mockCmd := &cobra.Command{Use: "mock"}
+			registerPqWriteFlags(mockCmd)
+			registerCsvReadFlags(mockCmd)
+			mockRootCmd.AddCommand(mockCmd)
+
+			err := mockCmd.ParseFlags(append([]string{"--config-dir", configDir}, tc.args...))
+			if err != nil {
+				t.Fatalf("failed parsing flags. error: %v", err)
+			}
+
+			err = applyConfig(mockCmd)
+			if tc.expectedErr != "" {
+				if err == nil || err.Error() != tc.expectedErr {
+					t.Fatalf("expected error: %s but got: %v", tc.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("failed applying config. error: %v", err)
+			}
+
+			pqWriteFlags, err := getPqWriteFlags(mockCmd.PersistentFlags())
+			if err != nil {
+				t.Fatalf("failed getting parquet write flags. error: %v", err)
+			}
+			csvFlags, err := getCsvReadFlags(mockCmd.Flags())
+			if err != nil {
+				t.Fatalf("failed getting csv read flags. error: %v", err)
+			}
+			configs, err := getDuckDBConfig(mockRootCmd)
+			if err != nil {
+				t.Fatalf("failed getting duckdb configs. error: %v", err)
+			}
+
+			if pqWriteFlags.compression != tc.expectedCompression {
+				t.Fatalf("expected compression: %s but got: %s", tc.expectedCompression, pqWriteFlags.compression)
+			}
+			if !reflect.DeepEqual(pqWriteFlags.partitionBy, tc.expectedPartitionBy) {
+				t.Fatalf("expected partition by: %v but got: %v", tc.expectedPartitionBy, pqWriteFlags.partitionBy)
+			}
+			if csvFlags.delim != tc.expectedDelim {
+				t.Fatalf("expected delim: %s but got: %s", tc.expectedDelim, csvFlags.delim)
+			}
+			if !reflect.DeepEqual(configs, tc.expectedConfigs) {
+				t.Fatalf("expected duckdb configs: %v but got: %v", tc.expectedConfigs, configs)
+			}
+			// a default describe format of the config does not imply describe
+			if describe := getDescribeFlag(mockRootCmd); describe != tc.expectedDescribe {
+				t.Fatalf("expected describe: %t but got: %t", tc.expectedDescribe, describe)
+			}
+		})
+	}
+}
+
+func TestConfigDescribeFormatConversion(t *testing.T) {
+	configDir := t.TempDir()
+	err := os.WriteFile(filepath.Join(configDir, CONFIG_FILE), []byte(`profiles:
+  default:
+    describe-format: json
+`), 0600)
+	if err != nil {
+		t.Fatalf("failed writing config file. error: %v", err)
+	}
+
+	dest := filepath.Join(t.TempDir(), "iris.parquet")
+	err = csv2parquetCmd.ParseFlags([]string{"--config-dir", configDir, "--source", "../testdata/csv/iris150.csv", "--dest", dest, "--header"})
+	if err != nil {
+		t.Fatalf("failed parsing flags. error: %v", err)
+	}
+	err = applyConfig(csv2parquetCmd)
+	if err != nil {
+		t.Fatalf("failed applying config. error: %v", err)
+	}
+
+	err = runCsv2ParquetCmd(csv2parquetCmd)
+	if err != nil {
+		t.Fatalf("failed converting csv to parquet. error: %v", err)
+	}
+	if _, err := os.Stat(dest); err != nil {
+		t.Fatalf("expected the conversion to write the parquet file. error: %v", err)
+	}
+}
+
+func TestFormatProgress(t *testing.T) {
+	tests := []struct {
+		name           string
+		progress       fileconv.Progress
+		expectedOutput string
+	}{
+		{
+			name:           "TC1",
+			progress:       fileconv.Progress{Percentage: 0, RowsProcessed: 0, TotalRowsToProcess: 1000},
+			expectedOutput: "[----------------------------------------]   0.0% 0/1000 rows",
+		},
+		{
+			name:           "TC2",
+			progress:       fileconv.Progress{Percentage: 52.5, RowsProcessed: 525, TotalRowsToProcess: 1000},
+			expectedOutput: "[#####################-------------------]  52.5% 525/1000 rows",
+		},
+		{
+			name:           "TC3",
+			progress:       fileconv.Progress{Percentage: 100, RowsProcessed: 1000, TotalRowsToProcess: 1000},
+			expectedOutput: "[########################################] 100.0% 1000/1000 rows",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			actualOutput := formatProgress(tc.progress)
+			if actualOutput != tc.expectedOutput {
+				t.Fatalf("expected: %s but got: %s", tc.expectedOutput, actualOutput)
+			}
+		})
+	}
+}
+
+func TestGetProfileFlags(t *testing.T) {
+	tests := []struct {
+		name          string
+		setFlags      func(cmd *cobra.Command)
+		expectedFlags *profileFlags
+		expectedErr   string
+	}{
+		{
+			name:     "TC1",
+			setFlags: func(cmd *cobra.Command) {},
+			expectedFlags: &profileFlags{
+				topK:   5,
+				format: "table",
+			},
+		},
+		{
+			name: "TC2",
+			setFlags: func(cmd *cobra.Command) {
+				cmd.Flags().Set("top-k", "10")
+				cmd.Flags().Set("format", "markdown")
+			},
+			expectedFlags: &profileFlags{
+				topK:   10,
+				format: "markdown",
+			},
+		},
+		{
+			name: "TC3",
+			setFlags: func(cmd *cobra.Command) {
+				cmd.Flags().Set("format", "html")
+			},
+			expectedErr: "invalid format: html. must be one of (table, json, markdown)",
+		},
+	}
+
+	mockCmd := &cobra.Command{}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCmd.ResetFlags()
+			registerProfileFlags(mockCmd)
+
+			tc.setFlags(mockCmd)
+			actual, err := getProfileFlags(mockCmd.Flags())
+			if tc.expectedErr != "" {
+				if err == nil || err.Error() != tc.expectedErr {
+					t.Fatalf("expected error: %s but got: %v", tc.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("failed getting profile flags. error: %v", err)
+			}
+
+			if !reflect.DeepEqual(tc.expectedFlags, actual) {
+				t.Fatalf("expected: %v but got: %v", tc.expectedFlags, actual)
+			}
+		})
+	}
+}
+
+func TestGetDescribeFormatFlag(t *testing.T) {
+	tests := []struct {
+		name             string
+		setFlags         func(cmd *cobra.Command)
+		expectedDescribe bool
+		expectedFormat   string
+		expectedErr      string
+	}{
+		{
+			name:             "TC1",
+			setFlags:         func(cmd *cobra.Command) {},
+			expectedDescribe: false,
+			expectedFormat:   "table",
+		},
+		{
+			name: "TC2",
+			setFlags: func(cmd *cobra.Command) {
+				cmd.PersistentFlags().Set(FILECONV_CLI_DESC_FMT, "sql-ddl")
+			},
+			expectedDescribe: true,
+			expectedFormat:   "sql-ddl",
+		},
+		{
+			name: "TC3",
+			setFlags: func(cmd *cobra.Command) {
+				cmd.PersistentFlags().Set(FILECONV_CLI_DESC_FMT, "xml")
+			},
+			expectedDescribe: true,
+			expectedErr:      "invalid describe format: xml. must be one of (table, json, yaml, sql-ddl, bigquery-schema, json-schema, avro-schema, columns)",
+		},
+	}
+
+	mockCmd := &cobra.Command{}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCmd.ResetFlags()
+			registerGlobalFlags(mockCmd)
+
+			tc.setFlags(mockCmd)
+			if describe := getDescribeFlag(mockCmd); describe != tc.expectedDescribe {
+				t.Fatalf("expected describe: %t but got: %t", tc.expectedDescribe, describe)
+			}
+
+			actual, err := getDescribeFormatFlag(mockCmd)
+			if tc.expectedErr != "" {
+				if err == nil || err.Error() != tc.expectedErr {
+					t.Fatalf("expected error: %s but got: %v", tc.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("failed getting describe format flag. error: %v", err)
+			}
+			if actual != tc.expectedFormat {
+				t.Fatalf("expected: %s but got: %s", tc.expectedFormat, actual)
+			}
+		})
+	}
+}
+
+func TestGetSourceTableName(t *testing.T) {
+	tests := []struct {
+		name         string
+		source       string
+		expectedName string
+	}{
+		{name: "TC1", source: "path/to/iris.csv.gz", expectedName: "iris"},
+		{name: "TC2", source: "path/to/*.parquet", expectedName: "source"},
+		{name: "TC3", source: "-", expectedName: "source"},
+	}
+
+	mockCmd := &cobra.Command{}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCmd.ResetFlags()
+			mockCmd.Flags().String("source", "", "")
+
+			mockCmd.Flags().Set("source", tc.source)
+			if actual := getSourceTableName(mockCmd); actual != tc.expectedName {
+				t.Fatalf("expected: %s but got: %s", tc.expectedName, actual)
+			}
+		})
+	}
+}
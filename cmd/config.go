@@ -0,0 +1,185 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v3"
+)
+
+// CLI configuration file in the config directory.
+// A profile sets the defaults of the flags by their names, e.g.
+//
+//	profiles:
+//	  default:
+//	    duckdb-config:
+//	      - SET threads TO 4
+//	    pq-compression: zstd
+//	  semicolon:
+//	    delim: ";"
+//	    header: true
+type cliConfig struct {
+	Profiles map[string]map[string]any `yaml:"profiles"`
+}
+
+const (
+	FILECONV_CLI_PROFILE string = "profile"
+
+	DFLT_FILECONV_CLI_PROFILE string = "default"
+
+	CONFIG_FILE string = "config.yaml"
+
+	// prefix of the environment variables overriding the flags, e.g. FILECONV_PQ_COMPRESSION
+	ENV_PREFIX string = "FILECONV_"
+
+	// annotation of the flags set from an environment variable or a profile, with the variable or profile name
+	CONFIG_SOURCE_ANNOTATION string = "fileconv_config_source"
+)
+
+// Sets the flags of cmd which are not set on the command line from the FILECONV_* environment variables
+// and then from the selected profile of the config file.
+// The precedence is flag > environment variable > profile > built-in default.
+func applyConfig(cmd *cobra.Command) error {
+	err := applyEnvFlags(cmd.Flags())
+	if err != nil {
+		return err
+	}
+
+	configFile := filepath.Join(getConfigDir(cmd), CONFIG_FILE)
+	config, err := loadConfig(configFile)
+	if err != nil {
+		return err
+	}
+
+	profileName, err := cmd.Root().PersistentFlags().GetString(FILECONV_CLI_PROFILE)
+	if err != nil {
+		return err
+	}
+
+	profile, ok := config.Profiles[profileName]
+	if !ok {
+		if profileName != DFLT_FILECONV_CLI_PROFILE {
+			return fmt.Errorf("profile: %s not found in config file: %s", profileName, configFile)
+		}
+		return nil
+	}
+
+	err = applyProfileFlags(cmd, profile)
+	if err != nil {
+		return fmt.Errorf("invalid profile: %s in config file: %s. error: %w", profileName, configFile, err)
+	}
+	return nil
+}
+
+// Returns the config in the file, or an empty config if the file does not exist
+func loadConfig(path string) (*cliConfig, error) {
+	config := &cliConfig{}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return config, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	err = decoder.Decode(config)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed parsing config file: %s. error: %w", path, err)
+	}
+
+	return config, nil
+}
+
+// Sets the flags which are not set on the command line from their environment variables
+func applyEnvFlags(flags *pflag.FlagSet) error {
+	var err error
+	flags.VisitAll(func(f *pflag.Flag) {
+		if err != nil || f.Changed || f.Name == "help" || f.Name == "version" {
+			return
+		}
+
+		env := envName(f.Name)
+		value, ok := os.LookupEnv(env)
+		if !ok {
+			return
+		}
+
+		if setErr := flags.Set(f.Name, value); setErr != nil {
+			err = fmt.Errorf("invalid value of environment variable: %s. error: %w", env, setErr)
+			return
+		}
+		err = flags.SetAnnotation(f.Name, CONFIG_SOURCE_ANNOTATION, []string{env})
+	})
+	return err
+}
+
+// Sets the flags of cmd which are not set on the command line or by environment variables from the profile.
+// The profile may set the flags of any command, the flags which cmd does not have are ignored.
+func applyProfileFlags(cmd *cobra.Command, profile map[string]any) error {
+	known := cliFlagNames(cmd.Root())
+
+	for _, name := range sortedKeys(profile) {
+		if !known[name] || name == FILECONV_CLI_PROFILE || name == FILECONV_CLI_CONFIG_DIR {
+			return fmt.Errorf("unknown flag: %s", name)
+		}
+
+		flag := cmd.Flags().Lookup(name)
+		if flag == nil || flag.Changed {
+			continue
+		}
+
+		err := setOptionFlag(cmd.Flags(), name, profile[name])
+		if err != nil {
+			return fmt.Errorf("invalid value for flag: %s. error: %w", name, err)
+		}
+		err = cmd.Flags().SetAnnotation(name, CONFIG_SOURCE_ANNOTATION, []string{FILECONV_CLI_PROFILE})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Returns true if the flag is set on the command line or by a job option, and not by an environment variable or a profile.
+// Flags which imply a mode, e.g. describe-format implying describe, only imply it when they are set explicitly,
+// so that the defaults of the config do not change what every command does.
+func isSetExplicitly(flags *pflag.FlagSet, name string) bool {
+	flag := flags.Lookup(name)
+	if flag == nil || !flag.Changed {
+		return false
+	}
+	_, fromConfig := flag.Annotations[CONFIG_SOURCE_ANNOTATION]
+	return !fromConfig
+}
+
+// Returns the names of the flags of cmd and all its sub commands
+func cliFlagNames(cmd *cobra.Command) map[string]bool {
+	names := map[string]bool{}
+	addName := func(f *pflag.Flag) {
+		names[f.Name] = true
+	}
+
+	cmd.Flags().VisitAll(addName)
+	cmd.PersistentFlags().VisitAll(addName)
+	for _, c := range cmd.Commands() {
+		for name := range cliFlagNames(c) {
+			names[name] = true
+		}
+	}
+	return names
+}
+
+// Returns the name of the environment variable of the flag, e.g. FILECONV_PQ_COMPRESSION for pq-compression
+func envName(flag string) string {
+	return ENV_PREFIX + strings.ToUpper(strings.ReplaceAll(flag, "-", "_"))
+}
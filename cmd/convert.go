@@ -0,0 +1,262 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/hbbtekademy/go-fileconv/pkg/fileconv"
+	"github.com/hbbtekademy/go-fileconv/pkg/param/jsonparam"
+	"github.com/hbbtekademy/go-fileconv/pkg/param/transformparam"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+const (
+	IN_CSV_PREFIX  string = "in-csv-"
+	IN_JSON_PREFIX string = "in-json-"
+	IN_PQ_PREFIX   string = "in-pq-"
+)
+
+var convertCmd = &cobra.Command{
+	Use:   "convert",
+	Short: "Convert files from any supported format to any other supported format (csv, json, parquet)",
+	Long: `Convert files from any supported format to any other supported format (csv, json, parquet).
+Read flags of the source format are prefixed with in-csv-, in-json- or in-pq-.
+Write flags of the output format are prefixed with csv-, json- or pq-.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		err := runConvertCmd(cmd)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(convertCmd)
+	registerConvertFlags(convertCmd)
+	registerPqWriteFlags(convertCmd)
+	registerCsvWriteFlags(convertCmd)
+	registerJsonWriteFlags(convertCmd)
+}
+
+func runConvertCmd(cmd *cobra.Command) (err error) {
+	source, err := cmd.Flags().GetString("source")
+	if err != nil {
+		return fmt.Errorf("error: %w. failed getting source flag", err)
+	}
+
+	dest, err := cmd.Flags().GetString("dest")
+	if err != nil {
+		return fmt.Errorf("error: %w. failed getting dest flag", err)
+	}
+
+	from, err := getFormatFlag(cmd.Flags(), "from")
+	if err != nil {
+		return fmt.Errorf("error: %w. failed getting from flag", err)
+	}
+
+	to, err := getFormatFlag(cmd.Flags(), "to")
+	if err != nil {
+		return fmt.Errorf("error: %w. failed getting to flag", err)
+	}
+
+	src, err := getConvertSource(cmd.Flags(), from, to, source)
+	if err != nil {
+		return fmt.Errorf("error: %w. failed getting %s source", err, from)
+	}
+
+	sink, err := getConvertSink(cmd.PersistentFlags(), to, dest)
+	if err != nil {
+		return fmt.Errorf("error: %w. failed getting %s sink", err, to)
+	}
+
+	duckdbConfigs, err := getDuckDBConfig(rootCmd)
+	if err != nil {
+		return fmt.Errorf("error: %w. failed getting duckdb configs", err)
+	}
+
+	ctx, stop := newCmdContext()
+	defer stop()
+
+	dbFile := getDBFile(cmd)
+	defer func() { err = errors.Join(err, deleteDBFile(dbFile)) }()
+
+	client, err := fileconv.New(ctx, dbFile, duckdbConfigs...)
+	if err != nil {
+		return fmt.Errorf("error: %w. failed getting duckdb client", err)
+	}
+	defer client.Close()
+
+	result, err := client.Convert(ctx, src, sink)
+	if err != nil {
+		return fmt.Errorf("error: %w. failed converting %s to %s", err, from, to)
+	}
+	return printResult(cmd, result)
+}
+
+func registerConvertFlags(cmd *cobra.Command) {
+	cmd.Flags().SortFlags = false
+
+	cmd.Flags().String("from", "", "format of the source files (csv, json, parquet).")
+	err := cmd.MarkFlagRequired("from")
+	checkErr("failed setting from flag as required", err)
+
+	cmd.Flags().String("to", "", "format of the output files (csv, json, parquet).")
+	err = cmd.MarkFlagRequired("to")
+	checkErr("failed setting to flag as required", err)
+
+	cmd.Flags().String("source", "", "full path of source file or regex for multiple source files. Use - to read from stdin.")
+	err = cmd.MarkFlagRequired("source")
+	checkErr("failed setting source flag as required", err)
+
+	cmd.Flags().String("dest", "", "filename of output file or directory in which to write hive partitioned parquet files. Use - to write to stdout.\n")
+	err = cmd.MarkFlagRequired("dest")
+	checkErr("failed setting dest flag as required", err)
+
+	registerPrefixedFlags(cmd, IN_CSV_PREFIX, registerCsvReadFlags)
+	registerPrefixedFlags(cmd, IN_JSON_PREFIX, registerJsonReadFlags)
+	registerPrefixedFlags(cmd, IN_PQ_PREFIX, registerPqReadFlags)
+}
+
+// Registers the flags of register on cmd with their names prefixed by prefix.
+// This allows the read flags of all formats to be registered on the same command.
+func registerPrefixedFlags(cmd *cobra.Command, prefix string, register func(*cobra.Command)) {
+	tmpCmd := &cobra.Command{}
+	tmpCmd.Flags().SortFlags = false
+	register(tmpCmd)
+
+	tmpCmd.Flags().VisitAll(func(f *pflag.Flag) {
+		prefixed := *f
+		prefixed.Name = prefix + f.Name
+		prefixed.Shorthand = ""
+		cmd.Flags().AddFlag(&prefixed)
+	})
+}
+
+// Returns a FlagSet with the flags of flags having prefix, with the prefix removed.
+// The returned flags share their values with flags, so the existing getters can be used on it.
+func unprefixedFlags(flags *pflag.FlagSet, prefix string) *pflag.FlagSet {
+	unprefixed := pflag.NewFlagSet(prefix, pflag.ContinueOnError)
+	unprefixed.SortFlags = false
+
+	flags.VisitAll(func(f *pflag.Flag) {
+		if !strings.HasPrefix(f.Name, prefix) {
+			return
+		}
+		flag := *f
+		flag.Name = strings.TrimPrefix(f.Name, prefix)
+		unprefixed.AddFlag(&flag)
+	})
+
+	return unprefixed
+}
+
+func getFormatFlag(flags *pflag.FlagSet, name string) (fileconv.Format, error) {
+	format, err := flags.GetString(name)
+	if err != nil {
+		return "", err
+	}
+
+	return parseFormat(format)
+}
+
+func parseFormat(format string) (fileconv.Format, error) {
+	switch fileconv.Format(format) {
+	case fileconv.Csv, fileconv.Json, fileconv.Parquet:
+		return fileconv.Format(format), nil
+	default:
+		return "", fmt.Errorf("invalid format: %s. must be one of (csv, json, parquet)", format)
+	}
+}
+
+func getConvertSource(flags *pflag.FlagSet, from fileconv.Format, to fileconv.Format, source string) (fileconv.Source, error) {
+	transformFlags, err := getTransformFlags(rootCmd)
+	if err != nil {
+		return nil, fmt.Errorf("error: %w. failed getting transform flags", err)
+	}
+
+	return newFormatSource(unprefixedFlags(flags, readFlagPrefix(from)), from, to, source,
+		getDescribeFlag(rootCmd), transformFlags.transformParams())
+}
+
+// Returns the prefix of the read flags of the format on the convert command
+func readFlagPrefix(format fileconv.Format) string {
+	switch format {
+	case fileconv.Csv:
+		return IN_CSV_PREFIX
+	case fileconv.Json:
+		return IN_JSON_PREFIX
+	default:
+		return IN_PQ_PREFIX
+	}
+}
+
+// Returns the source of the from format with its read params taken from the unprefixed read flags
+func newFormatSource(flags *pflag.FlagSet, from fileconv.Format, to fileconv.Format, source string,
+	describe bool, transform []transformparam.TransformParam) (fileconv.Source, error) {
+	switch from {
+	case fileconv.Csv:
+		csvFlags, err := getCsvReadFlags(flags)
+		if err != nil {
+			return nil, fmt.Errorf("error: %w. failed getting csv read flags", err)
+		}
+		csvFlags.describe = describe
+		csvFlags.transform = transform
+		return newCsvSource(source, csvFlags.readParams()...), nil
+
+	case fileconv.Json:
+		jsonFlags, err := getJsonReadFlags(flags)
+		if err != nil {
+			return nil, fmt.Errorf("error: %w. failed getting json read flags", err)
+		}
+		jsonFlags.describe = describe
+		jsonFlags.transform = transform
+		params := jsonFlags.readParams()
+		// nested json is always flattened when writing csv
+		if to == fileconv.Csv {
+			params = append(params, jsonparam.WithFlatten(true))
+		}
+		return newJsonSource(source, params...), nil
+
+	case fileconv.Parquet:
+		pqFlags, err := getPqReadFlags(flags)
+		if err != nil {
+			return nil, fmt.Errorf("error: %w. failed getting parquet read flags", err)
+		}
+		pqFlags.describe = describe
+		pqFlags.transform = transform
+		return newParquetSource(source, pqFlags.readParams()...), nil
+	}
+
+	return nil, fmt.Errorf("unsupported source format: %s", from)
+}
+
+func getConvertSink(flags *pflag.FlagSet, to fileconv.Format, dest string) (fileconv.Sink, error) {
+	switch to {
+	case fileconv.Csv:
+		csvWriteFlags, err := getCsvWriteFlags(flags)
+		if err != nil {
+			return nil, fmt.Errorf("error: %w. failed getting csv write flags", err)
+		}
+		return newCsvSink(dest, csvWriteFlags.writeParams()), nil
+
+	case fileconv.Json:
+		jsonWriteFlags, err := getJsonWriteFlags(flags)
+		if err != nil {
+			return nil, fmt.Errorf("error: %w. failed getting json write flags", err)
+		}
+		return newJsonSink(dest, jsonWriteFlags.writeParams()), nil
+
+	case fileconv.Parquet:
+		pqWriteFlags, err := getPqWriteFlags(flags)
+		if err != nil {
+			return nil, fmt.Errorf("error: %w. failed getting parquet write flags", err)
+		}
+		return newParquetSink(dest, pqWriteFlags.writeParams()), nil
+	}
+
+	return nil, fmt.Errorf("unsupported output format: %s", to)
+}
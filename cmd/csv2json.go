@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/hbbtekademy/go-fileconv/pkg/fileconv"
+	"github.com/spf13/cobra"
+)
+
+var csv2jsonCmd = &cobra.Command{
+	Use:   "csv2json",
+	Short: "Convert CSV files to JSON files (https://duckdb.org/docs/data/csv/overview#parameters)",
+	Long:  ``,
+	Run: func(cmd *cobra.Command, args []string) {
+		err := runCsv2JsonCmd(cmd)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(csv2jsonCmd)
+	registerCsv2JsonFlags(csv2jsonCmd)
+	registerJsonWriteFlags(csv2jsonCmd)
+}
+
+func runCsv2JsonCmd(cmd *cobra.Command) (err error) {
+	source, err := cmd.Flags().GetString("source")
+	if err != nil {
+		return fmt.Errorf("error: %w. failed getting source flag", err)
+	}
+
+	dest, err := cmd.Flags().GetString("dest")
+	if err != nil {
+		return fmt.Errorf("error: %w. failed getting dest flag", err)
+	}
+
+	jsonWriteFlags, err := getJsonWriteFlags(cmd.PersistentFlags())
+	if err != nil {
+		return fmt.Errorf("error: %w. failed getting json write flags", err)
+	}
+
+	csvFlags, err := getCsvReadFlags(cmd.Flags())
+	if err != nil {
+		return fmt.Errorf("error: %w. failed getting csv read flags", err)
+	}
+	csvFlags.describe = getDescribeFlag(rootCmd)
+
+	transformFlags, err := getTransformFlags(rootCmd)
+	if err != nil {
+		return fmt.Errorf("error: %w. failed getting transform flags", err)
+	}
+	csvFlags.transform = transformFlags.transformParams()
+
+	duckdbConfigs, err := getDuckDBConfig(rootCmd)
+	if err != nil {
+		return fmt.Errorf("error: %w. failed getting duckdb configs", err)
+	}
+
+	ctx, stop := newCmdContext()
+	defer stop()
+
+	dbFile := getDBFile(cmd)
+	defer func() { err = errors.Join(err, deleteDBFile(dbFile)) }()
+
+	client, err := fileconv.New(ctx, dbFile, duckdbConfigs...)
+	if err != nil {
+		return fmt.Errorf("error: %w. failed getting duckdb client", err)
+	}
+	defer client.Close()
+
+	result, err := client.Convert(ctx,
+		newCsvSource(source, csvFlags.readParams()...),
+		newJsonSink(dest, jsonWriteFlags.writeParams()),
+	)
+	if err != nil {
+		return fmt.Errorf("error: %w. failed converting csv to json", err)
+	}
+	return printResult(cmd, result)
+}
+
+func registerCsv2JsonFlags(cmd *cobra.Command) {
+	cmd.Flags().SortFlags = false
+
+	cmd.Flags().String("source", "", "full path of csv file or regex for multiple csv files. Use - to read from stdin.")
+	err := cmd.MarkFlagRequired("source")
+	checkErr("failed setting source flag as required", err)
+
+	cmd.Flags().String("dest", "", "filename of output json file. Use - to write to stdout.\n")
+	err = cmd.MarkFlagRequired("dest")
+	checkErr("failed setting dest flag as required", err)
+
+	registerCsvReadFlags(cmd)
+}
@@ -1,19 +1,19 @@
 package cmd
 
 import (
-	"context"
+	"errors"
 	"fmt"
 	"os"
 
 	"github.com/hbbtekademy/go-fileconv/pkg/fileconv"
 	"github.com/hbbtekademy/go-fileconv/pkg/param"
 	"github.com/hbbtekademy/go-fileconv/pkg/param/csvparam"
-	"github.com/hbbtekademy/go-fileconv/pkg/param/pqparam"
+	"github.com/hbbtekademy/go-fileconv/pkg/param/transformparam"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 )
 
-type csv2ParquetFlags struct {
+type csvReadFlags struct {
 	allVarchar         bool
 	disableQuotedNulls bool
 	disableAutodetect  bool
@@ -42,7 +42,10 @@ type csv2ParquetFlags struct {
 	timestampformat    string
 	types              param.Columns
 	unionByName        bool
+	rejects            string
+	maxRejects         int64
 	describe           bool
+	transform          []transformparam.TransformParam
 }
 
 var csv2parquetCmd = &cobra.Command{
@@ -52,7 +55,7 @@ var csv2parquetCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		err := runCsv2ParquetCmd(cmd)
 		if err != nil {
-			fmt.Println(err)
+			fmt.Fprintln(os.Stderr, err)
 			os.Exit(1)
 		}
 	},
@@ -64,7 +67,7 @@ func init() {
 	registerPqWriteFlags(csv2parquetCmd)
 }
 
-func runCsv2ParquetCmd(cmd *cobra.Command) error {
+func runCsv2ParquetCmd(cmd *cobra.Command) (err error) {
 	source, err := cmd.Flags().GetString("source")
 	if err != nil {
 		return fmt.Errorf("error: %w. failed getting source flag", err)
@@ -86,76 +89,54 @@ func runCsv2ParquetCmd(cmd *cobra.Command) error {
 	}
 	csvFlags.describe = getDescribeFlag(rootCmd)
 
+	transformFlags, err := getTransformFlags(rootCmd)
+	if err != nil {
+		return fmt.Errorf("error: %w. failed getting transform flags", err)
+	}
+	csvFlags.transform = transformFlags.transformParams()
+
 	duckdbConfigs, err := getDuckDBConfig(rootCmd)
 	if err != nil {
 		return fmt.Errorf("error: %w. failed getting duckdb configs", err)
 	}
 
+	ctx, stop := newCmdContext()
+	defer stop()
+
 	dbFile := getDBFile(cmd)
-	defer deleteDBFile(dbFile)
+	defer func() { err = errors.Join(err, deleteDBFile(dbFile)) }()
 
-	client, err := fileconv.New(context.Background(), dbFile, duckdbConfigs...)
+	client, err := fileconv.New(ctx, dbFile, duckdbConfigs...)
 	if err != nil {
 		return fmt.Errorf("error: %w. failed getting duckdb client", err)
 	}
+	defer client.Close()
 
-	err = client.Csv2Parquet(context.Background(), source, dest,
-		pqparam.NewWriteParams(
-			pqparam.WithCompression(pqparam.Compression(pqWriteFlags.compression)),
-			pqparam.WithPerThreadOutput(pqWriteFlags.perThreadOutput),
-			pqparam.WithHivePartitionConfig(
-				pqparam.WithFilenamePattern(pqWriteFlags.filenamePattern),
-				pqparam.WithOverwriteOrIgnore(pqWriteFlags.overwriteOrIgnore),
-				pqparam.WithPartitionBy(pqWriteFlags.partitionBy...),
-			),
-		),
-		csvparam.WithAllVarchar(csvFlags.allVarchar),
-		csvparam.WithAllowQuotedNulls(!csvFlags.disableQuotedNulls),
-		csvparam.WithAutoDetect(!csvFlags.disableAutodetect),
-		csvparam.WithAutoTypeCandidates(csvFlags.autoTypeCandidates),
-		csvparam.WithColumns(csvFlags.columns),
-		csvparam.WithCompression(param.Compression(csvFlags.compression)),
-		csvparam.WithDateformat(csvFlags.dateformat),
-		csvparam.WithDecimalSeparator(csvFlags.decimalSeparator),
-		csvparam.WithDelim(csvFlags.delim),
-		csvparam.WithEscape(csvFlags.escape),
-		csvparam.WithFilename(csvFlags.filename),
-		csvparam.WithForceNotNull(csvFlags.forceNotNull),
-		csvparam.WithHeader(csvFlags.header),
-		csvparam.WithHivePartitioning(csvFlags.hivePartitioning),
-		csvparam.WithIgnoreErrors(csvFlags.ignoreErrors),
-		csvparam.WithMaxLineSize(csvFlags.maxLineSize),
-		csvparam.WithNames(csvFlags.names),
-		csvparam.WithNewLine(csvFlags.newLine),
-		csvparam.WithNormalizeNames(csvFlags.normalizeNames),
-		csvparam.WithNullPadding(csvFlags.nullPadding),
-		csvparam.WithNullStrings(csvFlags.nullStr),
-		csvparam.WithParallel(csvFlags.parallel),
-		csvparam.WithQuote(csvFlags.quote),
-		csvparam.WithSampleSize(csvFlags.sampleSize),
-		csvparam.WithSkip(csvFlags.skip),
-		csvparam.WithTimestampFormat(csvFlags.timestampformat),
-		csvparam.WithTypes(csvFlags.types),
-		csvparam.WithUnionByName(csvFlags.unionByName),
-		csvparam.WithDescribe(csvFlags.describe),
+	result, err := client.Convert(ctx,
+		newCsvSource(source, csvFlags.readParams()...),
+		newParquetSink(dest, pqWriteFlags.writeParams()),
 	)
 	if err != nil {
 		return fmt.Errorf("error: %w. failed converting csv to parquet", err)
 	}
-	return nil
+	return printResult(cmd, result)
 }
 
 func registerCsv2ParquetFlags(cmd *cobra.Command) {
 	cmd.Flags().SortFlags = false
 
-	cmd.Flags().String("source", "", "full path of csv file or regex for multiple csv files.")
+	cmd.Flags().String("source", "", "full path of csv file or regex for multiple csv files. Use - to read from stdin.")
 	err := cmd.MarkFlagRequired("source")
 	checkErr("failed setting source flag as required", err)
 
-	cmd.Flags().String("dest", "", "filename of output parquet file or directory in which to write hive partitioned parquet files.\n")
+	cmd.Flags().String("dest", "", "filename of output parquet file or directory in which to write hive partitioned parquet files. Use - to write to stdout.\n")
 	err = cmd.MarkFlagRequired("dest")
 	checkErr("failed setting dest flag as required", err)
 
+	registerCsvReadFlags(cmd)
+}
+
+func registerCsvReadFlags(cmd *cobra.Command) {
 	cmd.Flags().String("delim", ",", "(Optional) Specifies the character that separates columns within each row (line) of the file.")
 	cmd.Flags().String("quote", `"`, "(Optional) Specifies the quoting string to be used when a data value is quoted.")
 	cmd.Flags().String("new-line", "", "(Optional) Set the new line character(s) in the file. Options are '\\r','\\n', or '\\r\\n'.")
@@ -180,6 +161,9 @@ Using this option implies that auto detection is not used.`)
 	cmd.Flags().StringSlice("names", []string{}, `(Optional) If the file does not contain a header, names will be auto-generated by default. You can provide your own names with the names option.`)
 	cmd.Flags().StringSlice("nullstr", []string{}, `(Optional) Specifies a list of strings that represent a NULL value.`)
 	cmd.Flags().StringSlice("types", []string{}, `(Optional) The types flag can be used to override types of only certain columns by providing a list of name:type mappings (e.g., col1:INTEGER,col2:VARCHAR)`)
+	cmd.Flags().String("schema-file", "", `(Optional) JSON or YAML file with the column names and types in the format printed by --describe-format json or yaml.
+Sets the columns, or the types with --schema-file-types. Cannot be used together with the flag it sets.`)
+	cmd.Flags().Bool("schema-file-types", false, "(Optional) Use the schema file to override the types of only certain columns instead of setting all columns.")
 
 	cmd.Flags().Bool("disable-autodetect", false, "(Optional) Disable auto detection of CSV parameters.")
 	cmd.Flags().Bool("all-varchar", false, "(Optional) Option to skip type detection for CSV parsing and assume all columns to be of type VARCHAR.")
@@ -191,10 +175,13 @@ Using this option implies that auto detection is not used.`)
 	cmd.Flags().Bool("ignore-errors", false, "(Optional) Whether to ignore parse errors (only possible when format is 'newline_delimited').")
 	cmd.Flags().Bool("null-padding", false, "(Optional) If this option is enabled, when a row lacks columns, it will pad the remaining columns on the right with null values.")
 	cmd.Flags().Bool("parallel", false, "(Optional) Whether or not the parallel CSV reader is used.")
-	cmd.Flags().Bool("union-by-name", false, "(Optional) Whether the schema's of multiple CSV files should be unified.\n\n")
+	cmd.Flags().Bool("union-by-name", false, "(Optional) Whether the schema's of multiple CSV files should be unified.")
+
+	cmd.Flags().String("rejects", "", "(Optional) File to which the rows which cannot be read are written with their file name, line number, column and error, instead of failing the conversion. Written as json if the file has a .json extension, otherwise as csv.")
+	cmd.Flags().Int64("max-rejects", -1, "(Optional) Fail the conversion if more than this number of rows are rejected. -1 for no limit.\n\n")
 }
 
-func getCsvReadFlags(flags *pflag.FlagSet) (*csv2ParquetFlags, error) {
+func getCsvReadFlags(flags *pflag.FlagSet) (*csvReadFlags, error) {
 	delim, err := flags.GetString("delim")
 	if err != nil {
 		return nil, err
@@ -307,11 +294,40 @@ func getCsvReadFlags(flags *pflag.FlagSet) (*csv2ParquetFlags, error) {
 	if err != nil {
 		return nil, err
 	}
+	schemaColumns, err := getSchemaFileFlag(flags)
+	if err != nil {
+		return nil, err
+	}
+	schemaFileTypes, err := flags.GetBool("schema-file-types")
+	if err != nil {
+		return nil, err
+	}
+	if len(schemaColumns) > 0 {
+		if schemaFileTypes {
+			if len(types) > 0 {
+				return nil, fmt.Errorf("schema-file and types flags cannot be used together")
+			}
+			types = schemaColumns
+		} else {
+			if len(columns) > 0 {
+				return nil, fmt.Errorf("schema-file and columns flags cannot be used together")
+			}
+			columns = schemaColumns
+		}
+	}
+	rejects, err := flags.GetString("rejects")
+	if err != nil {
+		return nil, err
+	}
+	maxRejects, err := flags.GetInt64("max-rejects")
+	if err != nil {
+		return nil, err
+	}
 	if len(columns) > 0 {
 		disableAutodetect = true
 	}
 
-	return &csv2ParquetFlags{
+	return &csvReadFlags{
 		delim:              delim,
 		quote:              quote,
 		newLine:            newLine,
@@ -340,5 +356,44 @@ func getCsvReadFlags(flags *pflag.FlagSet) (*csv2ParquetFlags, error) {
 		names:              names,
 		nullStr:            nullStr,
 		types:              types,
+		rejects:            rejects,
+		maxRejects:         maxRejects,
 	}, nil
 }
+
+func (f *csvReadFlags) readParams() []csvparam.ReadParam {
+	return []csvparam.ReadParam{
+		csvparam.WithAllVarchar(f.allVarchar),
+		csvparam.WithAllowQuotedNulls(!f.disableQuotedNulls),
+		csvparam.WithAutoDetect(!f.disableAutodetect),
+		csvparam.WithAutoTypeCandidates(f.autoTypeCandidates),
+		csvparam.WithColumns(f.columns),
+		csvparam.WithCompression(param.Compression(f.compression)),
+		csvparam.WithDateformat(f.dateformat),
+		csvparam.WithDecimalSeparator(f.decimalSeparator),
+		csvparam.WithDelim(f.delim),
+		csvparam.WithEscape(f.escape),
+		csvparam.WithFilename(f.filename),
+		csvparam.WithForceNotNull(f.forceNotNull),
+		csvparam.WithHeader(f.header),
+		csvparam.WithHivePartitioning(f.hivePartitioning),
+		csvparam.WithIgnoreErrors(f.ignoreErrors),
+		csvparam.WithMaxLineSize(f.maxLineSize),
+		csvparam.WithNames(f.names),
+		csvparam.WithNewLine(f.newLine),
+		csvparam.WithNormalizeNames(f.normalizeNames),
+		csvparam.WithNullPadding(f.nullPadding),
+		csvparam.WithNullStrings(f.nullStr),
+		csvparam.WithParallel(f.parallel),
+		csvparam.WithQuote(f.quote),
+		csvparam.WithSampleSize(f.sampleSize),
+		csvparam.WithSkip(f.skip),
+		csvparam.WithTimestampFormat(f.timestampformat),
+		csvparam.WithTypes(f.types),
+		csvparam.WithUnionByName(f.unionByName),
+		csvparam.WithRejects(f.rejects),
+		csvparam.WithMaxRejects(f.maxRejects),
+		csvparam.WithDescribe(f.describe),
+		csvparam.WithTransform(f.transform...),
+	}
+}
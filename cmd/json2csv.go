@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/hbbtekademy/go-fileconv/pkg/fileconv"
+	"github.com/hbbtekademy/go-fileconv/pkg/param/jsonparam"
+	"github.com/spf13/cobra"
+)
+
+var json2csvCmd = &cobra.Command{
+	Use:   "json2csv",
+	Short: "Convert JSON files to CSV files. Nested json is flattened (https://duckdb.org/docs/data/json/overview#parameters)",
+	Long:  ``,
+	Run: func(cmd *cobra.Command, args []string) {
+		err := runJson2CsvCmd(cmd)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(json2csvCmd)
+	registerJson2CsvFlags(json2csvCmd)
+	registerCsvWriteFlags(json2csvCmd)
+}
+
+func runJson2CsvCmd(cmd *cobra.Command) (err error) {
+	source, err := cmd.Flags().GetString("source")
+	if err != nil {
+		return fmt.Errorf("error: %w. failed getting source flag", err)
+	}
+
+	dest, err := cmd.Flags().GetString("dest")
+	if err != nil {
+		return fmt.Errorf("error: %w. failed getting dest flag", err)
+	}
+
+	csvWriteFlags, err := getCsvWriteFlags(cmd.PersistentFlags())
+	if err != nil {
+		return fmt.Errorf("error: %w. failed getting csv write flags", err)
+	}
+
+	jsonFlags, err := getJsonReadFlags(cmd.Flags())
+	if err != nil {
+		return fmt.Errorf("error: %w. failed getting json read flags", err)
+	}
+	jsonFlags.describe = getDescribeFlag(rootCmd)
+
+	transformFlags, err := getTransformFlags(rootCmd)
+	if err != nil {
+		return fmt.Errorf("error: %w. failed getting transform flags", err)
+	}
+	jsonFlags.transform = transformFlags.transformParams()
+
+	duckdbConfigs, err := getDuckDBConfig(rootCmd)
+	if err != nil {
+		return fmt.Errorf("error: %w. failed getting duckdb configs", err)
+	}
+
+	ctx, stop := newCmdContext()
+	defer stop()
+
+	dbFile := getDBFile(cmd)
+	defer func() { err = errors.Join(err, deleteDBFile(dbFile)) }()
+
+	client, err := fileconv.New(ctx, dbFile, duckdbConfigs...)
+	if err != nil {
+		return fmt.Errorf("error: %w. failed getting duckdb client", err)
+	}
+	defer client.Close()
+
+	// nested json is always flattened when writing csv
+	result, err := client.Convert(ctx,
+		newJsonSource(source, append(jsonFlags.readParams(), jsonparam.WithFlatten(true))...),
+		newCsvSink(dest, csvWriteFlags.writeParams()),
+	)
+	if err != nil {
+		return fmt.Errorf("error: %w. failed converting json to csv", err)
+	}
+	return printResult(cmd, result)
+}
+
+func registerJson2CsvFlags(cmd *cobra.Command) {
+	cmd.Flags().SortFlags = false
+
+	cmd.Flags().String("source", "", "full path of json file or regex for multiple json files. Use - to read from stdin.")
+	err := cmd.MarkFlagRequired("source")
+	checkErr("failed setting source flag as required", err)
+
+	cmd.Flags().String("dest", "", "filename of output csv file. Use - to write to stdout.\n")
+	err = cmd.MarkFlagRequired("dest")
+	checkErr("failed setting dest flag as required", err)
+
+	registerJsonReadFlags(cmd)
+
+	// nested json is always flattened when writing csv
+	err = cmd.Flags().MarkHidden("flatten")
+	checkErr("failed hiding flatten flag", err)
+}
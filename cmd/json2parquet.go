@@ -1,19 +1,19 @@
 package cmd
 
 import (
-	"context"
+	"errors"
 	"fmt"
 	"os"
 
 	"github.com/hbbtekademy/go-fileconv/pkg/fileconv"
 	"github.com/hbbtekademy/go-fileconv/pkg/param"
 	"github.com/hbbtekademy/go-fileconv/pkg/param/jsonparam"
-	"github.com/hbbtekademy/go-fileconv/pkg/param/pqparam"
+	"github.com/hbbtekademy/go-fileconv/pkg/param/transformparam"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 )
 
-type json2ParquetFlags struct {
+type jsonReadFlags struct {
 	disableAutodetect bool
 	compression       string
 	convStr2Int       bool
@@ -30,7 +30,16 @@ type json2ParquetFlags struct {
 	unionByName       bool
 	columns           param.Columns
 	flatten           bool
+	flattenSep        string
+	flattenMaxDepth   int
+	flattenArrays     string
+	flattenCollision  string
+	recordsPath       string
+	recordsCarry      []string
+	rejects           string
+	maxRejects        int64
 	describe          bool
+	transform         []transformparam.TransformParam
 }
 
 var json2parquetCmd = &cobra.Command{
@@ -40,7 +49,7 @@ var json2parquetCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		err := runJson2ParquetCmd(cmd)
 		if err != nil {
-			fmt.Println(err)
+			fmt.Fprintln(os.Stderr, err)
 			os.Exit(1)
 		}
 	},
@@ -52,7 +61,7 @@ func init() {
 	registerPqWriteFlags(json2parquetCmd)
 }
 
-func runJson2ParquetCmd(cmd *cobra.Command) error {
+func runJson2ParquetCmd(cmd *cobra.Command) (err error) {
 	source, err := cmd.Flags().GetString("source")
 	if err != nil {
 		return fmt.Errorf("error: %w. failed getting source flag", err)
@@ -74,83 +83,83 @@ func runJson2ParquetCmd(cmd *cobra.Command) error {
 	}
 	jsonFlags.describe = getDescribeFlag(rootCmd)
 
+	transformFlags, err := getTransformFlags(rootCmd)
+	if err != nil {
+		return fmt.Errorf("error: %w. failed getting transform flags", err)
+	}
+	jsonFlags.transform = transformFlags.transformParams()
+
 	duckdbConfigs, err := getDuckDBConfig(rootCmd)
 	if err != nil {
 		return fmt.Errorf("error: %w. failed getting duckdb configs", err)
 	}
 
+	ctx, stop := newCmdContext()
+	defer stop()
+
 	dbFile := getDBFile(cmd)
-	defer deleteDBFile(dbFile)
+	defer func() { err = errors.Join(err, deleteDBFile(dbFile)) }()
 
-	client, err := fileconv.New(context.Background(), dbFile, duckdbConfigs...)
+	client, err := fileconv.New(ctx, dbFile, duckdbConfigs...)
 	if err != nil {
 		return fmt.Errorf("error: %w. failed getting duckdb client", err)
 	}
+	defer client.Close()
 
-	err = client.Json2Parquet(context.Background(), source, dest,
-		pqparam.NewWriteParams(
-			pqparam.WithCompression(pqparam.Compression(pqWriteFlags.compression)),
-			pqparam.WithPerThreadOutput(pqWriteFlags.perThreadOutput),
-			pqparam.WithHivePartitionConfig(
-				pqparam.WithFilenamePattern(pqWriteFlags.filenamePattern),
-				pqparam.WithOverwriteOrIgnore(pqWriteFlags.overwriteOrIgnore),
-				pqparam.WithPartitionBy(pqWriteFlags.partitionBy...),
-			),
-		),
-		jsonparam.WithAutoDetect(!jsonFlags.disableAutodetect),
-		jsonparam.WithColumns(jsonFlags.columns),
-		jsonparam.WithCompression(param.Compression(jsonFlags.compression)),
-		jsonparam.WithConvStr2Int(jsonFlags.convStr2Int),
-		jsonparam.WithDateFormat(jsonFlags.dateformat),
-		jsonparam.WithFilename(jsonFlags.filename),
-		jsonparam.WithFormat(jsonparam.Format(jsonFlags.format)),
-		jsonparam.WithHivePartitioning(jsonFlags.hivePartitioning),
-		jsonparam.WithIgnoreErrors(jsonFlags.ignoreErrors),
-		jsonparam.WithMaxDepth(jsonFlags.maxDepth),
-		jsonparam.WithMaxObjSize(jsonFlags.maxObjSize),
-		jsonparam.WithRecords(jsonparam.Records(jsonFlags.records)),
-		jsonparam.WithSampleSize(jsonFlags.sampleSize),
-		jsonparam.WithTimestampFormat(jsonFlags.timestampformat),
-		jsonparam.WithUnionByName(jsonFlags.unionByName),
-		jsonparam.WithFlatten(jsonFlags.flatten),
-		jsonparam.WithDescribe(jsonFlags.describe),
+	result, err := client.Convert(ctx,
+		newJsonSource(source, jsonFlags.readParams()...),
+		newParquetSink(dest, pqWriteFlags.writeParams()),
 	)
 	if err != nil {
 		return fmt.Errorf("error: %w. failed converting json to parquet", err)
 	}
-	return nil
+	return printResult(cmd, result)
 }
 
 func registerJson2ParquetFlags(json2parquetCmd *cobra.Command) {
 	json2parquetCmd.Flags().SortFlags = false
 
-	json2parquetCmd.Flags().String("source", "", "full path of json file or regex for multiple json files.")
+	json2parquetCmd.Flags().String("source", "", "full path of json file or regex for multiple json files. Use - to read from stdin.")
 	err := json2parquetCmd.MarkFlagRequired("source")
 	checkErr("failed setting source flag as required", err)
 
-	json2parquetCmd.Flags().String("dest", "", "filename of output parquet file or directory in which to write hive partitioned parquet files.\n")
+	json2parquetCmd.Flags().String("dest", "", "filename of output parquet file or directory in which to write hive partitioned parquet files. Use - to write to stdout.\n")
 	err = json2parquetCmd.MarkFlagRequired("dest")
 	checkErr("failed setting dest flag as required", err)
 
-	json2parquetCmd.Flags().Bool("disable-autodetect", false, "(Optional) Disable automatically detecting the names of the keys and data types of the values.")
-	json2parquetCmd.Flags().String("compression", "auto", "(Optional) The compression type for the file (auto, gzip, zstd).")
-	json2parquetCmd.Flags().StringSlice("columns", []string{}, `(Optional) A list of key names and value types contained within the JSON file. (e.g., "key1:INTEGER,key2:VARCHAR"). If auto detect is enabled these will be inferred.`)
-	json2parquetCmd.Flags().String("format", "array", "(Optional) Can be one of ('auto', 'unstructured', 'newline_delimited', 'array').")
-	json2parquetCmd.Flags().String("dateformat", "iso", "(Optional) Specifies the date format to use when parsing dates. https://duckdb.org/docs/sql/functions/dateformat")
-	json2parquetCmd.Flags().String("timestampformat", "iso", "(Optional) Specifies the date format to use when parsing timestamps. https://duckdb.org/docs/sql/functions/dateformat")
-	json2parquetCmd.Flags().Int64("max-depth", -1, "(Optional) Maximum nesting depth to which the automatic schema detection detects types.")
-	json2parquetCmd.Flags().Uint64("max-obj-size", 16777216, "(Optional) The maximum size of a JSON object (in bytes).")
-	json2parquetCmd.Flags().String("records", "auto", "(Optional) Can be one of ('auto', 'true', 'false').")
-	json2parquetCmd.Flags().Uint64("sample-size", 20480, "(Optional) Flag to define number of sample objects for automatic JSON type detection. Set to -1 to scan the entire input file.")
-	json2parquetCmd.Flags().Bool("convert-str-to-int", false, "(Optional) Whether strings representing integer values should be converted to a numerical type.")
-	json2parquetCmd.Flags().Bool("filename", false, "(Optional) Whether or not an extra filename column should be included in the result.")
-	json2parquetCmd.Flags().Bool("hive-partitioning", false, "(Optional) Whether or not to interpret the path as a Hive partitioned path.")
-	json2parquetCmd.Flags().Bool("ignore-errors", false, "(Optional) Whether to ignore parse errors (only possible when format is 'newline_delimited').")
-	json2parquetCmd.Flags().Bool("union-by-name", false, "(Optional) Whether the schema's of multiple JSON files should be unified.")
-	json2parquetCmd.Flags().Bool("flatten", false, "(Optional) Flatten nested json\n\n")
+	registerJsonReadFlags(json2parquetCmd)
+}
+
+func registerJsonReadFlags(cmd *cobra.Command) {
+	cmd.Flags().Bool("disable-autodetect", false, "(Optional) Disable automatically detecting the names of the keys and data types of the values.")
+	cmd.Flags().String("compression", "auto", "(Optional) The compression type for the file (auto, gzip, zstd).")
+	cmd.Flags().StringSlice("columns", []string{}, `(Optional) A list of key names and value types contained within the JSON file. (e.g., "key1:INTEGER,key2:VARCHAR"). If auto detect is enabled these will be inferred.`)
+	cmd.Flags().String("schema-file", "", "(Optional) JSON or YAML file with the key names and value types in the format printed by --describe-format json or yaml. Sets the columns flag, so cannot be used together with it.")
+	cmd.Flags().String("jsonschema-file", "", "(Optional) JSON Schema file describing the json records. The columns are derived from the schema instead of being detected from the sampled records. Sets the columns flag, so cannot be used together with it or with schema-file.")
+	cmd.Flags().String("format", "array", "(Optional) Can be one of ('auto', 'unstructured', 'newline_delimited', 'array').")
+	cmd.Flags().String("dateformat", "iso", "(Optional) Specifies the date format to use when parsing dates. https://duckdb.org/docs/sql/functions/dateformat")
+	cmd.Flags().String("timestampformat", "iso", "(Optional) Specifies the date format to use when parsing timestamps. https://duckdb.org/docs/sql/functions/dateformat")
+	cmd.Flags().Int64("max-depth", -1, "(Optional) Maximum nesting depth to which the automatic schema detection detects types.")
+	cmd.Flags().Uint64("max-obj-size", 16777216, "(Optional) The maximum size of a JSON object (in bytes).")
+	cmd.Flags().String("records", "auto", "(Optional) Can be one of ('auto', 'true', 'false').")
+	cmd.Flags().Uint64("sample-size", 20480, "(Optional) Flag to define number of sample objects for automatic JSON type detection. Set to -1 to scan the entire input file.")
+	cmd.Flags().Bool("convert-str-to-int", false, "(Optional) Whether strings representing integer values should be converted to a numerical type.")
+	cmd.Flags().Bool("filename", false, "(Optional) Whether or not an extra filename column should be included in the result.")
+	cmd.Flags().Bool("hive-partitioning", false, "(Optional) Whether or not to interpret the path as a Hive partitioned path.")
+	cmd.Flags().Bool("ignore-errors", false, "(Optional) Whether to ignore parse errors (only possible when format is 'newline_delimited').")
+	cmd.Flags().Bool("union-by-name", false, "(Optional) Whether the schema's of multiple JSON files should be unified.")
+	cmd.Flags().Bool("flatten", false, "(Optional) Flatten nested json")
+	cmd.Flags().String("flatten-sep", "_", "(Optional) Separator joining the names of the flattened nested columns. Implies --flatten.")
+	cmd.Flags().Int("flatten-max-depth", -1, "(Optional) Maximum nesting depth which is flattened. Deeper values are kept nested. -1 for no limit. Implies --flatten.")
+	cmd.Flags().String("flatten-arrays", "keep", "(Optional) How arrays are flattened (keep, explode, spread). explode writes a row per element with a <name>_index column, spread writes the elements to <name>_0 to <name>_n columns. Implies --flatten.")
+	cmd.Flags().String("flatten-collision", "error", "(Optional) How flattened column names clashing with other columns are handled (error, rename). rename appends the separator and a number. Implies --flatten.")
+	cmd.Flags().String("records-path", "", "(Optional) JSONPath-like path of the array of records in the json documents, e.g. $.data.items[*]. Each [*] reads the elements of an array as rows. Sets --format to auto unless it is set. Every document is read as one json value, so --max-obj-size is raised to the uncompressed size of the largest local file.")
+	cmd.Flags().StringSlice("records-carry", []string{}, "(Optional) Paths of the fields of the json documents added as columns to every record of the records path, e.g. meta.generated_at is added as meta_generated_at.")
+	cmd.Flags().String("rejects", "", "(Optional) File to which the malformed lines and the lines with values which cannot be converted to the types of the columns are written with their file name, line number, column and error, instead of failing the conversion (only possible when format is 'newline_delimited'). Written as json if the file has a .json extension, otherwise as csv.")
+	cmd.Flags().Int64("max-rejects", -1, "(Optional) Fail the conversion if more than this number of lines are rejected. -1 for no limit.\n\n")
 }
 
-func getJsonReadFlags(flags *pflag.FlagSet) (*json2ParquetFlags, error) {
+func getJsonReadFlags(flags *pflag.FlagSet) (*jsonReadFlags, error) {
 	disableAutodetect, err := flags.GetBool("disable-autodetect")
 	if err != nil {
 		return nil, err
@@ -211,12 +220,76 @@ func getJsonReadFlags(flags *pflag.FlagSet) (*json2ParquetFlags, error) {
 	if err != nil {
 		return nil, err
 	}
+	schemaColumns, err := getSchemaFileFlag(flags)
+	if err != nil {
+		return nil, err
+	}
+	if len(schemaColumns) > 0 {
+		if len(columns) > 0 {
+			return nil, fmt.Errorf("schema-file and columns flags cannot be used together")
+		}
+		columns = schemaColumns
+	}
+	jsonSchemaColumns, err := getJsonSchemaFileFlag(flags)
+	if err != nil {
+		return nil, err
+	}
+	if len(jsonSchemaColumns) > 0 {
+		if len(columns) > 0 {
+			return nil, fmt.Errorf("jsonschema-file flag cannot be used together with the columns or schema-file flags")
+		}
+		columns = jsonSchemaColumns
+	}
 	flatten, err := flags.GetBool("flatten")
 	if err != nil {
 		return nil, err
 	}
+	flattenSep, err := flags.GetString("flatten-sep")
+	if err != nil {
+		return nil, err
+	}
+	flattenMaxDepth, err := flags.GetInt("flatten-max-depth")
+	if err != nil {
+		return nil, err
+	}
+	flattenArrays, err := flags.GetString("flatten-arrays")
+	if err != nil {
+		return nil, err
+	}
+	flattenCollision, err := flags.GetString("flatten-collision")
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range []string{"flatten-sep", "flatten-max-depth", "flatten-arrays", "flatten-collision"} {
+		if isSetExplicitly(flags, name) {
+			flatten = true
+		}
+	}
+	recordsPath, err := flags.GetString("records-path")
+	if err != nil {
+		return nil, err
+	}
+	recordsCarry, err := flags.GetStringSlice("records-carry")
+	if err != nil {
+		return nil, err
+	}
+	if len(recordsCarry) > 0 && recordsPath == "" {
+		return nil, fmt.Errorf("records-carry flag requires the records-path flag")
+	}
+	// the document holding the records is usually a single object, which is not read with the default array format
+	if recordsPath != "" && !flags.Changed("format") {
+		format = string(jsonparam.AutoFormat)
+	}
+	rejects, err := flags.GetString("rejects")
+	if err != nil {
+		return nil, err
+	}
+	maxRejects, err := flags.GetInt64("max-rejects")
+	if err != nil {
+		return nil, err
+	}
 
-	return &json2ParquetFlags{
+	return &jsonReadFlags{
 		disableAutodetect: disableAutodetect,
 		compression:       compression,
 		convStr2Int:       convStr2Int,
@@ -233,5 +306,59 @@ func getJsonReadFlags(flags *pflag.FlagSet) (*json2ParquetFlags, error) {
 		unionByName:       unionByName,
 		columns:           columns,
 		flatten:           flatten,
+		flattenSep:        flattenSep,
+		flattenMaxDepth:   flattenMaxDepth,
+		flattenArrays:     flattenArrays,
+		flattenCollision:  flattenCollision,
+		recordsPath:       recordsPath,
+		recordsCarry:      recordsCarry,
+		rejects:           rejects,
+		maxRejects:        maxRejects,
 	}, nil
 }
+
+// Returns the columns of the JSON Schema file flag. Returns no columns if the flag is not set.
+func getJsonSchemaFileFlag(flags *pflag.FlagSet) (param.Columns, error) {
+	jsonSchemaFile, err := flags.GetString("jsonschema-file")
+	if err != nil {
+		return nil, err
+	}
+	if jsonSchemaFile == "" {
+		return nil, nil
+	}
+
+	return jsonparam.LoadJsonSchema(jsonSchemaFile)
+}
+
+func (f *jsonReadFlags) readParams() []jsonparam.ReadParam {
+	return []jsonparam.ReadParam{
+		jsonparam.WithAutoDetect(!f.disableAutodetect),
+		jsonparam.WithColumns(f.columns),
+		jsonparam.WithCompression(param.Compression(f.compression)),
+		jsonparam.WithConvStr2Int(f.convStr2Int),
+		jsonparam.WithDateFormat(f.dateformat),
+		jsonparam.WithFilename(f.filename),
+		jsonparam.WithFormat(jsonparam.Format(f.format)),
+		jsonparam.WithHivePartitioning(f.hivePartitioning),
+		jsonparam.WithIgnoreErrors(f.ignoreErrors),
+		jsonparam.WithMaxDepth(f.maxDepth),
+		jsonparam.WithMaxObjSize(f.maxObjSize),
+		jsonparam.WithRecords(jsonparam.Records(f.records)),
+		jsonparam.WithSampleSize(f.sampleSize),
+		jsonparam.WithTimestampFormat(f.timestampformat),
+		jsonparam.WithUnionByName(f.unionByName),
+		jsonparam.WithFlatten(f.flatten),
+		jsonparam.WithFlattenConfig(
+			jsonparam.WithFlattenSeparator(f.flattenSep),
+			jsonparam.WithFlattenMaxDepth(f.flattenMaxDepth),
+			jsonparam.WithArrayMode(jsonparam.ArrayMode(f.flattenArrays)),
+			jsonparam.WithCollision(jsonparam.Collision(f.flattenCollision)),
+		),
+		jsonparam.WithRecordsPath(f.recordsPath),
+		jsonparam.WithRecordsCarry(f.recordsCarry...),
+		jsonparam.WithRejects(f.rejects),
+		jsonparam.WithMaxRejects(f.maxRejects),
+		jsonparam.WithDescribe(f.describe),
+		jsonparam.WithTransform(f.transform...),
+	}
+}
@@ -0,0 +1,162 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/hbbtekademy/go-fileconv/pkg/fileconv"
+	"github.com/hbbtekademy/go-fileconv/pkg/param/pqparam"
+	"github.com/hbbtekademy/go-fileconv/pkg/param/transformparam"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+type pqReadFlags struct {
+	binaryAsString   bool
+	filename         bool
+	fileRowNum       bool
+	hivePartitioning bool
+	unionByName      bool
+	describe         bool
+	transform        []transformparam.TransformParam
+}
+
+var parquet2csvCmd = &cobra.Command{
+	Use:   "parquet2csv",
+	Short: "Convert Apache Parquet files to CSV files (https://duckdb.org/docs/sql/statements/copy#csv-options)",
+	Long:  ``,
+	Run: func(cmd *cobra.Command, args []string) {
+		err := runParquet2CsvCmd(cmd)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(parquet2csvCmd)
+	registerParquet2CsvFlags(parquet2csvCmd)
+	registerCsvWriteFlags(parquet2csvCmd)
+}
+
+func runParquet2CsvCmd(cmd *cobra.Command) (err error) {
+	source, err := cmd.Flags().GetString("source")
+	if err != nil {
+		return fmt.Errorf("error: %w. failed getting source flag", err)
+	}
+
+	dest, err := cmd.Flags().GetString("dest")
+	if err != nil {
+		return fmt.Errorf("error: %w. failed getting dest flag", err)
+	}
+
+	csvWriteFlags, err := getCsvWriteFlags(cmd.PersistentFlags())
+	if err != nil {
+		return fmt.Errorf("error: %w. failed getting csv write flags", err)
+	}
+
+	pqFlags, err := getPqReadFlags(cmd.Flags())
+	if err != nil {
+		return fmt.Errorf("error: %w. failed getting parquet read flags", err)
+	}
+	pqFlags.describe = getDescribeFlag(rootCmd)
+
+	transformFlags, err := getTransformFlags(rootCmd)
+	if err != nil {
+		return fmt.Errorf("error: %w. failed getting transform flags", err)
+	}
+	pqFlags.transform = transformFlags.transformParams()
+
+	duckdbConfigs, err := getDuckDBConfig(rootCmd)
+	if err != nil {
+		return fmt.Errorf("error: %w. failed getting duckdb configs", err)
+	}
+
+	ctx, stop := newCmdContext()
+	defer stop()
+
+	dbFile := getDBFile(cmd)
+	defer func() { err = errors.Join(err, deleteDBFile(dbFile)) }()
+
+	client, err := fileconv.New(ctx, dbFile, duckdbConfigs...)
+	if err != nil {
+		return fmt.Errorf("error: %w. failed getting duckdb client", err)
+	}
+	defer client.Close()
+
+	result, err := client.Convert(ctx,
+		newParquetSource(source, pqFlags.readParams()...),
+		newCsvSink(dest, csvWriteFlags.writeParams()),
+	)
+	if err != nil {
+		return fmt.Errorf("error: %w. failed converting parquet to csv", err)
+	}
+	return printResult(cmd, result)
+}
+
+func registerParquet2CsvFlags(cmd *cobra.Command) {
+	cmd.Flags().SortFlags = false
+
+	cmd.Flags().String("source", "", "full path of parquet file or regex for multiple parquet files. Use - to read from stdin.")
+	err := cmd.MarkFlagRequired("source")
+	checkErr("failed setting source flag as required", err)
+
+	cmd.Flags().String("dest", "", "filename of output csv file. Use - to write to stdout.\n")
+	err = cmd.MarkFlagRequired("dest")
+	checkErr("failed setting dest flag as required", err)
+
+	registerPqReadFlags(cmd)
+}
+
+func registerPqReadFlags(cmd *cobra.Command) {
+	cmd.Flags().Bool("binary-as-string", false, "(Optional) Parquet files generated by legacy writers do not correctly set the UTF8 flag for strings, causing string columns to be loaded as BLOB instead. Set this to true to load binary columns as strings.")
+	cmd.Flags().Bool("filename", false, "(Optional) Whether or not an extra filename column should be included in the result.")
+	cmd.Flags().Bool("file-row-number", false, "(Optional) Whether or not to include the file_row_number column.")
+	cmd.Flags().Bool("hive-partitioning", false, "(Optional) Whether or not to interpret the path as a Hive partitioned path.")
+	cmd.Flags().Bool("union-by-name", false, "(Optional) Whether the columns of multiple schemas should be unified by name, rather than by position.\n\n")
+}
+
+func getPqReadFlags(flags *pflag.FlagSet) (*pqReadFlags, error) {
+	binaryAsString, err := flags.GetBool("binary-as-string")
+	if err != nil {
+		return nil, err
+	}
+	filename, err := flags.GetBool("filename")
+	if err != nil {
+		return nil, err
+	}
+	fileRowNum, err := flags.GetBool("file-row-number")
+	if err != nil {
+		return nil, err
+	}
+	hivePartitioning, err := flags.GetBool("hive-partitioning")
+	if err != nil {
+		return nil, err
+	}
+	unionByName, err := flags.GetBool("union-by-name")
+	if err != nil {
+		return nil, err
+	}
+
+	return &pqReadFlags{
+		binaryAsString:   binaryAsString,
+		filename:         filename,
+		fileRowNum:       fileRowNum,
+		hivePartitioning: hivePartitioning,
+		unionByName:      unionByName,
+	}, nil
+}
+
+func (f *pqReadFlags) readParams() []pqparam.ReadParam {
+	return []pqparam.ReadParam{
+		pqparam.WithBinaryAsString(f.binaryAsString),
+		pqparam.WithFilename(f.filename),
+		pqparam.WithFileRowNum(f.fileRowNum),
+		pqparam.WithHivePartition(f.hivePartitioning),
+		pqparam.WithUnionByName(f.unionByName),
+		pqparam.WithDescribe(f.describe),
+		pqparam.WithTransform(f.transform...),
+	}
+}
@@ -0,0 +1,163 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/hbbtekademy/go-fileconv/pkg/fileconv"
+	"github.com/hbbtekademy/go-fileconv/pkg/model"
+	"github.com/hbbtekademy/go-fileconv/pkg/param/profileparam"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+type profileFlags struct {
+	topK   int
+	format string
+}
+
+const (
+	PROFILE_FORMAT_TABLE    string = "table"
+	PROFILE_FORMAT_JSON     string = "json"
+	PROFILE_FORMAT_MARKDOWN string = "markdown"
+)
+
+var profileCmd = &cobra.Command{
+	Use:   "profile",
+	Short: "Profile the columns of csv, json or parquet files",
+	Long: `Profile the columns of csv, json or parquet files.
+For each column the null count, approximate distinct count, min and max, mean and standard deviation of numeric columns,
+most frequent values and length distribution of string columns are printed.
+Read flags of the source format are prefixed with in-csv-, in-json- or in-pq-.
+The select, exclude, where, limit and mapping flags are applied to the rows before they are profiled.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		err := runProfileCmd(cmd)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(profileCmd)
+	registerProfileFlags(profileCmd)
+}
+
+func runProfileCmd(cmd *cobra.Command) (err error) {
+	source, err := cmd.Flags().GetString("source")
+	if err != nil {
+		return fmt.Errorf("error: %w. failed getting source flag", err)
+	}
+
+	from, err := getFormatFlag(cmd.Flags(), "from")
+	if err != nil {
+		return fmt.Errorf("error: %w. failed getting from flag", err)
+	}
+
+	flags, err := getProfileFlags(cmd.Flags())
+	if err != nil {
+		return fmt.Errorf("error: %w. failed getting profile flags", err)
+	}
+
+	transformFlags, err := getTransformFlags(rootCmd)
+	if err != nil {
+		return fmt.Errorf("error: %w. failed getting transform flags", err)
+	}
+
+	src, err := newFormatSource(unprefixedFlags(cmd.Flags(), readFlagPrefix(from)), from, from, source,
+		false, transformFlags.transformParams())
+	if err != nil {
+		return fmt.Errorf("error: %w. failed getting %s source", err, from)
+	}
+
+	duckdbConfigs, err := getDuckDBConfig(rootCmd)
+	if err != nil {
+		return fmt.Errorf("error: %w. failed getting duckdb configs", err)
+	}
+
+	ctx, stop := newCmdContext()
+	defer stop()
+
+	dbFile := getDBFile(cmd)
+	defer func() { err = errors.Join(err, deleteDBFile(dbFile)) }()
+
+	client, err := fileconv.New(ctx, dbFile, duckdbConfigs...)
+	if err != nil {
+		return fmt.Errorf("error: %w. failed getting duckdb client", err)
+	}
+	defer client.Close()
+
+	profile, err := client.Profile(ctx, src, profileparam.WithTopK(flags.topK))
+	if err != nil {
+		return fmt.Errorf("error: %w. failed profiling %s", err, from)
+	}
+
+	output, err := formatProfile(profile, flags.format)
+	if err != nil {
+		return fmt.Errorf("error: %w. failed formatting profile", err)
+	}
+	fmt.Print(output)
+	return nil
+}
+
+func registerProfileFlags(cmd *cobra.Command) {
+	cmd.Flags().SortFlags = false
+
+	cmd.Flags().String("from", "", "format of the source files (csv, json, parquet).")
+	err := cmd.MarkFlagRequired("from")
+	checkErr("failed setting from flag as required", err)
+
+	cmd.Flags().String("source", "", "full path of source file or regex for multiple source files. Use - to read from stdin.")
+	err = cmd.MarkFlagRequired("source")
+	checkErr("failed setting source flag as required", err)
+
+	cmd.Flags().Int("top-k", 5, "(Optional) Number of most frequent values printed for each column. No values are printed if 0.")
+	cmd.Flags().String("format", PROFILE_FORMAT_TABLE, "(Optional) Output format of the profile (table, json, markdown).")
+
+	registerPrefixedFlags(cmd, IN_CSV_PREFIX, registerCsvReadFlags)
+	registerPrefixedFlags(cmd, IN_JSON_PREFIX, registerJsonReadFlags)
+	registerPrefixedFlags(cmd, IN_PQ_PREFIX, registerPqReadFlags)
+}
+
+func getProfileFlags(flags *pflag.FlagSet) (*profileFlags, error) {
+	topK, err := flags.GetInt("top-k")
+	if err != nil {
+		return nil, err
+	}
+	if topK < 0 {
+		return nil, fmt.Errorf("invalid top-k: %d. top-k must not be negative", topK)
+	}
+
+	format, err := flags.GetString("format")
+	if err != nil {
+		return nil, err
+	}
+	switch format {
+	case PROFILE_FORMAT_TABLE, PROFILE_FORMAT_JSON, PROFILE_FORMAT_MARKDOWN:
+	default:
+		return nil, fmt.Errorf("invalid format: %s. must be one of (table, json, markdown)", format)
+	}
+
+	return &profileFlags{
+		topK:   topK,
+		format: format,
+	}, nil
+}
+
+func formatProfile(profile *model.TableProfile, format string) (string, error) {
+	switch format {
+	case PROFILE_FORMAT_JSON:
+		b, err := json.MarshalIndent(profile, "", "  ")
+		if err != nil {
+			return "", err
+		}
+		return string(b) + "\n", nil
+	case PROFILE_FORMAT_MARKDOWN:
+		return profile.Markdown(), nil
+	default:
+		return profile.String(), nil
+	}
+}
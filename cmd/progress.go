@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+
+	"github.com/hbbtekademy/go-fileconv/pkg/fileconv"
+	"github.com/spf13/cobra"
+)
+
+const PROGRESS_BAR_WIDTH int = 40
+
+// Progress bar of a conversion written on a single line
+type progressBar struct {
+	w     io.Writer
+	mu    sync.Mutex
+	drawn bool
+}
+
+// Returns the context of a conversion command and a func releasing it.
+// The context is cancelled on SIGINT or SIGTERM, which interrupts the running conversion.
+// If the progress flag is set, the progress of the conversion is drawn on stderr.
+func newCmdContext() (context.Context, func()) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	if !getProgressFlag(rootCmd) {
+		return ctx, stop
+	}
+
+	bar := &progressBar{w: os.Stderr}
+	return fileconv.WithProgress(ctx, bar.update), func() {
+		bar.finish()
+		stop()
+	}
+}
+
+func getProgressFlag(cmd *cobra.Command) bool {
+	progress, err := cmd.PersistentFlags().GetBool(FILECONV_CLI_PROGRESS)
+	if err != nil {
+		return false
+	}
+
+	return progress
+}
+
+func (b *progressBar) update(p fileconv.Progress) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	fmt.Fprintf(b.w, "\r%s", formatProgress(p))
+	b.drawn = true
+}
+
+// Ends the line of the progress bar
+func (b *progressBar) finish() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.drawn {
+		fmt.Fprintln(b.w)
+		b.drawn = false
+	}
+}
+
+// Returns the progress as a bar, e.g. [##########----------] 50.0% 500/1000 rows
+func formatProgress(p fileconv.Progress) string {
+	done := int(p.Percentage / 100 * float64(PROGRESS_BAR_WIDTH))
+	done = max(0, min(done, PROGRESS_BAR_WIDTH))
+
+	return fmt.Sprintf("[%s%s] %5.1f%% %d/%d rows",
+		strings.Repeat("#", done), strings.Repeat("-", PROGRESS_BAR_WIDTH-done),
+		p.Percentage, p.RowsProcessed, p.TotalRowsToProcess)
+}
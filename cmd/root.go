@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
@@ -9,17 +10,58 @@ import (
 	"time"
 
 	"github.com/hbbtekademy/go-fileconv/pkg/fileconv"
+	"github.com/hbbtekademy/go-fileconv/pkg/model"
 	"github.com/hbbtekademy/go-fileconv/pkg/param"
+	"github.com/hbbtekademy/go-fileconv/pkg/param/csvparam"
+	"github.com/hbbtekademy/go-fileconv/pkg/param/jsonparam"
+	"github.com/hbbtekademy/go-fileconv/pkg/param/pqparam"
+	"github.com/hbbtekademy/go-fileconv/pkg/param/transformparam"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 )
 
+type transformFlags struct {
+	selectCols  []string
+	exclude     []string
+	where       string
+	limit       int64
+	mapping     *transformparam.Mapping
+	rules       *transformparam.Rules
+	rulesReport string
+}
+
 type pqWriteFlags struct {
 	compression       string
 	partitionBy       []string
 	filenamePattern   string
 	overwriteOrIgnore bool
 	perThreadOutput   bool
+	keepPartialOutput bool
+}
+
+type csvWriteFlags struct {
+	delim             string
+	quote             string
+	escape            string
+	disableHeader     bool
+	nullStr           string
+	dateformat        string
+	timestampformat   string
+	forceQuote        []string
+	compression       string
+	keepPartialOutput bool
+}
+
+type jsonWriteFlags struct {
+	format            string
+	compression       string
+	dateformat        string
+	timestampformat   string
+	nestedAsString    bool
+	unflatten         bool
+	unflattenSep      string
+	unflattenGroups   []string
+	keepPartialOutput bool
 }
 
 const (
@@ -28,14 +70,60 @@ const (
 	PQ_FILENAME_PATTERN    string = "pq-filename-pattern"
 	PQ_OVERWRITE_OR_IGNORE string = "pq-overwrite-or-ignore"
 	PQ_PER_THREAD_OUTPUT   string = "pq-per-thread-output"
+	PQ_KEEP_PARTIAL_OUTPUT string = "pq-keep-partial-output"
+
+	CSV_DELIM               string = "csv-delim"
+	CSV_QUOTE               string = "csv-quote"
+	CSV_ESCAPE              string = "csv-escape"
+	CSV_DISABLE_HEADER      string = "csv-disable-header"
+	CSV_NULLSTR             string = "csv-nullstr"
+	CSV_DATEFORMAT          string = "csv-dateformat"
+	CSV_TIMESTAMPFORMAT     string = "csv-timestampformat"
+	CSV_FORCE_QUOTE         string = "csv-force-quote"
+	CSV_COMPRESSION         string = "csv-compression"
+	CSV_KEEP_PARTIAL_OUTPUT string = "csv-keep-partial-output"
+
+	JSON_FORMAT              string = "json-format"
+	JSON_COMPRESSION         string = "json-compression"
+	JSON_DATEFORMAT          string = "json-dateformat"
+	JSON_TIMESTAMPFORMAT     string = "json-timestampformat"
+	JSON_NESTED_AS_STRING    string = "json-nested-as-string"
+	JSON_UNFLATTEN           string = "json-unflatten"
+	JSON_UNFLATTEN_SEP       string = "json-unflatten-sep"
+	JSON_UNFLATTEN_GROUPS    string = "json-unflatten-groups"
+	JSON_KEEP_PARTIAL_OUTPUT string = "json-keep-partial-output"
 
 	FILECONV_CLI_CONFIG_DIR string = "config-dir"
 	FILECONV_CLI_DESC       string = "describe"
+	FILECONV_CLI_DESC_FMT   string = "describe-format"
+	FILECONV_CLI_PROGRESS   string = "progress"
+
+	TRANSFORM_SELECT  string = "select"
+	TRANSFORM_EXCLUDE string = "exclude"
+	TRANSFORM_WHERE   string = "where"
+	TRANSFORM_LIMIT   string = "limit"
+	TRANSFORM_MAPPING string = "mapping"
+	TRANSFORM_RULES   string = "rules"
+	TRANSFORM_REPORT  string = "rules-report"
 
 	DFLT_FILECONV_CLI_CONFIG_DIR string = "$HOME/.fileconv-cli"
 	DFLT_FILECONV_CLI_DESC       bool   = false
+	DFLT_FILECONV_CLI_DESC_FMT   string = DESC_FORMAT_TABLE
+	DFLT_FILECONV_CLI_PROGRESS   bool   = false
+
+	DESC_FORMAT_TABLE    string = "table"
+	DESC_FORMAT_JSON     string = "json"
+	DESC_FORMAT_YAML     string = "yaml"
+	DESC_FORMAT_SQL_DDL  string = "sql-ddl"
+	DESC_FORMAT_BIGQUERY string = "bigquery-schema"
+	DESC_FORMAT_JSON_SCH string = "json-schema"
+	DESC_FORMAT_AVRO     string = "avro-schema"
+	DESC_FORMAT_COLUMNS  string = "columns"
 
 	DUCKDB_CONFIG string = "duckdb-config"
+
+	// source or dest value for reading from stdin or writing to stdout
+	STDIO string = "-"
 )
 
 var Version = "development"
@@ -46,6 +134,10 @@ var rootCmd = &cobra.Command{
 	Long:    `Convert file between different formats like JSON, CSV and Apache Parquet`,
 	Version: getVersion(),
 	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		err := applyConfig(cmd)
+		if err != nil {
+			return fmt.Errorf("error: %w. failed applying config", err)
+		}
 		return createConfigDir(cmd)
 	},
 }
@@ -66,20 +158,34 @@ func registerGlobalFlags(rootCmd *cobra.Command) {
 	rootCmd.Flags().SortFlags = false
 	rootCmd.PersistentFlags().SortFlags = false
 	rootCmd.PersistentFlags().Bool(FILECONV_CLI_DESC, DFLT_FILECONV_CLI_DESC, "(Optional) Describe the file columns")
+	rootCmd.PersistentFlags().String(FILECONV_CLI_DESC_FMT, DFLT_FILECONV_CLI_DESC_FMT, "(Optional) Output format of the described columns (table, json, yaml, sql-ddl, bigquery-schema, json-schema, avro-schema, columns). Implies --describe.")
+	rootCmd.PersistentFlags().Bool(FILECONV_CLI_PROGRESS, DFLT_FILECONV_CLI_PROGRESS, "(Optional) Show the progress of the conversion on stderr")
+	registerTransformFlags(rootCmd)
 	rootCmd.PersistentFlags().String(FILECONV_CLI_CONFIG_DIR, DFLT_FILECONV_CLI_CONFIG_DIR, "(Optional) Config Directory for the CLI")
+	rootCmd.PersistentFlags().String(FILECONV_CLI_PROFILE, DFLT_FILECONV_CLI_PROFILE, "(Optional) Profile of the config.yaml file in the config directory setting the defaults of the flags")
 	rootCmd.PersistentFlags().StringSlice(DUCKDB_CONFIG, []string{}, `(Optional) List of DuckDB configuration parameters. e.g.
 --duckdb-config "SET threads TO 1"
 --duckdb-config "SET memory_limit = '10GB'"
 Refer https://duckdb.org/docs/configuration/overview.html for list of all the configurations`)
 }
 
+func registerTransformFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringSlice(TRANSFORM_SELECT, []string{}, "(Optional) Columns to write, in the given order. All columns are written by default.")
+	cmd.PersistentFlags().StringSlice(TRANSFORM_EXCLUDE, []string{}, "(Optional) Columns not to write.")
+	cmd.PersistentFlags().String(TRANSFORM_WHERE, "", "(Optional) SQL expression filtering the rows to write. e.g. --where \"species = 'setosa' AND petal_length > 1.5\"")
+	cmd.PersistentFlags().Int64(TRANSFORM_LIMIT, 0, "(Optional) Maximum number of rows to write. All rows are written if 0.")
+	cmd.PersistentFlags().String(TRANSFORM_MAPPING, "", "(Optional) YAML or JSON file mapping the source columns to the output columns (rename, cast, default, computed columns).")
+	cmd.PersistentFlags().String(TRANSFORM_RULES, "", "(Optional) YAML or JSON file of data quality rules checked on the rows to write (not_null, unique, allowed_values, regex, range, row_count). No rows are written if a rule with severity error fails.")
+	cmd.PersistentFlags().String(TRANSFORM_REPORT, "", "(Optional) Path of the json report of the data quality rules.")
+}
+
 func registerPqWriteFlags(cmd *cobra.Command) {
 	cmd.PersistentFlags().String(PQ_COMPRESSION, "snappy", "(Optional) The compression type for the output parquet file.")
 	cmd.PersistentFlags().StringSlice(PQ_PARTITION_BY, []string{}, "(Optional) Write to a Hive partitioned data set of Parquet files.")
 	cmd.PersistentFlags().Bool(PQ_OVERWRITE_OR_IGNORE, false, "(Optional) Use this flag to allow overwriting an existing directory.")
 	cmd.PersistentFlags().String(PQ_FILENAME_PATTERN, "data_{i}.parquet", "(Optional) With this flag a pattern with {i} or {uuid} can be defined to create specific partition filenames.")
-	cmd.PersistentFlags().Bool(PQ_PER_THREAD_OUTPUT, false, "(Optional) If the final number of Parquet files is not important, writing one file per thread can significantly improve performance.\n\n")
-
+	cmd.PersistentFlags().Bool(PQ_PER_THREAD_OUTPUT, false, "(Optional) If the final number of Parquet files is not important, writing one file per thread can significantly improve performance.")
+	cmd.PersistentFlags().Bool(PQ_KEEP_PARTIAL_OUTPUT, false, "(Optional) Keep the partial output of a failed conversion in the temp directory next to the destination for debugging.\n\n")
 }
 
 func getPqWriteFlags(flags *pflag.FlagSet) (*pqWriteFlags, error) {
@@ -103,18 +209,202 @@ func getPqWriteFlags(flags *pflag.FlagSet) (*pqWriteFlags, error) {
 	if err != nil {
 		return nil, err
 	}
+	keepPartialOutput, err := flags.GetBool(PQ_KEEP_PARTIAL_OUTPUT)
+	if err != nil {
+		return nil, err
+	}
 	return &pqWriteFlags{
 		compression:       compression,
 		partitionBy:       partitionBy,
 		filenamePattern:   filenamePattern,
 		overwriteOrIgnore: overwriteOrIgnore,
 		perThreadOutput:   perThreadOutput,
+		keepPartialOutput: keepPartialOutput,
 	}, nil
 }
 
+func (f *pqWriteFlags) writeParams() *pqparam.WriteParams {
+	return pqparam.NewWriteParams(
+		pqparam.WithCompression(pqparam.Compression(f.compression)),
+		pqparam.WithPerThreadOutput(f.perThreadOutput),
+		pqparam.WithHivePartitionConfig(
+			pqparam.WithFilenamePattern(f.filenamePattern),
+			pqparam.WithOverwriteOrIgnore(f.overwriteOrIgnore),
+			pqparam.WithPartitionBy(f.partitionBy...),
+		),
+		pqparam.WithKeepPartialOutput(f.keepPartialOutput),
+	)
+}
+
+func registerCsvWriteFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().SortFlags = false
+	cmd.PersistentFlags().String(CSV_DELIM, ",", "(Optional) The character that is written to separate columns within each row.")
+	cmd.PersistentFlags().String(CSV_QUOTE, `"`, "(Optional) The quoting character to be used when a data value is quoted.")
+	cmd.PersistentFlags().String(CSV_ESCAPE, `"`, "(Optional) The character that should appear before a character that matches the quote value.")
+	cmd.PersistentFlags().Bool(CSV_DISABLE_HEADER, false, "(Optional) Do not write a header line with the column names.")
+	cmd.PersistentFlags().String(CSV_NULLSTR, "", "(Optional) The string that is written to represent a NULL value.")
+	cmd.PersistentFlags().String(CSV_DATEFORMAT, "", "(Optional) Specifies the date format to use when writing dates. https://duckdb.org/docs/sql/functions/dateformat")
+	cmd.PersistentFlags().String(CSV_TIMESTAMPFORMAT, "", "(Optional) Specifies the date format to use when writing timestamps. https://duckdb.org/docs/sql/functions/dateformat")
+	cmd.PersistentFlags().String(CSV_COMPRESSION, "auto", "(Optional) The compression type for the output csv file (auto, none, gzip, zstd).")
+	cmd.PersistentFlags().StringSlice(CSV_FORCE_QUOTE, []string{}, "(Optional) The list of columns to always add quotes to, even if not required.")
+	cmd.PersistentFlags().Bool(CSV_KEEP_PARTIAL_OUTPUT, false, "(Optional) Keep the partial output of a failed conversion in the temp directory next to the destination for debugging.\n\n")
+}
+
+func getCsvWriteFlags(flags *pflag.FlagSet) (*csvWriteFlags, error) {
+	delim, err := flags.GetString(CSV_DELIM)
+	if err != nil {
+		return nil, err
+	}
+	quote, err := flags.GetString(CSV_QUOTE)
+	if err != nil {
+		return nil, err
+	}
+	escape, err := flags.GetString(CSV_ESCAPE)
+	if err != nil {
+		return nil, err
+	}
+	disableHeader, err := flags.GetBool(CSV_DISABLE_HEADER)
+	if err != nil {
+		return nil, err
+	}
+	nullStr, err := flags.GetString(CSV_NULLSTR)
+	if err != nil {
+		return nil, err
+	}
+	dateformat, err := flags.GetString(CSV_DATEFORMAT)
+	if err != nil {
+		return nil, err
+	}
+	timestampformat, err := flags.GetString(CSV_TIMESTAMPFORMAT)
+	if err != nil {
+		return nil, err
+	}
+	forceQuote, err := flags.GetStringSlice(CSV_FORCE_QUOTE)
+	if err != nil {
+		return nil, err
+	}
+	compression, err := flags.GetString(CSV_COMPRESSION)
+	if err != nil {
+		return nil, err
+	}
+	keepPartialOutput, err := flags.GetBool(CSV_KEEP_PARTIAL_OUTPUT)
+	if err != nil {
+		return nil, err
+	}
+	return &csvWriteFlags{
+		delim:             delim,
+		quote:             quote,
+		escape:            escape,
+		disableHeader:     disableHeader,
+		nullStr:           nullStr,
+		dateformat:        dateformat,
+		timestampformat:   timestampformat,
+		forceQuote:        forceQuote,
+		compression:       compression,
+		keepPartialOutput: keepPartialOutput,
+	}, nil
+}
+
+func (f *csvWriteFlags) writeParams() *csvparam.WriteParams {
+	return csvparam.NewWriteParams(
+		csvparam.WithWriteDelim(f.delim),
+		csvparam.WithWriteQuote(f.quote),
+		csvparam.WithWriteEscape(f.escape),
+		csvparam.WithWriteHeader(!f.disableHeader),
+		csvparam.WithWriteNullStr(f.nullStr),
+		csvparam.WithWriteDateformat(f.dateformat),
+		csvparam.WithWriteTimestampformat(f.timestampformat),
+		csvparam.WithForceQuote(f.forceQuote...),
+		csvparam.WithWriteCompression(param.Compression(f.compression)),
+		csvparam.WithKeepPartialOutput(f.keepPartialOutput),
+	)
+}
+
+func registerJsonWriteFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().SortFlags = false
+	cmd.PersistentFlags().String(JSON_FORMAT, "newline_delimited", "(Optional) Can be one of ('newline_delimited', 'array').")
+	cmd.PersistentFlags().String(JSON_COMPRESSION, "auto", "(Optional) The compression type for the output json file (auto, none, gzip, zstd).")
+	cmd.PersistentFlags().String(JSON_DATEFORMAT, "", "(Optional) Specifies the date format to use when writing dates. https://duckdb.org/docs/sql/functions/dateformat")
+	cmd.PersistentFlags().String(JSON_TIMESTAMPFORMAT, "", "(Optional) Specifies the date format to use when writing timestamps. https://duckdb.org/docs/sql/functions/dateformat")
+	cmd.PersistentFlags().Bool(JSON_NESTED_AS_STRING, false, "(Optional) Write nested STRUCT, LIST and MAP columns as json encoded strings instead of nested objects.")
+	cmd.PersistentFlags().Bool(JSON_UNFLATTEN, false, "(Optional) Nest flattened columns into objects, e.g. address_city and address_zip are written to the city and zip keys of an address object.")
+	cmd.PersistentFlags().String(JSON_UNFLATTEN_SEP, "_", "(Optional) Separator joining the names of the flattened columns. Implies --json-unflatten.")
+	cmd.PersistentFlags().StringSlice(JSON_UNFLATTEN_GROUPS, []string{}, "(Optional) Flattened names of the objects to nest, e.g. address,address_geo. Only the columns of the groups are nested. By default the prefixes shared by two or more columns are nested. Implies --json-unflatten.")
+	cmd.PersistentFlags().Bool(JSON_KEEP_PARTIAL_OUTPUT, false, "(Optional) Keep the partial output of a failed conversion in the temp directory next to the destination for debugging.\n\n")
+}
+
+func getJsonWriteFlags(flags *pflag.FlagSet) (*jsonWriteFlags, error) {
+	format, err := flags.GetString(JSON_FORMAT)
+	if err != nil {
+		return nil, err
+	}
+	compression, err := flags.GetString(JSON_COMPRESSION)
+	if err != nil {
+		return nil, err
+	}
+	dateformat, err := flags.GetString(JSON_DATEFORMAT)
+	if err != nil {
+		return nil, err
+	}
+	timestampformat, err := flags.GetString(JSON_TIMESTAMPFORMAT)
+	if err != nil {
+		return nil, err
+	}
+	nestedAsString, err := flags.GetBool(JSON_NESTED_AS_STRING)
+	if err != nil {
+		return nil, err
+	}
+	unflatten, err := flags.GetBool(JSON_UNFLATTEN)
+	if err != nil {
+		return nil, err
+	}
+	unflattenSep, err := flags.GetString(JSON_UNFLATTEN_SEP)
+	if err != nil {
+		return nil, err
+	}
+	unflattenGroups, err := flags.GetStringSlice(JSON_UNFLATTEN_GROUPS)
+	if err != nil {
+		return nil, err
+	}
+	if isSetExplicitly(flags, JSON_UNFLATTEN_SEP) || isSetExplicitly(flags, JSON_UNFLATTEN_GROUPS) {
+		unflatten = true
+	}
+	keepPartialOutput, err := flags.GetBool(JSON_KEEP_PARTIAL_OUTPUT)
+	if err != nil {
+		return nil, err
+	}
+	return &jsonWriteFlags{
+		format:            format,
+		compression:       compression,
+		dateformat:        dateformat,
+		timestampformat:   timestampformat,
+		nestedAsString:    nestedAsString,
+		unflatten:         unflatten,
+		unflattenSep:      unflattenSep,
+		unflattenGroups:   unflattenGroups,
+		keepPartialOutput: keepPartialOutput,
+	}, nil
+}
+
+func (f *jsonWriteFlags) writeParams() *jsonparam.WriteParams {
+	return jsonparam.NewWriteParams(
+		jsonparam.WithWriteFormat(jsonparam.Format(f.format)),
+		jsonparam.WithWriteCompression(param.Compression(f.compression)),
+		jsonparam.WithWriteDateFormat(f.dateformat),
+		jsonparam.WithWriteTimestampFormat(f.timestampformat),
+		jsonparam.WithNestedAsString(f.nestedAsString),
+		jsonparam.WithUnflatten(f.unflatten),
+		jsonparam.WithUnflattenConfig(
+			jsonparam.WithUnflattenSeparator(f.unflattenSep),
+			jsonparam.WithUnflattenGroups(f.unflattenGroups...),
+		),
+		jsonparam.WithKeepPartialOutput(f.keepPartialOutput),
+	)
+}
+
 func checkErr(msg string, err error) {
 	if err != nil {
-		fmt.Printf("error: %v. %s\n", err, msg)
+		fmt.Fprintf(os.Stderr, "error: %v. %s\n", err, msg)
 		os.Exit(1)
 	}
 }
@@ -128,15 +418,14 @@ func getDBFile(cmd *cobra.Command) string {
 	return filepath.Clean(filename)
 }
 
-func deleteDBFile(dbFile string) {
+// Removes the DuckDB file and its WAL file
+func deleteDBFile(dbFile string) error {
 	err := os.Remove(dbFile)
-	if err != nil {
-		fmt.Println(err)
-	}
-	err = os.Remove(dbFile + ".wal")
-	if err != nil && !errors.Is(err, os.ErrNotExist) {
-		fmt.Println(err)
+	walErr := os.Remove(dbFile + ".wal")
+	if errors.Is(walErr, os.ErrNotExist) {
+		walErr = nil
 	}
+	return errors.Join(err, walErr)
 }
 
 func getColumnsFlag(flags *pflag.FlagSet, name string) (param.Columns, error) {
@@ -157,11 +446,31 @@ func getColumnsFlag(flags *pflag.FlagSet, name string) (param.Columns, error) {
 		case l > 2:
 			columns = append(columns, param.Column{Name: strings.Join(keyDataType[0:l-1], ":"), Type: keyDataType[l-1]})
 		}
+		if err := model.ValidateType(columns[len(columns)-1].Type); err != nil {
+			return nil, fmt.Errorf("invalid %s flag. error: %w", name, err)
+		}
 	}
 
 	return columns, nil
 }
 
+// Returns the columns of the schema file flag. Returns no columns if the flag is not set.
+func getSchemaFileFlag(flags *pflag.FlagSet) (param.Columns, error) {
+	schemaFile, err := flags.GetString("schema-file")
+	if err != nil {
+		return nil, err
+	}
+	if schemaFile == "" {
+		return nil, nil
+	}
+
+	schema, err := model.LoadSchema(schemaFile)
+	if err != nil {
+		return nil, err
+	}
+	return schema.ParamColumns(), nil
+}
+
 func getVersion() string {
 	duckdbVer, err := fileconv.GetDuckDBVersion()
 	if err != nil {
@@ -184,11 +493,230 @@ func getDuckDBConfig(cmd *cobra.Command) ([]fileconv.DuckDBConfig, error) {
 	return duckDBConfigs, nil
 }
 
+// Returns true if the describe flag is set, or if the describe format flag is set explicitly
 func getDescribeFlag(cmd *cobra.Command) bool {
 	desc, err := cmd.PersistentFlags().GetBool(FILECONV_CLI_DESC)
 	if err != nil {
 		return false
 	}
 
-	return desc
+	return desc || isSetExplicitly(cmd.PersistentFlags(), FILECONV_CLI_DESC_FMT)
+}
+
+func getDescribeFormatFlag(cmd *cobra.Command) (string, error) {
+	format, err := cmd.PersistentFlags().GetString(FILECONV_CLI_DESC_FMT)
+	if err != nil {
+		return "", err
+	}
+
+	switch format {
+	case DESC_FORMAT_TABLE, DESC_FORMAT_JSON, DESC_FORMAT_YAML, DESC_FORMAT_SQL_DDL, DESC_FORMAT_BIGQUERY, DESC_FORMAT_JSON_SCH, DESC_FORMAT_AVRO, DESC_FORMAT_COLUMNS:
+		return format, nil
+	default:
+		return "", fmt.Errorf("invalid describe format: %s. must be one of (table, json, yaml, sql-ddl, bigquery-schema, json-schema, avro-schema, columns)", format)
+	}
+}
+
+func getTransformFlags(cmd *cobra.Command) (*transformFlags, error) {
+	selectCols, err := cmd.PersistentFlags().GetStringSlice(TRANSFORM_SELECT)
+	if err != nil {
+		return nil, err
+	}
+	exclude, err := cmd.PersistentFlags().GetStringSlice(TRANSFORM_EXCLUDE)
+	if err != nil {
+		return nil, err
+	}
+	where, err := cmd.PersistentFlags().GetString(TRANSFORM_WHERE)
+	if err != nil {
+		return nil, err
+	}
+	limit, err := cmd.PersistentFlags().GetInt64(TRANSFORM_LIMIT)
+	if err != nil {
+		return nil, err
+	}
+	mappingFile, err := cmd.PersistentFlags().GetString(TRANSFORM_MAPPING)
+	if err != nil {
+		return nil, err
+	}
+
+	var mapping *transformparam.Mapping
+	if mappingFile != "" {
+		mapping, err = transformparam.LoadMapping(mappingFile)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	rulesFile, err := cmd.PersistentFlags().GetString(TRANSFORM_RULES)
+	if err != nil {
+		return nil, err
+	}
+	rulesReport, err := cmd.PersistentFlags().GetString(TRANSFORM_REPORT)
+	if err != nil {
+		return nil, err
+	}
+
+	var rules *transformparam.Rules
+	if rulesFile != "" {
+		rules, err = transformparam.LoadRules(rulesFile)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &transformFlags{
+		selectCols:  selectCols,
+		exclude:     exclude,
+		where:       where,
+		limit:       limit,
+		mapping:     mapping,
+		rules:       rules,
+		rulesReport: rulesReport,
+	}, nil
+}
+
+func (f *transformFlags) transformParams() []transformparam.TransformParam {
+	return []transformparam.TransformParam{
+		transformparam.WithSelect(f.selectCols...),
+		transformparam.WithExclude(f.exclude...),
+		transformparam.WithWhere(f.where),
+		transformparam.WithLimit(f.limit),
+		transformparam.WithMapping(f.mapping),
+		transformparam.WithRules(f.rules),
+		transformparam.WithRulesReport(f.rulesReport),
+	}
+}
+
+// Prints the schema of the source files in the describe format if the describe flag is set,
+// and the rejected rows and the failed data quality warnings of the conversion
+func printResult(cmd *cobra.Command, result *fileconv.ConversionResult) error {
+	if getDescribeFlag(rootCmd) {
+		format, err := getDescribeFormatFlag(rootCmd)
+		if err != nil {
+			return fmt.Errorf("error: %w. failed getting describe format flag", err)
+		}
+		output, err := formatTableDesc(result.Schema, format, getSourceTableName(cmd))
+		if err != nil {
+			return fmt.Errorf("error: %w. failed formatting described columns", err)
+		}
+		fmt.Print(output)
+	}
+	if result.RowsRejected > 0 {
+		fmt.Fprintf(os.Stderr, "rejected rows: %d\n", result.RowsRejected)
+	}
+	if result.Quality != nil {
+		if failed := result.Quality.Failed(transformparam.SeverityWarn); len(failed) > 0 {
+			fmt.Fprintf(os.Stderr, "data quality warnings: %s\n", strings.Join(failed, ", "))
+		}
+	}
+	return nil
+}
+
+func formatTableDesc(tableDesc *model.TableDesc, format string, table string) (string, error) {
+	switch format {
+	case DESC_FORMAT_JSON:
+		return tableDesc.JSON()
+	case DESC_FORMAT_YAML:
+		return tableDesc.YAML()
+	case DESC_FORMAT_SQL_DDL:
+		return tableDesc.DDL(table), nil
+	case DESC_FORMAT_BIGQUERY:
+		fields, err := tableDesc.BigQuerySchema()
+		if err != nil {
+			return "", err
+		}
+		b, err := json.MarshalIndent(fields, "", "  ")
+		if err != nil {
+			return "", err
+		}
+		return string(b) + "\n", nil
+	case DESC_FORMAT_JSON_SCH:
+		schema, err := tableDesc.JSONSchema(table)
+		if err != nil {
+			return "", err
+		}
+		b, err := json.MarshalIndent(schema, "", "  ")
+		if err != nil {
+			return "", err
+		}
+		return string(b) + "\n", nil
+	case DESC_FORMAT_AVRO:
+		schema, err := tableDesc.AvroSchema(table)
+		if err != nil {
+			return "", err
+		}
+		b, err := json.MarshalIndent(schema, "", "  ")
+		if err != nil {
+			return "", err
+		}
+		return string(b) + "\n", nil
+	case DESC_FORMAT_COLUMNS:
+		return tableDesc.ColumnsFlag(), nil
+	default:
+		return tableDesc.String() + "\n", nil
+	}
+}
+
+// Returns the name of the source file without its extensions, used as the table name of the sql-ddl describe format
+// and as the title or record name of the json-schema and avro-schema describe formats.
+// Returns source if the source flag is not a single file.
+func getSourceTableName(cmd *cobra.Command) string {
+	source, err := cmd.Flags().GetString("source")
+	if err != nil || source == STDIO || strings.ContainsAny(source, "*?[") {
+		return "source"
+	}
+
+	name := filepath.Base(source)
+	if i := strings.Index(name, "."); i > 0 {
+		name = name[:i]
+	}
+	return name
+}
+
+// Returns a csv Source reading from stdin if source is "-"
+func newCsvSource(source string, params ...csvparam.ReadParam) fileconv.Source {
+	if source == STDIO {
+		return fileconv.NewCsvReaderSource(os.Stdin, params...)
+	}
+	return fileconv.NewCsvSource(source, params...)
+}
+
+// Returns a json Source reading from stdin if source is "-"
+func newJsonSource(source string, params ...jsonparam.ReadParam) fileconv.Source {
+	if source == STDIO {
+		return fileconv.NewJsonReaderSource(os.Stdin, params...)
+	}
+	return fileconv.NewJsonSource(source, params...)
+}
+
+// Returns a parquet Source reading from stdin if source is "-"
+func newParquetSource(source string, params ...pqparam.ReadParam) fileconv.Source {
+	if source == STDIO {
+		return fileconv.NewParquetReaderSource(os.Stdin, params...)
+	}
+	return fileconv.NewParquetSource(source, params...)
+}
+
+// Returns a csv Sink writing to stdout if dest is "-"
+func newCsvSink(dest string, params *csvparam.WriteParams) fileconv.Sink {
+	if dest == STDIO {
+		return fileconv.NewCsvWriterSink(os.Stdout, params)
+	}
+	return fileconv.NewCsvSink(dest, params)
+}
+
+// Returns a json Sink writing to stdout if dest is "-"
+func newJsonSink(dest string, params *jsonparam.WriteParams) fileconv.Sink {
+	if dest == STDIO {
+		return fileconv.NewJsonWriterSink(os.Stdout, params)
+	}
+	return fileconv.NewJsonSink(dest, params)
+}
+
+// Returns a parquet Sink writing to stdout if dest is "-"
+func newParquetSink(dest string, params *pqparam.WriteParams) fileconv.Sink {
+	if dest == STDIO {
+		return fileconv.NewParquetWriterSink(os.Stdout, params)
+	}
+	return fileconv.NewParquetSink(dest, params)
 }
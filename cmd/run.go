@@ -0,0 +1,517 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/hbbtekademy/go-fileconv/pkg/fileconv"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v3"
+)
+
+// Manifest of the conversion jobs run by the run command
+type jobManifest struct {
+	// Variables which can be referenced as ${name} in the string values of the jobs
+	Vars map[string]string `yaml:"vars"`
+	// Values used for the fields which are not set on a job
+	Defaults jobSpec `yaml:"defaults"`
+	// Maximum number of jobs run concurrently. Jobs are run sequentially by default.
+	Workers int       `yaml:"workers"`
+	Jobs    []jobSpec `yaml:"jobs"`
+}
+
+// Conversion job of the manifest. The read, write and transform options are named after the
+// flags of the CLI commands, e.g. header, delim, compression, partition-by, select, where.
+// The write options are given without the format prefix.
+type jobSpec struct {
+	Name         string         `yaml:"name"`
+	From         string         `yaml:"from"`
+	To           string         `yaml:"to"`
+	Source       string         `yaml:"source"`
+	Dest         string         `yaml:"dest"`
+	Read         map[string]any `yaml:"read"`
+	Write        map[string]any `yaml:"write"`
+	Transform    map[string]any `yaml:"transform"`
+	DuckDBConfig []string       `yaml:"duckdb-config"`
+}
+
+// Validated job ready to be run
+type job struct {
+	name          string
+	src           fileconv.Source
+	sink          fileconv.Sink
+	duckdbConfigs []fileconv.DuckDBConfig
+}
+
+type jobResult struct {
+	name   string
+	result *fileconv.ConversionResult
+	err    error
+}
+
+const (
+	RUN_JOB     string = "job"
+	RUN_WORKERS string = "workers"
+	RUN_VAR     string = "var"
+)
+
+var runCmd = &cobra.Command{
+	Use:   "run",
+	Short: "Run the conversion jobs of a job manifest",
+	Long: `Run the conversion jobs of a YAML job manifest.
+All the jobs are validated before any job is run. A summary of the jobs is printed once all the jobs complete,
+and the command fails if any job failed. The describe, progress, transform and duckdb-config flags are ignored, they are set per job.
+
+vars:
+  in: /data/in
+defaults:
+  from: csv
+  to: parquet
+  read:
+    header: true
+  write:
+    compression: zstd
+jobs:
+  - name: iris
+    source: ${in}/iris*.csv
+    dest: /data/out/iris.parquet
+    transform:
+      where: species = 'setosa'`,
+	Run: func(cmd *cobra.Command, args []string) {
+		err := runRunCmd(cmd)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(runCmd)
+	registerRunFlags(runCmd)
+}
+
+func runRunCmd(cmd *cobra.Command) (err error) {
+	jobFile, err := cmd.Flags().GetString(RUN_JOB)
+	if err != nil {
+		return fmt.Errorf("error: %w. failed getting job flag", err)
+	}
+
+	workers, err := cmd.Flags().GetInt(RUN_WORKERS)
+	if err != nil {
+		return fmt.Errorf("error: %w. failed getting workers flag", err)
+	}
+
+	vars, err := cmd.Flags().GetStringToString(RUN_VAR)
+	if err != nil {
+		return fmt.Errorf("error: %w. failed getting var flag", err)
+	}
+
+	manifest, err := loadJobManifest(jobFile, vars)
+	if err != nil {
+		return fmt.Errorf("error: %w. failed loading job manifest", err)
+	}
+
+	jobs, err := manifest.jobs()
+	if err != nil {
+		return fmt.Errorf("error: %w. invalid job manifest", err)
+	}
+
+	if workers == 0 {
+		workers = manifest.Workers
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	results := runJobs(ctx, cmd, jobs, workers)
+	printJobResults(results)
+
+	failed := 0
+	for _, result := range results {
+		if result.err != nil {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("error: %d of %d jobs failed", failed, len(results))
+	}
+	return nil
+}
+
+func registerRunFlags(cmd *cobra.Command) {
+	cmd.Flags().SortFlags = false
+
+	cmd.Flags().String(RUN_JOB, "", "YAML job manifest listing the conversion jobs.")
+	err := cmd.MarkFlagRequired(RUN_JOB)
+	checkErr("failed setting job flag as required", err)
+
+	cmd.Flags().StringToString(RUN_VAR, map[string]string{}, "(Optional) Variables of the manifest, e.g. --var in=/data/in. Overrides the vars of the manifest.")
+	cmd.Flags().Int(RUN_WORKERS, 0, "(Optional) Maximum number of jobs run concurrently. Overrides the workers of the manifest.\n\n")
+}
+
+// Loads the job manifest, applies the defaults to the jobs and expands the variables.
+// vars override the variables of the manifest.
+func loadJobManifest(path string, vars map[string]string) (*jobManifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest := &jobManifest{}
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	err = decoder.Decode(manifest)
+	if err != nil {
+		return nil, fmt.Errorf("failed parsing job manifest: %s. error: %w", path, err)
+	}
+
+	if manifest.Vars == nil {
+		manifest.Vars = map[string]string{}
+	}
+	for k, v := range vars {
+		manifest.Vars[k] = v
+	}
+
+	errs := []error{}
+	for i := range manifest.Jobs {
+		spec := manifest.Jobs[i].withDefaults(manifest.Defaults)
+		if spec.Name == "" {
+			spec.Name = fmt.Sprintf("job-%d", i+1)
+		}
+
+		spec, err = spec.expand(manifest.Vars)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("job %s: %w", spec.Name, err))
+		}
+		manifest.Jobs[i] = spec
+	}
+
+	return manifest, errors.Join(errs...)
+}
+
+// Returns the validated jobs of the manifest. All the invalid jobs are reported.
+func (m *jobManifest) jobs() ([]*job, error) {
+	if len(m.Jobs) == 0 {
+		return nil, fmt.Errorf("no jobs in job manifest")
+	}
+
+	jobs := make([]*job, 0, len(m.Jobs))
+	names := map[string]bool{}
+	errs := []error{}
+
+	for _, spec := range m.Jobs {
+		if names[spec.Name] {
+			errs = append(errs, fmt.Errorf("job %s: duplicate job name", spec.Name))
+			continue
+		}
+		names[spec.Name] = true
+
+		j, err := spec.job()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("job %s: %w", spec.Name, err))
+			continue
+		}
+		jobs = append(jobs, j)
+	}
+
+	return jobs, errors.Join(errs...)
+}
+
+// Returns the spec with the unset fields taken from the defaults.
+// The read, write and transform options are merged with the default options.
+func (s jobSpec) withDefaults(defaults jobSpec) jobSpec {
+	if s.From == "" {
+		s.From = defaults.From
+	}
+	if s.To == "" {
+		s.To = defaults.To
+	}
+	if s.Source == "" {
+		s.Source = defaults.Source
+	}
+	if s.Dest == "" {
+		s.Dest = defaults.Dest
+	}
+	if s.DuckDBConfig == nil {
+		s.DuckDBConfig = defaults.DuckDBConfig
+	}
+	s.Read = mergeOptions(defaults.Read, s.Read)
+	s.Write = mergeOptions(defaults.Write, s.Write)
+	s.Transform = mergeOptions(defaults.Transform, s.Transform)
+	return s
+}
+
+// Returns the spec with the ${name} variables of its string values replaced
+func (s jobSpec) expand(vars map[string]string) (jobSpec, error) {
+	missing := map[string]bool{}
+	expand := func(value string) string {
+		return os.Expand(value, func(name string) string {
+			v, ok := vars[name]
+			if !ok {
+				missing[name] = true
+			}
+			return v
+		})
+	}
+
+	s.Source = expand(s.Source)
+	s.Dest = expand(s.Dest)
+	s.Read = expandOptions(s.Read, expand)
+	s.Write = expandOptions(s.Write, expand)
+	s.Transform = expandOptions(s.Transform, expand)
+
+	duckdbConfig := make([]string, 0, len(s.DuckDBConfig))
+	for _, c := range s.DuckDBConfig {
+		duckdbConfig = append(duckdbConfig, expand(c))
+	}
+	s.DuckDBConfig = duckdbConfig
+
+	if len(missing) > 0 {
+		return s, fmt.Errorf("undefined variables: %s", strings.Join(sortedKeys(missing), ", "))
+	}
+	return s, nil
+}
+
+// Returns the job with its source and sink built from the options of the spec
+func (s jobSpec) job() (*job, error) {
+	from, err := parseFormat(s.From)
+	if err != nil {
+		return nil, fmt.Errorf("invalid from. error: %w", err)
+	}
+	to, err := parseFormat(s.To)
+	if err != nil {
+		return nil, fmt.Errorf("invalid to. error: %w", err)
+	}
+
+	if s.Source == "" || s.Dest == "" {
+		return nil, fmt.Errorf("source and dest are required")
+	}
+	if s.Source == STDIO || s.Dest == STDIO {
+		return nil, fmt.Errorf("stdin and stdout are not supported in jobs")
+	}
+
+	transformCmd := &cobra.Command{}
+	registerTransformFlags(transformCmd)
+	err = setOptionFlags(transformCmd.PersistentFlags(), "", s.Transform)
+	if err != nil {
+		return nil, fmt.Errorf("invalid transform. error: %w", err)
+	}
+	transformFlags, err := getTransformFlags(transformCmd)
+	if err != nil {
+		return nil, fmt.Errorf("invalid transform. error: %w", err)
+	}
+
+	readCmd := &cobra.Command{}
+	registerFormatReadFlags(readCmd, from)
+	err = setOptionFlags(readCmd.Flags(), "", s.Read)
+	if err != nil {
+		return nil, fmt.Errorf("invalid read. error: %w", err)
+	}
+	src, err := newFormatSource(readCmd.Flags(), from, to, s.Source, false, transformFlags.transformParams())
+	if err != nil {
+		return nil, fmt.Errorf("invalid read. %w", err)
+	}
+
+	writeCmd := &cobra.Command{}
+	registerFormatWriteFlags(writeCmd, to)
+	err = setOptionFlags(writeCmd.PersistentFlags(), writeFlagPrefix(to), s.Write)
+	if err != nil {
+		return nil, fmt.Errorf("invalid write. error: %w", err)
+	}
+	sink, err := getConvertSink(writeCmd.PersistentFlags(), to, s.Dest)
+	if err != nil {
+		return nil, fmt.Errorf("invalid write. %w", err)
+	}
+
+	duckdbConfigs := make([]fileconv.DuckDBConfig, 0, len(s.DuckDBConfig))
+	for _, c := range s.DuckDBConfig {
+		duckdbConfigs = append(duckdbConfigs, fileconv.DuckDBConfig(c))
+	}
+
+	return &job{
+		name:          s.Name,
+		src:           src,
+		sink:          sink,
+		duckdbConfigs: duckdbConfigs,
+	}, nil
+}
+
+// Runs the jobs with at most workers jobs running concurrently.
+// The results are returned in the order of the jobs.
+func runJobs(ctx context.Context, cmd *cobra.Command, jobs []*job, workers int) []*jobResult {
+	if workers < 1 {
+		workers = 1
+	}
+
+	results := make([]*jobResult, len(jobs))
+	sem := make(chan struct{}, workers)
+	var wg sync.WaitGroup
+
+	for i, j := range jobs {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, j *job) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			result, err := runJob(ctx, fmt.Sprintf("%s.%d", getDBFile(cmd), i), j)
+			results[i] = &jobResult{name: j.name, result: result, err: err}
+		}(i, j)
+	}
+
+	wg.Wait()
+	return results
+}
+
+func runJob(ctx context.Context, dbFile string, j *job) (result *fileconv.ConversionResult, err error) {
+	defer func() { err = errors.Join(err, deleteDBFile(dbFile)) }()
+
+	client, err := fileconv.New(ctx, dbFile, j.duckdbConfigs...)
+	if err != nil {
+		return nil, fmt.Errorf("failed getting duckdb client. error: %w", err)
+	}
+	defer client.Close()
+
+	return client.Convert(ctx, j.src, j.sink)
+}
+
+func printJobResults(results []*jobResult) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "JOB\tSTATUS\tROWS WRITTEN\tROWS REJECTED\tELAPSED\tERROR")
+	for _, r := range results {
+		if r.err != nil {
+			fmt.Fprintf(w, "%s\tFAILED\t-\t-\t-\t%s\n", r.name, strings.Join(strings.Fields(r.err.Error()), " "))
+			continue
+		}
+		fmt.Fprintf(w, "%s\tOK\t%d\t%d\t%s\t\n", r.name, r.result.RowsWritten, r.result.RowsRejected, r.result.Elapsed.Round(time.Millisecond))
+	}
+	w.Flush()
+}
+
+func registerFormatReadFlags(cmd *cobra.Command, format fileconv.Format) {
+	switch format {
+	case fileconv.Csv:
+		registerCsvReadFlags(cmd)
+	case fileconv.Json:
+		registerJsonReadFlags(cmd)
+	case fileconv.Parquet:
+		registerPqReadFlags(cmd)
+	}
+}
+
+func registerFormatWriteFlags(cmd *cobra.Command, format fileconv.Format) {
+	switch format {
+	case fileconv.Csv:
+		registerCsvWriteFlags(cmd)
+	case fileconv.Json:
+		registerJsonWriteFlags(cmd)
+	case fileconv.Parquet:
+		registerPqWriteFlags(cmd)
+	}
+}
+
+// Returns the prefix of the write flags of the format
+func writeFlagPrefix(format fileconv.Format) string {
+	switch format {
+	case fileconv.Csv:
+		return "csv-"
+	case fileconv.Json:
+		return "json-"
+	default:
+		return "pq-"
+	}
+}
+
+// Sets the flags named prefix + option to the option values
+func setOptionFlags(flags *pflag.FlagSet, prefix string, options map[string]any) error {
+	for _, name := range sortedKeys(options) {
+		flag := flags.Lookup(prefix + name)
+		if flag == nil {
+			return fmt.Errorf("unknown option: %s", name)
+		}
+
+		err := setOptionFlag(flags, flag.Name, options[name])
+		if err != nil {
+			return fmt.Errorf("invalid value for option: %s. error: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// Sets the flag to the option value.
+// List values set each of their items, other values are set as their string representation.
+func setOptionFlag(flags *pflag.FlagSet, name string, option any) error {
+	values := []any{option}
+	if list, ok := option.([]any); ok {
+		values = list
+	}
+
+	for _, value := range values {
+		switch value.(type) {
+		case []any, map[string]any:
+			return fmt.Errorf("must be a scalar or a list of scalars")
+		}
+
+		err := flags.Set(name, fmt.Sprint(value))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Returns the options with the values of options overriding the defaults
+func mergeOptions(defaults map[string]any, options map[string]any) map[string]any {
+	merged := make(map[string]any, len(defaults)+len(options))
+	for k, v := range defaults {
+		merged[k] = v
+	}
+	for k, v := range options {
+		merged[k] = v
+	}
+	return merged
+}
+
+func expandOptions(options map[string]any, expand func(string) string) map[string]any {
+	expanded := make(map[string]any, len(options))
+	for k, v := range options {
+		expanded[k] = expandValue(v, expand)
+	}
+	return expanded
+}
+
+func expandValue(value any, expand func(string) string) any {
+	switch v := value.(type) {
+	case string:
+		return expand(v)
+	case []any:
+		list := make([]any, 0, len(v))
+		for _, item := range v {
+			list = append(list, expandValue(item, expand))
+		}
+		return list
+	default:
+		return v
+	}
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
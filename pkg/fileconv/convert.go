@@ -0,0 +1,252 @@
+package fileconv
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hbbtekademy/go-fileconv/pkg/model"
+	"github.com/hbbtekademy/go-fileconv/pkg/param"
+	"github.com/hbbtekademy/go-fileconv/pkg/param/transformparam"
+)
+
+type Format string
+
+const (
+	Csv     Format = "csv"
+	Json    Format = "json"
+	Parquet Format = "parquet"
+)
+
+// Source of a conversion. Use NewCsvSource, NewJsonSource or NewParquetSource to create a Source.
+type Source interface {
+	// Format of the source files
+	Format() Format
+
+	// Returns the query selecting all the rows of the source and a func releasing any resources
+	// created for the query. The release func must be called once the query has been executed.
+	query(ctx context.Context, c *fileconv) (string, func(), error)
+
+	// Returns the column names and types of the source
+	describe(ctx context.Context, c *fileconv) (*model.TableDesc, error)
+
+	getDescribe() bool
+
+	getTransform() *transformparam.TransformParams
+
+	// Returns the query selecting the rows rejected by the source query, or an empty string if the rejected rows
+	// are not captured. The query must be executed on the connection which executed the source query.
+	rejectsQuery() (string, error)
+
+	// Returns the path of the rejects file and the maximum number of rejected rows
+	getRejects() (string, int64)
+}
+
+// Sink of a conversion. Use NewCsvSink, NewJsonSink or NewParquetSink to create a Sink.
+type Sink interface {
+	// Format of the output files
+	Format() Format
+
+	// Returns the command copying the rows selected by query to the sink
+	copyCmd(ctx context.Context, c *fileconv, query string) (string, error)
+
+	getPath() string
+
+	// Returns true if the sink writes multiple files in a directory
+	isMultiFile() bool
+
+	// Returns a copy of the sink writing to path
+	withPath(path string) Sink
+
+	// Returns true if files may be written into an existing non empty output directory
+	getOverwriteOrIgnore() bool
+
+	getKeepPartialOutput() bool
+}
+
+// Convert the source files to the sink format.
+// Any source can be paired with any sink.
+// If the describe param of the source is set, no files are written and only the Schema of the result is set.
+// The output is written to a temp directory next to the sink path and renamed to the sink path once the
+// conversion succeeds, so a failed or cancelled conversion leaves any existing output unchanged.
+// The temp output of a failed conversion is removed unless WithKeepPartialOutput of the write params is set.
+// If the transform of the source has data quality rules, the rows are staged and checked before they are written,
+// and a *QualityError is returned without writing any rows if a rule with severity error fails.
+func (c *fileconv) Convert(ctx context.Context, src Source, sink Sink) (*ConversionResult, error) {
+	start := time.Now()
+
+	src, releaseSrc, err := openSource(ctx, src)
+	if err != nil {
+		return nil, fmt.Errorf("failed opening %s source. error: %w", src.Format(), err)
+	}
+	defer releaseSrc()
+
+	// describing the source writes no output
+	if src.getDescribe() {
+		return c.convert(ctx, src, sink)
+	}
+
+	fileSink, closeSink, err := openSink(sink)
+	if err != nil {
+		return nil, fmt.Errorf("failed opening %s sink. error: %w", sink.Format(), err)
+	}
+
+	result, err := c.convert(ctx, src, fileSink)
+	if err != nil {
+		if sink.getKeepPartialOutput() && hasPartialOutput(fileSink.getPath()) {
+			return nil, fmt.Errorf("%w. partial output kept in: %s", err, fileSink.getPath())
+		}
+		closeSink(ctx, false)
+		return nil, err
+	}
+
+	err = closeSink(ctx, len(result.OutputFiles) > 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed writing %s output. error: %w", sink.Format(), err)
+	}
+	if _, ok := sink.(*writerSink); ok {
+		result.OutputFiles = nil
+	} else {
+		result.OutputFiles = relocateOutputFiles(result.OutputFiles, fileSink.getPath(), sink.getPath())
+	}
+
+	result.Elapsed = time.Since(start)
+	return result, nil
+}
+
+func (c *fileconv) convert(ctx context.Context, src Source, sink Sink) (*ConversionResult, error) {
+	start := time.Now()
+
+	if src.getDescribe() {
+		tableDesc, err := src.describe(ctx, c)
+		if err != nil {
+			return nil, err
+		}
+
+		return &ConversionResult{
+			Elapsed: time.Since(start),
+			Schema:  tableDesc,
+		}, nil
+	}
+
+	query, release, err := src.query(ctx, c)
+	if err != nil {
+		return nil, fmt.Errorf("failed getting %s source query. error: %w", src.Format(), err)
+	}
+	defer release()
+
+	srcQuery := query
+	query, tableDesc, err := c.applyTransform(ctx, query, src.getTransform())
+	if err != nil {
+		return nil, err
+	}
+
+	rules := src.getTransform().GetRules()
+	if rules != nil {
+		err = rules.Validate(tableDesc)
+		if err != nil {
+			return nil, fmt.Errorf("invalid rules. error: %w", err)
+		}
+	}
+
+	rejectsQuery, err := src.rejectsQuery()
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s source rejects. error: %w", src.Format(), err)
+	}
+
+	// the rejected rows are saved on the connection reading the source as the reject tables are temporary
+	beforeCmds, afterCmds := []string{}, []string{}
+	rejectsTable := ""
+	if rejectsQuery != "" {
+		var saveCmd string
+		saveCmd, rejectsTable = saveRejectsCmd(rejectsQuery)
+		beforeCmds = append(beforeCmds, clearRejectTablesCmds...)
+		afterCmds = append(afterCmds, saveCmd)
+		defer c.dropTable(context.WithoutCancel(ctx), rejectsTable)
+	}
+
+	// the rules are checked on the rows staged to a table, which are copied to the sink only if no error rule fails
+	var rejected int64
+	var quality *QualityReport
+	if rules != nil {
+		cmd, stageTable := stageCmd(query)
+		_, err = c.executeCopyCmd(ctx, cmd, beforeCmds, afterCmds)
+		if err != nil {
+			return nil, fmt.Errorf("failed staging %s rows. error: %w", src.Format(), err)
+		}
+		defer c.dropTable(context.WithoutCancel(ctx), stageTable)
+
+		if rejectsTable != "" {
+			rejected, err = c.checkRejects(ctx, src, rejectsTable)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		quality, err = c.checkQuality(ctx, stageTable, rules, src.getTransform().GetRulesReport())
+		if err != nil {
+			return nil, fmt.Errorf("failed checking data quality rules. error: %w", err)
+		}
+		if !quality.Passed {
+			return nil, &QualityError{Report: quality}
+		}
+
+		query = fmt.Sprintf("SELECT * FROM %s", param.QuoteIdent(stageTable))
+		beforeCmds, afterCmds, rejectsTable = []string{}, []string{}, ""
+	}
+
+	copyCmd, err := sink.copyCmd(ctx, c, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed getting %s sink copy command. error: %w", sink.Format(), err)
+	}
+
+	rows, err := c.executeCopyCmd(ctx, copyCmd, beforeCmds, afterCmds)
+	if err != nil {
+		return nil, fmt.Errorf("failed converting %s to %s. error: %w", src.Format(), sink.Format(), err)
+	}
+
+	if rejectsTable != "" {
+		rejected, err = c.checkRejects(ctx, src, rejectsTable)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	outputFiles, err := getOutputFiles(sink.getPath())
+	if err != nil {
+		return nil, fmt.Errorf("failed getting %s output files. error: %w", sink.Format(), err)
+	}
+
+	// partitioned COPY does not report the number of rows written
+	if rows == 0 && sink.Format() == Parquet && len(outputFiles) > 0 {
+		rows, err = c.getParquetRowCount(ctx, outputFiles)
+		if err != nil {
+			return nil, fmt.Errorf("failed getting parquet row count. error: %w", err)
+		}
+	}
+
+	// the rows written differ from the rows read if the rows are filtered, limited or rejected
+	rowsRead := rows
+	transform := src.getTransform()
+	if transform.GetWhere() != "" || transform.GetLimit() != 0 || rules != nil || rejectsQuery != "" {
+		// counting the rows as structs reads all the columns, so the rows with values which cannot be read are skipped
+		rowsRead, err = c.queryCount(ctx, fmt.Sprintf("SELECT count(src) FROM (%s) src", srcQuery))
+		if err != nil {
+			return nil, fmt.Errorf("failed getting %s source row count. error: %w", src.Format(), err)
+		}
+		// the rejected rows are skipped by the source query
+		rowsRead += rejected
+	}
+
+	reportDone(ctx, rows)
+
+	return &ConversionResult{
+		RowsRead:     rowsRead,
+		RowsWritten:  rows,
+		RowsRejected: rejected,
+		Quality:      quality,
+		OutputFiles:  outputFiles,
+		Elapsed:      time.Since(start),
+		Schema:       tableDesc,
+	}, nil
+}
@@ -0,0 +1,648 @@
+package fileconv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hbbtekademy/go-fileconv/pkg/param"
+	"github.com/hbbtekademy/go-fileconv/pkg/param/csvparam"
+	"github.com/hbbtekademy/go-fileconv/pkg/param/jsonparam"
+	"github.com/hbbtekademy/go-fileconv/pkg/param/pqparam"
+	"github.com/hbbtekademy/go-fileconv/pkg/param/transformparam"
+)
+
+func TestConvert(t *testing.T) {
+	tests := []struct {
+		name             string
+		src              Source
+		sink             Sink
+		output           string
+		expectedRowCount int
+	}{
+		{
+			name:             "TC1",
+			src:              NewCsvSource("../../testdata/csv/iris150.csv", csvparam.WithHeader(true)),
+			sink:             NewParquetSink("../../testdata/convert/iris150.parquet", pqparam.NewWriteParams()),
+			output:           "../../testdata/convert/iris150.parquet",
+			expectedRowCount: 150,
+		},
+		{
+			name:             "TC2",
+			src:              NewParquetSource("../../testdata/convert/iris150.parquet"),
+			sink:             NewCsvSink("../../testdata/convert/iris150.csv", csvparam.NewWriteParams()),
+			output:           "../../testdata/convert/iris150.csv",
+			expectedRowCount: 150,
+		},
+		{
+			name:             "TC3",
+			src:              NewCsvSource("../../testdata/convert/iris150.csv", csvparam.WithHeader(true)),
+			sink:             NewJsonSink("../../testdata/convert/iris150.json", jsonparam.NewWriteParams()),
+			output:           "../../testdata/convert/iris150.json",
+			expectedRowCount: 150,
+		},
+		{
+			name:             "TC4",
+			src:              NewJsonSource("../../testdata/convert/iris150.json", jsonparam.WithFormat(jsonparam.NewlineDelimited)),
+			sink:             NewParquetSink("../../testdata/convert/iris150_json.parquet", pqparam.NewWriteParams()),
+			output:           "../../testdata/convert/iris150_json.parquet",
+			expectedRowCount: 150,
+		},
+	}
+
+	dbFile := fmt.Sprintf("test_%d.db", time.Now().UnixNano())
+	defer os.RemoveAll(dbFile)
+	defer os.RemoveAll(dbFile + ".wal")
+
+	conv, err := newFileconv(context.Background(), dbFile)
+	if err != nil {
+		t.Fatalf("failed getting duckdb client. error: %v", err)
+	}
+	defer conv.Close()
+
+	err = os.MkdirAll("../../testdata/convert", 0755)
+	if err != nil {
+		t.Fatalf("failed creating output dir. error: %v", err)
+	}
+	defer deleteOutput("../../testdata/convert")
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := conv.Convert(context.Background(), tc.src, tc.sink)
+			if err != nil {
+				t.Fatalf("failed converting %s to %s. error: %v", tc.src.Format(), tc.sink.Format(), err)
+			}
+
+			if result.RowsWritten != int64(tc.expectedRowCount) {
+				t.Fatalf("expected rows written: %d but got: %d", tc.expectedRowCount, result.RowsWritten)
+			}
+			if len(result.OutputFiles) != 1 || result.OutputFiles[0].Path != tc.output || result.OutputFiles[0].Size == 0 {
+				t.Fatalf("expected output file: %s but got: %v", tc.output, result.OutputFiles)
+			}
+
+			err = validateOutput(conv, tc.output, tc.expectedRowCount)
+			if err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestConvertTransform(t *testing.T) {
+	tests := []struct {
+		name             string
+		src              Source
+		output           string
+		expectedRowsRead int64
+		expectedRowCount int
+		expectedDesc     string
+		expectedErr      string
+	}{
+		{
+			name: "TC1",
+			src: NewCsvSource("../../testdata/csv/iris150.csv",
+				csvparam.WithHeader(true),
+				csvparam.WithTransform(
+					transformparam.WithSelect("species", "sepal_length"),
+					transformparam.WithWhere("species = 'Iris-setosa'"),
+				)),
+			output:           "../../testdata/transform/iris_setosa.parquet",
+			expectedRowsRead: 150,
+			expectedRowCount: 50,
+			expectedDesc:     "COLUMN NAME      | COLUMN TYPE \n=================|============\nspecies          | VARCHAR     \nsepal_length     | DOUBLE      \n",
+		},
+		{
+			name: "TC2",
+			src: NewCsvSource("../../testdata/csv/iris150.csv",
+				csvparam.WithHeader(true),
+				csvparam.WithTransform(
+					transformparam.WithExclude("species"),
+					transformparam.WithLimit(10),
+				)),
+			output:           "../../testdata/transform/iris_limit.parquet",
+			expectedRowsRead: 150,
+			expectedRowCount: 10,
+			expectedDesc:     "COLUMN NAME      | COLUMN TYPE\n=================|===========\nsepal_length     | DOUBLE     \nsepal_width      | DOUBLE     \npetal_length     | DOUBLE     \npetal_width      | DOUBLE     \n",
+		},
+		{
+			name: "TC3",
+			src: NewCsvSource("../../testdata/csv/iris150.csv",
+				csvparam.WithHeader(true),
+				csvparam.WithTransform(
+					transformparam.WithSelect("sepal_lenght"),
+				)),
+			output:      "../../testdata/transform/iris_typo.parquet",
+			expectedErr: `invalid transform. error: unknown column "sepal_lenght" in select. did you mean "sepal_length"?`,
+		},
+		{
+			name: "TC4",
+			src: NewCsvSource("../../testdata/csv/iris150.csv",
+				csvparam.WithHeader(true),
+				csvparam.WithTransform(
+					transformparam.WithMapping(&transformparam.Mapping{
+						Columns: []transformparam.ColumnMapping{
+							{Name: "species", Rename: "iris_species"},
+							{Name: "sepal_length", Cast: "DECIMAL(4,1)", Default: 0},
+						},
+						Computed: []transformparam.ComputedColumn{
+							{Name: "petal_ratio", Expr: "petal_length / petal_width", Cast: "FLOAT"},
+						},
+					}),
+					transformparam.WithSelect("iris_species", "sepal_length", "petal_ratio"),
+					transformparam.WithWhere("iris_species = 'Iris-virginica'"),
+				)),
+			output:           "../../testdata/transform/iris_mapping.parquet",
+			expectedRowsRead: 150,
+			expectedRowCount: 50,
+			expectedDesc:     "COLUMN NAME      | COLUMN TYPE      \n=================|=================\niris_species     | VARCHAR          \nsepal_length     | DECIMAL(4,1)     \npetal_ratio      | FLOAT            \n",
+		},
+		{
+			name: "TC5",
+			src: NewCsvSource("../../testdata/csv/iris150.csv",
+				csvparam.WithHeader(true),
+				csvparam.WithTransform(
+					transformparam.WithMapping(&transformparam.Mapping{
+						Columns: []transformparam.ColumnMapping{
+							{Name: "species", Rename: "iris_species"},
+						},
+					}),
+					transformparam.WithSelect("iris_speceis"),
+				)),
+			output:      "../../testdata/transform/iris_mapping_typo.parquet",
+			expectedErr: `invalid transform. error: unknown column "iris_speceis" in select. did you mean "iris_species"?`,
+		},
+	}
+
+	conv, err := newFileconv(context.Background(), "")
+	if err != nil {
+		t.Fatalf("failed getting duckdb client. error: %v", err)
+	}
+	defer conv.Close()
+
+	err = os.MkdirAll("../../testdata/transform", 0755)
+	if err != nil {
+		t.Fatalf("failed creating output dir. error: %v", err)
+	}
+	defer deleteOutput("../../testdata/transform")
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := conv.Convert(context.Background(), tc.src, NewParquetSink(tc.output, pqparam.NewWriteParams()))
+			if tc.expectedErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.expectedErr) {
+					t.Fatalf("expected error: %s but got: %v", tc.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("failed converting %s to parquet. error: %v", tc.src.Format(), err)
+			}
+
+			if result.RowsRead != tc.expectedRowsRead {
+				t.Fatalf("expected rows read: %d but got: %d", tc.expectedRowsRead, result.RowsRead)
+			}
+			if actual := result.Schema.String(); actual != tc.expectedDesc {
+				t.Fatalf("expected:\n%s\nbut got:\n%s\n", tc.expectedDesc, actual)
+			}
+
+			err = validateOutput(conv, tc.output, tc.expectedRowCount)
+			if err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestConvertAtomicOutput(t *testing.T) {
+	failingCast := transformparam.WithMapping(&transformparam.Mapping{
+		Columns: []transformparam.ColumnMapping{{Name: "species", Cast: "INTEGER"}},
+	})
+	dir := t.TempDir()
+
+	tests := []struct {
+		name                string
+		src                 Source
+		sink                func(output string) Sink
+		output              string
+		existing            string
+		expectedErr         string
+		expectedFiles       []string
+		expectedPartialKept bool
+	}{
+		{
+			name:          "TC1",
+			src:           NewCsvSource("../../testdata/csv/iris150.csv", csvparam.WithHeader(true)),
+			sink:          func(output string) Sink { return NewCsvSink(output, csvparam.NewWriteParams()) },
+			output:        filepath.Join(dir, "tc1.csv"),
+			existing:      "tc1.csv",
+			expectedFiles: []string{"tc1.csv"},
+		},
+		{
+			name:          "TC2",
+			src:           NewCsvSource("../../testdata/csv/iris150.csv", csvparam.WithHeader(true), csvparam.WithTransform(failingCast)),
+			sink:          func(output string) Sink { return NewCsvSink(output, csvparam.NewWriteParams()) },
+			output:        filepath.Join(dir, "tc2.csv"),
+			existing:      "tc2.csv",
+			expectedErr:   "Could not convert string 'Iris-setosa'",
+			expectedFiles: []string{"tc2.csv"},
+		},
+		{
+			name: "TC3",
+			src:  NewCsvSource("../../testdata/csv/iris150.csv", csvparam.WithHeader(true), csvparam.WithTransform(failingCast)),
+			sink: func(output string) Sink {
+				return NewCsvSink(output, csvparam.NewWriteParams(csvparam.WithKeepPartialOutput(true)))
+			},
+			output:              filepath.Join(dir, "tc3.csv"),
+			expectedErr:         "partial output kept in",
+			expectedPartialKept: true,
+		},
+		{
+			name: "TC4",
+			src:  NewCsvSource("../../testdata/csv/iris150.csv", csvparam.WithHeader(true)),
+			sink: func(output string) Sink {
+				return NewParquetSink(output, pqparam.NewWriteParams(
+					pqparam.WithHivePartitionConfig(pqparam.WithPartitionBy("species"))))
+			},
+			output:        filepath.Join(dir, "tc4"),
+			existing:      filepath.Join("tc4", "existing.parquet"),
+			expectedErr:   "is not empty",
+			expectedFiles: []string{filepath.Join("tc4", "existing.parquet")},
+		},
+		{
+			name: "TC5",
+			src:  NewCsvSource("../../testdata/csv/iris150.csv", csvparam.WithHeader(true)),
+			sink: func(output string) Sink {
+				return NewParquetSink(output, pqparam.NewWriteParams(
+					pqparam.WithHivePartitionConfig(pqparam.WithPartitionBy("species"), pqparam.WithOverwriteOrIgnore(true))))
+			},
+			output:   filepath.Join(dir, "tc5"),
+			existing: filepath.Join("tc5", "existing.parquet"),
+			expectedFiles: []string{
+				filepath.Join("tc5", "existing.parquet"),
+				filepath.Join("tc5", "species=Iris-setosa", "data_0.parquet"),
+				filepath.Join("tc5", "species=Iris-versicolor", "data_0.parquet"),
+				filepath.Join("tc5", "species=Iris-virginica", "data_0.parquet"),
+			},
+		},
+		{
+			// describing the source does not create the temp output next to the sink path
+			name:   "TC6",
+			src:    NewCsvSource("../../testdata/csv/iris150.csv", csvparam.WithHeader(true), csvparam.WithDescribe(true)),
+			sink:   func(output string) Sink { return NewCsvSink(output, csvparam.NewWriteParams()) },
+			output: filepath.Join(dir, "missing", "tc6.csv"),
+		},
+	}
+
+	conv, err := newFileconv(context.Background(), "")
+	if err != nil {
+		t.Fatalf("failed getting duckdb client. error: %v", err)
+	}
+	defer conv.Close()
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.existing != "" {
+				existing := filepath.Join(dir, tc.existing)
+				err := os.MkdirAll(filepath.Dir(existing), 0755)
+				if err != nil {
+					t.Fatalf("failed creating output dir. error: %v", err)
+				}
+				err = os.WriteFile(existing, []byte("existing"), 0644)
+				if err != nil {
+					t.Fatalf("failed writing existing output. error: %v", err)
+				}
+			}
+
+			result, err := conv.Convert(context.Background(), tc.src, tc.sink(tc.output))
+			if tc.expectedErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.expectedErr) {
+					t.Fatalf("expected error: %s but got: %v", tc.expectedErr, err)
+				}
+			} else if err != nil {
+				t.Fatalf("failed converting. error: %v", err)
+			}
+
+			tmpDirs, _ := filepath.Glob(filepath.Join(dir, ".fileconv-tmp-*"))
+			if tc.expectedPartialKept != (len(tmpDirs) == 1) {
+				t.Fatalf("expected partial output kept: %v but got temp dirs: %v", tc.expectedPartialKept, tmpDirs)
+			}
+			for _, tmpDir := range tmpDirs {
+				os.RemoveAll(tmpDir)
+			}
+
+			for _, file := range tc.expectedFiles {
+				if _, err := os.Stat(filepath.Join(dir, file)); err != nil {
+					t.Fatalf("expected output file: %s. error: %v", file, err)
+				}
+			}
+			if tc.existing != "" && tc.expectedErr != "" {
+				data, _ := os.ReadFile(filepath.Join(dir, tc.existing))
+				if string(data) != "existing" {
+					t.Fatalf("expected existing output: %s to be unchanged but got: %s", tc.existing, data)
+				}
+			}
+
+			if result != nil {
+				for _, outputFile := range result.OutputFiles {
+					if !strings.HasPrefix(outputFile.Path, tc.output) {
+						t.Fatalf("expected output file in: %s but got: %s", tc.output, outputFile.Path)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestConvertRejects(t *testing.T) {
+	dir := t.TempDir()
+	types := csvparam.WithColumns(param.Columns{
+		{Name: "sepal_length", Type: "DOUBLE"},
+		{Name: "sepal_width", Type: "DOUBLE"},
+		{Name: "petal_length", Type: "DOUBLE"},
+		{Name: "petal_width", Type: "DOUBLE"},
+		{Name: "species", Type: "VARCHAR"},
+	})
+
+	tests := []struct {
+		name             string
+		params           []csvparam.ReadParam
+		rejects          string
+		expectedErr      string
+		expectedRowCount int64
+		expectedRejects  string
+	}{
+		{
+			name:             "TC1",
+			params:           []csvparam.ReadParam{csvparam.WithHeader(true), types, csvparam.WithRejects(filepath.Join(dir, "tc1.csv"))},
+			rejects:          filepath.Join(dir, "tc1.csv"),
+			expectedRowCount: 7,
+			expectedRejects: `file,line,column,error,rejected_line
+../../testdata/csv/iris9_rejects.csv,6,sepal_length,"Error when converting column ""sepal_length"". Could not convert string ""abc"" to 'DOUBLE'","abc,3.0,1.4,0.2,Iris-setosa"
+../../testdata/csv/iris9_rejects.csv,10,,Expected Number of Columns: 5 Found: 6,"4.6,3.4,1.4,0.3,Iris-setosa,extra"
+`,
+		},
+		{
+			name: "TC2",
+			params: []csvparam.ReadParam{csvparam.WithHeader(true), types,
+				csvparam.WithRejects(filepath.Join(dir, "tc2.csv")), csvparam.WithMaxRejects(1)},
+			rejects:     filepath.Join(dir, "tc2.csv"),
+			expectedErr: "rejected rows: 2 exceed max rejects: 1",
+		},
+		{
+			name:        "TC3",
+			params:      []csvparam.ReadParam{csvparam.WithHeader(true), csvparam.WithAutoDetect(false), types},
+			expectedErr: "Could not convert string \"abc\" to 'DOUBLE'",
+		},
+	}
+
+	conv, err := newFileconv(context.Background(), "")
+	if err != nil {
+		t.Fatalf("failed getting duckdb client. error: %v", err)
+	}
+	defer conv.Close()
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			output := filepath.Join(dir, tc.name+".parquet")
+			result, err := conv.Convert(context.Background(),
+				NewCsvSource("../../testdata/csv/iris9_rejects.csv", tc.params...),
+				NewParquetSink(output, pqparam.NewWriteParams()))
+			if tc.expectedErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.expectedErr) {
+					t.Fatalf("expected error: %s but got: %v", tc.expectedErr, err)
+				}
+				if _, err := os.Stat(output); !errors.Is(err, os.ErrNotExist) {
+					t.Fatalf("expected no output: %s. error: %v", output, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("failed converting csv to parquet. error: %v", err)
+			}
+
+			if result.RowsWritten != tc.expectedRowCount || result.RowsRejected != 2 {
+				t.Fatalf("expected rows written: %d and rejected: 2 but got: %d and %d", tc.expectedRowCount, result.RowsWritten, result.RowsRejected)
+			}
+			if result.RowsRead != tc.expectedRowCount+2 {
+				t.Fatalf("expected rows read: %d but got: %d", tc.expectedRowCount+2, result.RowsRead)
+			}
+
+			rejects, err := os.ReadFile(tc.rejects)
+			if err != nil {
+				t.Fatalf("failed reading rejects. error: %v", err)
+			}
+			if string(rejects) != tc.expectedRejects {
+				t.Fatalf("expected rejects:\n%s\nbut got:\n%s", tc.expectedRejects, rejects)
+			}
+		})
+	}
+}
+
+func TestConvertJsonRejects(t *testing.T) {
+	dir := t.TempDir()
+	types := jsonparam.WithColumns(param.Columns{
+		{Name: "sepalLength", Type: "DOUBLE"},
+		{Name: "sepalWidth", Type: "DOUBLE"},
+		{Name: "petalLength", Type: "DOUBLE"},
+		{Name: "petalWidth", Type: "DOUBLE"},
+		{Name: "species", Type: "VARCHAR"},
+	})
+
+	// bad lines far beyond the first chunk of rows read by read_csv
+	large := filepath.Join(dir, "large.json")
+	var sb strings.Builder
+	for i := 1; i <= 100000; i++ {
+		switch i {
+		case 70001:
+			sb.WriteString(`{"sepalLength": "abc", "sepalWidth": 3.0, "petalLength": 1.4, "petalWidth": 0.2, "species": "setosa"}` + "\n")
+		case 99999:
+			sb.WriteString(`{"sepalLength": 5.4, "sepalWidth": 3.9,` + "\n")
+		default:
+			sb.WriteString(fmt.Sprintf(`{"sepalLength": %d.1, "sepalWidth": 3.5, "petalLength": 1.4, "petalWidth": 0.2, "species": "setosa"}`, i%10) + "\n")
+		}
+	}
+	err := os.WriteFile(large, []byte(sb.String()), 0644)
+	if err != nil {
+		t.Fatalf("failed writing input json. error: %v", err)
+	}
+
+	tests := []struct {
+		name            string
+		input           string
+		params          []jsonparam.ReadParam
+		expectedRejects string
+	}{
+		{
+			name:   "TC1",
+			input:  "../../testdata/json/iris9_rejects.json",
+			params: []jsonparam.ReadParam{types},
+			expectedRejects: `file,line,column,error,rejected_line
+../../testdata/json/iris9_rejects.json,5,sepalLength,"Error when converting column ""sepalLength"" to 'DOUBLE'","{""sepalLength"": ""abc"", ""sepalWidth"": 3.0, ""petalLength"": 1.4, ""petalWidth"": 0.2, ""species"": ""setosa""}"
+../../testdata/json/iris9_rejects.json,7,,malformed json,"{""sepalLength"": 5.4, ""sepalWidth"": 3.9, ""petalLength"": 1.7,"
+`,
+		},
+		{
+			name:   "TC2",
+			input:  "../../testdata/json/iris9_rejects.json.gz",
+			params: []jsonparam.ReadParam{types, jsonparam.WithCompression(param.Gzip)},
+			expectedRejects: `file,line,column,error,rejected_line
+../../testdata/json/iris9_rejects.json.gz,5,sepalLength,"Error when converting column ""sepalLength"" to 'DOUBLE'","{""sepalLength"": ""abc"", ""sepalWidth"": 3.0, ""petalLength"": 1.4, ""petalWidth"": 0.2, ""species"": ""setosa""}"
+../../testdata/json/iris9_rejects.json.gz,7,,malformed json,"{""sepalLength"": 5.4, ""sepalWidth"": 3.9, ""petalLength"": 1.7,"
+`,
+		},
+		{
+			name:   "TC3",
+			input:  large,
+			params: []jsonparam.ReadParam{types},
+			expectedRejects: fmt.Sprintf(`file,line,column,error,rejected_line
+%s,70001,sepalLength,"Error when converting column ""sepalLength"" to 'DOUBLE'","{""sepalLength"": ""abc"", ""sepalWidth"": 3.0, ""petalLength"": 1.4, ""petalWidth"": 0.2, ""species"": ""setosa""}"
+%s,99999,,malformed json,"{""sepalLength"": 5.4, ""sepalWidth"": 3.9,"
+`, large, large),
+		},
+	}
+
+	conv, err := newFileconv(context.Background(), "")
+	if err != nil {
+		t.Fatalf("failed getting duckdb client. error: %v", err)
+	}
+	defer conv.Close()
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			rejectsPath := filepath.Join(dir, tc.name+".csv")
+			params := append(tc.params, jsonparam.WithFormat(jsonparam.NewlineDelimited), jsonparam.WithRejects(rejectsPath))
+			result, err := conv.Convert(context.Background(),
+				NewJsonSource(tc.input, params...),
+				NewParquetSink(filepath.Join(dir, tc.name+".parquet"), pqparam.NewWriteParams()))
+			if err != nil {
+				t.Fatalf("failed converting json to parquet. error: %v", err)
+			}
+
+			if result.RowsRejected != 2 {
+				t.Fatalf("expected rows rejected: 2 but got: %d", result.RowsRejected)
+			}
+
+			rejects, err := os.ReadFile(rejectsPath)
+			if err != nil {
+				t.Fatalf("failed reading rejects. error: %v", err)
+			}
+			if string(rejects) != tc.expectedRejects {
+				t.Fatalf("expected rejects:\n%s\nbut got:\n%s", tc.expectedRejects, rejects)
+			}
+		})
+	}
+}
+
+func TestConvertRules(t *testing.T) {
+	dir := t.TempDir()
+	one, five := float64(1), float64(5)
+	rules := &transformparam.Rules{Rules: []transformparam.Rule{
+		{Check: transformparam.NotNull, Column: "species"},
+		{Check: transformparam.Range, Column: "sepal_length", Max: &five, Severity: transformparam.SeverityWarn},
+		{Check: transformparam.RowCount, Min: &one},
+	}}
+
+	tests := []struct {
+		name             string
+		src              Source
+		expectedErr      string
+		expectedRowCount int64
+		expectedRejected int64
+		expectedFailed   []string
+	}{
+		{
+			name: "TC1",
+			src: NewCsvSource("../../testdata/csv/iris150.csv", csvparam.WithHeader(true),
+				csvparam.WithTransform(transformparam.WithRules(rules), transformparam.WithRulesReport(filepath.Join(dir, "TC1.json")))),
+			expectedRowCount: 150,
+			expectedFailed:   []string{"range(sepal_length)"},
+		},
+		{
+			name: "TC2",
+			src: NewCsvSource("../../testdata/csv/iris150.csv", csvparam.WithHeader(true),
+				csvparam.WithTransform(transformparam.WithWhere("species = 'unknown'"),
+					transformparam.WithRules(rules), transformparam.WithRulesReport(filepath.Join(dir, "TC2.json")))),
+			expectedErr: "data quality rules failed: row_count",
+		},
+		{
+			name: "TC3",
+			src: NewCsvSource("../../testdata/csv/iris150.csv", csvparam.WithHeader(true),
+				csvparam.WithTransform(transformparam.WithRules(&transformparam.Rules{Rules: []transformparam.Rule{
+					{Name: "known_species", Check: transformparam.AllowedValues, Column: "species", Values: []any{"Iris-setosa"}},
+				}}), transformparam.WithRulesReport(filepath.Join(dir, "TC3.json")))),
+			expectedErr: "data quality rules failed: known_species",
+		},
+		{
+			name: "TC4",
+			src: NewCsvSource("../../testdata/csv/iris9_rejects.csv", csvparam.WithHeader(true),
+				csvparam.WithColumns(param.Columns{
+					{Name: "sepal_length", Type: "DOUBLE"},
+					{Name: "sepal_width", Type: "DOUBLE"},
+					{Name: "petal_length", Type: "DOUBLE"},
+					{Name: "petal_width", Type: "DOUBLE"},
+					{Name: "species", Type: "VARCHAR"},
+				}),
+				csvparam.WithRejects(filepath.Join(dir, "TC4_rejects.csv")),
+				csvparam.WithTransform(transformparam.WithRules(rules), transformparam.WithRulesReport(filepath.Join(dir, "TC4.json")))),
+			expectedRowCount: 7,
+			expectedRejected: 2,
+			expectedFailed:   []string{"range(sepal_length)"},
+		},
+		{
+			name: "TC5",
+			src: NewCsvSource("../../testdata/csv/iris150.csv", csvparam.WithHeader(true),
+				csvparam.WithTransform(transformparam.WithRules(&transformparam.Rules{Rules: []transformparam.Rule{
+					{Check: transformparam.Unique, Column: "specis"},
+				}}))),
+			expectedErr: `invalid rules. error: invalid rule "unique(specis)". error: unknown column "specis" in rule. did you mean "species"?`,
+		},
+	}
+
+	conv, err := newFileconv(context.Background(), "")
+	if err != nil {
+		t.Fatalf("failed getting duckdb client. error: %v", err)
+	}
+	defer conv.Close()
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			output := filepath.Join(dir, tc.name+".parquet")
+			result, err := conv.Convert(context.Background(), tc.src, NewParquetSink(output, pqparam.NewWriteParams()))
+			if tc.expectedErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.expectedErr) {
+					t.Fatalf("expected error: %s but got: %v", tc.expectedErr, err)
+				}
+				if _, err := os.Stat(output); !errors.Is(err, os.ErrNotExist) {
+					t.Fatalf("expected no output: %s. error: %v", output, err)
+				}
+				var qualityErr *QualityError
+				if errors.As(err, &qualityErr) && qualityErr.Report.Passed {
+					t.Fatalf("expected failed quality report")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("failed converting csv to parquet. error: %v", err)
+			}
+
+			if result.RowsWritten != tc.expectedRowCount || result.Quality.Rows != tc.expectedRowCount {
+				t.Fatalf("expected rows written and checked: %d but got: %d and %d", tc.expectedRowCount, result.RowsWritten, result.Quality.Rows)
+			}
+			if result.RowsRejected != tc.expectedRejected {
+				t.Fatalf("expected rows rejected: %d but got: %d", tc.expectedRejected, result.RowsRejected)
+			}
+			if failed := result.Quality.Failed(transformparam.SeverityWarn); !reflect.DeepEqual(failed, tc.expectedFailed) {
+				t.Fatalf("expected failed warn rules: %v but got: %v", tc.expectedFailed, failed)
+			}
+			if _, err := os.Stat(filepath.Join(dir, tc.name+".json")); err != nil {
+				t.Fatalf("expected rules report. error: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,13 @@
+package fileconv
+
+import (
+	"context"
+
+	"github.com/hbbtekademy/go-fileconv/pkg/param/csvparam"
+	"github.com/hbbtekademy/go-fileconv/pkg/param/jsonparam"
+)
+
+// Convert csv files to json files
+func (c *fileconv) Csv2Json(ctx context.Context, srcCsv string, dest string, jsonWriteParams *jsonparam.WriteParams, csvParams ...csvparam.ReadParam) (*ConversionResult, error) {
+	return c.Convert(ctx, NewCsvSource(srcCsv, csvParams...), NewJsonSink(dest, jsonWriteParams))
+}
@@ -0,0 +1,72 @@
+package fileconv
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/hbbtekademy/go-fileconv/pkg/param/csvparam"
+	"github.com/hbbtekademy/go-fileconv/pkg/param/jsonparam"
+)
+
+func TestCsv2Json(t *testing.T) {
+	tests := []struct {
+		name             string
+		jsonWriteParams  []jsonparam.WriteParam
+		csvReadParams    []csvparam.ReadParam
+		inputCsv         string
+		outputJson       string
+		expectedRowCount int
+	}{
+		{
+			name: "TC1",
+			csvReadParams: []csvparam.ReadParam{
+				csvparam.WithHeader(true),
+			},
+			inputCsv:         "../../testdata/csv/iris150.csv",
+			outputJson:       "../../testdata/csv/iris150.json",
+			expectedRowCount: 150,
+		},
+		{
+			name: "TC2",
+			jsonWriteParams: []jsonparam.WriteParam{
+				jsonparam.WithWriteFormat(jsonparam.Array),
+			},
+			csvReadParams: []csvparam.ReadParam{
+				csvparam.WithHeader(false),
+				csvparam.WithNames([]string{"sepal_length", "sepal_width", "petal_length", "petal_width"}),
+			},
+			inputCsv:         "../../testdata/csv/iris150_noheader.csv",
+			outputJson:       "../../testdata/csv/iris150_noheader.json",
+			expectedRowCount: 150,
+		},
+	}
+
+	dbFile := fmt.Sprintf("test_%d.db", time.Now().UnixNano())
+	defer os.RemoveAll(dbFile)
+	defer os.RemoveAll(dbFile + ".wal")
+
+	conv, err := newFileconv(context.Background(), dbFile)
+	if err != nil {
+		t.Fatalf("failed getting duckdb client. error: %v", err)
+	}
+	defer conv.Close()
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := conv.Csv2Json(context.Background(), tc.inputCsv, tc.outputJson,
+				jsonparam.NewWriteParams(tc.jsonWriteParams...), tc.csvReadParams...)
+			if err != nil {
+				t.Fatalf("failed converting csv to json. error: %v", err)
+			}
+
+			err = validateOutput(conv, tc.outputJson, tc.expectedRowCount)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer deleteOutput(tc.outputJson)
+		})
+	}
+}
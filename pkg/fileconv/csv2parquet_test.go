@@ -79,10 +79,11 @@ func TestCsv2Parquet(t *testing.T) {
 	}
 
 	for _, tc := range tests {
-		conv, err := New(context.Background(), "", tc.duckdbConfigs...)
+		conv, err := newFileconv(context.Background(), "", tc.duckdbConfigs...)
 		if err != nil {
 			t.Fatalf("failed getting duckdb client. error: %v", err)
 		}
+		defer conv.Close()
 
 		t.Run(tc.name, func(t *testing.T) {
 			if tc.setup != nil {
@@ -92,7 +93,7 @@ func TestCsv2Parquet(t *testing.T) {
 				}
 			}
 
-			err = conv.Csv2Parquet(context.Background(), tc.inputCsv, tc.outputParquet,
+			_, err = conv.Csv2Parquet(context.Background(), tc.inputCsv, tc.outputParquet,
 				pqparam.NewWriteParams(tc.pqParams...),
 				tc.csvReadParams...)
 			if err != nil {
@@ -134,17 +135,18 @@ column4     | VARCHAR
 
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
-			conv, err := New(context.Background(), "")
+			conv, err := newFileconv(context.Background(), "")
 			if err != nil {
 				t.Fatalf("failed getting file converter. error: %v", err)
 			}
+			defer conv.Close()
 
-			actual, err := conv.describeCsv(context.Background(), tc.inputCsv, csvparam.NewReadParams(tc.csvReadParams...))
+			tableDesc, err := conv.DescribeCsv(context.Background(), tc.inputCsv, tc.csvReadParams...)
 			if err != nil {
 				t.Fatalf("failed getting csv desc. error: %v", err)
 			}
 
-			if actual != tc.expectedDesc {
+			if actual := tableDesc.String(); actual != tc.expectedDesc {
 				t.Fatalf("expected:\n%s\nbut got:\n%s\n", tc.expectedDesc, actual)
 			}
 		})
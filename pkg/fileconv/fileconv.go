@@ -1,3 +1,74 @@
 package fileconv
 
+import (
+	"context"
+	"io"
+
+	"github.com/hbbtekademy/go-fileconv/pkg/model"
+	"github.com/hbbtekademy/go-fileconv/pkg/param/csvparam"
+	"github.com/hbbtekademy/go-fileconv/pkg/param/jsonparam"
+	"github.com/hbbtekademy/go-fileconv/pkg/param/pqparam"
+	"github.com/hbbtekademy/go-fileconv/pkg/param/profileparam"
+)
+
 type DuckDBConfig string
+
+// Converter converts files between the supported formats. Use New to create a Converter.
+// Close must be called to release the underlying DuckDB resources once the Converter is no longer needed.
+type Converter interface {
+	// Convert the source files to the sink format
+	Convert(ctx context.Context, src Source, sink Sink) (*ConversionResult, error)
+
+	// Convert csv files to parquet files
+	Csv2Parquet(ctx context.Context, srcCsv string, dest string, pqWriteParams *pqparam.WriteParams, csvParams ...csvparam.ReadParam) (*ConversionResult, error)
+
+	// Convert json files to parquet files
+	Json2Parquet(ctx context.Context, srcJson string, dest string, pqWriteParams *pqparam.WriteParams, jsonParams ...jsonparam.ReadParam) (*ConversionResult, error)
+
+	// Convert parquet files to csv files
+	Parquet2Csv(ctx context.Context, srcParquet string, dest string, csvWriteParams *csvparam.WriteParams, pqParams ...pqparam.ReadParam) (*ConversionResult, error)
+
+	// Convert parquet files to json files
+	Parquet2Json(ctx context.Context, srcParquet string, dest string, jsonWriteParams *jsonparam.WriteParams, pqParams ...pqparam.ReadParam) (*ConversionResult, error)
+
+	// Convert csv files to json files
+	Csv2Json(ctx context.Context, srcCsv string, dest string, jsonWriteParams *jsonparam.WriteParams, csvParams ...csvparam.ReadParam) (*ConversionResult, error)
+
+	// Convert json files to csv files
+	Json2Csv(ctx context.Context, srcJson string, dest string, csvWriteParams *csvparam.WriteParams, jsonParams ...jsonparam.ReadParam) (*ConversionResult, error)
+
+	// Convert csv read from r to a parquet file written to w
+	Csv2ParquetStream(ctx context.Context, r io.Reader, w io.Writer, pqWriteParams *pqparam.WriteParams, csvParams ...csvparam.ReadParam) (*ConversionResult, error)
+
+	// Convert json read from r to a parquet file written to w
+	Json2ParquetStream(ctx context.Context, r io.Reader, w io.Writer, pqWriteParams *pqparam.WriteParams, jsonParams ...jsonparam.ReadParam) (*ConversionResult, error)
+
+	// Returns the column names and types of the csv files
+	DescribeCsv(ctx context.Context, srcCsv string, csvParams ...csvparam.ReadParam) (*model.TableDesc, error)
+
+	// Returns the column names and types of the json files
+	DescribeJson(ctx context.Context, srcJson string, jsonParams ...jsonparam.ReadParam) (*model.TableDesc, error)
+
+	// Returns the column names and types of the parquet files
+	DescribeParquet(ctx context.Context, srcParquet string, pqParams ...pqparam.ReadParam) (*model.TableDesc, error)
+
+	// Returns the column names, types and statistics of the source rows
+	Profile(ctx context.Context, src Source, params ...profileparam.ProfileParam) (*model.TableProfile, error)
+
+	// Returns the column names and types of the table
+	GetTableDesc(ctx context.Context, table string) (*model.TableDesc, error)
+
+	// Returns the flattened columns of a STRUCT column
+	FlattenStructColumn(ctx context.Context, columnDesc *model.ColumnDesc) ([]*model.ColumnDesc, error)
+
+	// Release the resources held by the Converter
+	Close() error
+}
+
+// Returns a Converter backed by DuckDB.
+// dbFile is the DuckDB database file used for the temporary tables created during the conversions.
+func New(ctx context.Context, dbFile string, duckdbConfigs ...DuckDBConfig) (Converter, error) {
+	return newFileconv(ctx, dbFile, duckdbConfigs...)
+}
+
+var _ Converter = (*fileconv)(nil)
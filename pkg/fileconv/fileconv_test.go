@@ -1,10 +1,15 @@
 package fileconv
 
 import (
+	"bufio"
+	"context"
 	"fmt"
 	"os"
 	"path"
 	"strings"
+
+	"github.com/hbbtekademy/go-fileconv/pkg/param/csvparam"
+	"github.com/hbbtekademy/go-fileconv/pkg/param/pqparam"
 )
 
 const testdataPath = "../../testdata"
@@ -15,9 +20,13 @@ func validateParquetOutput(conv *fileconv, outputParquet, outputPartitionedParqu
 		parquetFile = outputPartitionedParquetRegex
 	}
 
-	rowcount, err := getParquetRowCount(conv, parquetFile)
+	return validateOutput(conv, parquetFile, expectedRowCount)
+}
+
+func validateOutput(conv *fileconv, output string, expectedRowCount int) error {
+	rowcount, err := getRowCount(conv, output)
 	if err != nil {
-		return fmt.Errorf("failed validating parquet rowcount. error: %v", err)
+		return fmt.Errorf("failed validating rowcount. error: %v", err)
 	}
 	if rowcount != expectedRowCount {
 		return fmt.Errorf("expected: %d rows but got: %d", expectedRowCount, rowcount)
@@ -26,6 +35,29 @@ func validateParquetOutput(conv *fileconv, outputParquet, outputPartitionedParqu
 	return nil
 }
 
+func createTestParquet(conv *fileconv, srcCsv, destParquet string) error {
+	err := os.MkdirAll(path.Dir(destParquet), 0700)
+	if err != nil {
+		return err
+	}
+
+	_, err = conv.Csv2Parquet(context.Background(), srcCsv, destParquet,
+		pqparam.NewWriteParams(), csvparam.WithHeader(true))
+	return err
+}
+
+func readFirstLine(file string) (string, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Scan()
+	return scanner.Text(), scanner.Err()
+}
+
 func deleteOutput(outputPath string) error {
 	outputPath = path.Clean(outputPath)
 	if !strings.HasPrefix(outputPath, testdataPath) {
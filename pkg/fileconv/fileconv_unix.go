@@ -16,7 +16,7 @@ type fileconv struct {
 }
 
 // Returns an instance of parquet converter
-func New(ctx context.Context, dbFile string, duckdbConfigs ...DuckDBConfig) (*fileconv, error) {
+func newFileconv(ctx context.Context, dbFile string, duckdbConfigs ...DuckDBConfig) (*fileconv, error) {
 	dbConn, err := duckdb.NewConnector(dbFile, func(execer driver.ExecerContext) error {
 		bootQueries := []string{
 			"INSTALL 'icu'",
@@ -48,6 +48,7 @@ func New(ctx context.Context, dbFile string, duckdbConfigs ...DuckDBConfig) (*fi
 
 	var ver string
 	if err := db.QueryRowContext(ctx, "select version()").Scan(&ver); err != nil {
+		db.Close()
 		return nil, err
 	}
 
@@ -56,6 +57,11 @@ func New(ctx context.Context, dbFile string, duckdbConfigs ...DuckDBConfig) (*fi
 	}, nil
 }
 
+// Close the DuckDB connection
+func (c *fileconv) Close() error {
+	return c.db.Close()
+}
+
 func GetDuckDBVersion() (string, error) {
 	dbConn, err := duckdb.NewConnector("", func(execer driver.ExecerContext) error {
 		return nil
@@ -7,11 +7,11 @@ import (
 	"fmt"
 )
 
-func getParquetRowCount(conv *fileconv, parquetFile string) (int, error) {
+func getRowCount(conv *fileconv, file string) (int, error) {
 	rowcount := 0
 	if err := conv.db.QueryRowContext(context.Background(),
-		fmt.Sprintf("select count(1) from '%s'", parquetFile)).Scan(&rowcount); err != nil {
-		return 0, fmt.Errorf("failed validating parquet rowcount. error: %v", err)
+		fmt.Sprintf("select count(1) from '%s'", file)).Scan(&rowcount); err != nil {
+		return 0, fmt.Errorf("failed getting rowcount. error: %v", err)
 	}
 
 	return rowcount, nil
@@ -12,7 +12,7 @@ type fileconv struct {
 	duckdbSettings []string
 }
 
-func New(ctx context.Context, dbFile string, duckdbConfigs ...DuckDBConfig) (*fileconv, error) {
+func newFileconv(ctx context.Context, dbFile string, duckdbConfigs ...DuckDBConfig) (*fileconv, error) {
 	fconv := &fileconv{
 		dbFile:         dbFile,
 		duckdbSettings: make([]string, 0, len(duckdbConfigs)),
@@ -38,6 +38,11 @@ func New(ctx context.Context, dbFile string, duckdbConfigs ...DuckDBConfig) (*fi
 	return fconv, nil
 }
 
+// Each command runs in its own duckdb cli process, so there is nothing to release
+func (c *fileconv) Close() error {
+	return nil
+}
+
 func GetDuckDBVersion() (string, error) {
 	cmd := exec.Command("duckdb", "--version")
 
@@ -6,7 +6,7 @@ import (
 	"fmt"
 )
 
-func getParquetRowCount(conv *fileconv, parquetFile string) (int, error) {
+func getRowCount(conv *fileconv, file string) (int, error) {
 	type rowcount struct {
 		Count int `json:"count"`
 	}
@@ -14,7 +14,7 @@ func getParquetRowCount(conv *fileconv, parquetFile string) (int, error) {
 	rc := []rowcount{
 		{Count: 0},
 	}
-	stdout, _, err := conv.execDuckDbCli(context.Background(), []string{}, "-json", "-c", fmt.Sprintf("select count(1) as count from '%s'", parquetFile))
+	stdout, _, err := conv.execDuckDbCli(context.Background(), []string{}, "-json", "-c", fmt.Sprintf("select count(1) as count from '%s'", file))
 	if err != nil {
 		return 0, err
 	}
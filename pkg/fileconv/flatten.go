@@ -0,0 +1,317 @@
+package fileconv
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/hbbtekademy/go-fileconv/pkg/model"
+	"github.com/hbbtekademy/go-fileconv/pkg/param"
+	"github.com/hbbtekademy/go-fileconv/pkg/param/jsonparam"
+)
+
+// Index of the element of the array exploded by a flatten step
+const explodeIndex = `"__fileconv_explode_index"`
+
+// Column of a flatten step and the expression selecting it from the previous step
+type flatColumn struct {
+	name     string
+	expr     string
+	dataType *model.DataType
+	depth    int
+	// Set if the column keeps its name from the previous step
+	carried bool
+}
+
+// Returns the query selecting the flattened columns of the json query.
+// STRUCT columns are flattened into a column per field and arrays are kept, exploded or spread by the flatten config.
+// The columns are flattened one nesting level per step, with each step selecting from the query of the previous step.
+func (c *fileconv) getFlattenedTableSelect(ctx context.Context, query string, config *jsonparam.FlattenConfig) (string, error) {
+	if err := config.Validate(); err != nil {
+		return "", fmt.Errorf("invalid flatten config. error: %w", err)
+	}
+
+	tableDesc, err := c.GetTableDesc(ctx, query)
+	if err != nil {
+		return "", fmt.Errorf("failed getting json desc. error: %w", err)
+	}
+
+	cols := make([]*flatColumn, 0, len(tableDesc.ColumnDescs))
+	for _, colDesc := range tableDesc.ColumnDescs {
+		dataType, err := colDesc.ColType.Parse()
+		if err != nil {
+			return "", fmt.Errorf("failed parsing type of column: %s. error: %w", colDesc.ColName, err)
+		}
+		cols = append(cols, &flatColumn{name: colDesc.ColName, dataType: dataType})
+	}
+
+	for {
+		next, explode, changed, err := c.flattenStep(ctx, query, cols, config)
+		if err != nil {
+			return "", err
+		}
+		if !changed {
+			return query, nil
+		}
+
+		if err := resolveCollisions(next, config); err != nil {
+			return "", err
+		}
+
+		selects := make([]string, 0, len(next))
+		for _, col := range next {
+			selects = append(selects, fmt.Sprintf("%s AS %s", col.expr, param.QuoteIdent(col.name)))
+		}
+
+		from := query
+		if explode != "" {
+			// empty and NULL arrays are exploded into a single row with NULL values
+			ident := param.QuoteIdent(explode)
+			from = fmt.Sprintf("SELECT *, UNNEST(CASE WHEN len(%s) > 0 THEN range(1, len(%s) + 1) ELSE [NULL] END) AS %s FROM (%s)",
+				ident, ident, explodeIndex, query)
+		}
+
+		query = fmt.Sprintf("SELECT %s FROM (%s)", strings.Join(selects, ","), from)
+		cols = next
+	}
+}
+
+// Flattens the columns by one nesting level. At most one array is exploded per step.
+// Returns the columns of the next step, the name of the exploded array and false if no column was flattened.
+func (c *fileconv) flattenStep(ctx context.Context, query string, cols []*flatColumn,
+	config *jsonparam.FlattenConfig) ([]*flatColumn, string, bool, error) {
+	sep := config.GetSeparator()
+	next := []*flatColumn{}
+	explode := ""
+	changed := false
+
+	for _, col := range cols {
+		ident := param.QuoteIdent(col.name)
+		carry := &flatColumn{name: col.name, expr: ident, dataType: col.dataType, depth: col.depth, carried: true}
+		if config.GetMaxDepth() >= 0 && col.depth >= config.GetMaxDepth() {
+			next = append(next, carry)
+			continue
+		}
+
+		switch col.dataType.Name {
+		case model.TypeStruct:
+			for _, field := range col.dataType.Fields {
+				next = append(next, &flatColumn{
+					name:     col.name + sep + field.Name,
+					expr:     fmt.Sprintf("struct_extract(%s, %s)", ident, param.QuoteLiteral(field.Name)),
+					dataType: field.Type,
+					depth:    col.depth + 1,
+				})
+			}
+			changed = true
+			continue
+
+		case model.TypeList, model.TypeArray:
+			switch config.GetArrayMode() {
+			case jsonparam.SpreadArrays:
+				size := col.dataType.Size
+				if col.dataType.Name == model.TypeList {
+					n, err := c.queryCount(ctx, fmt.Sprintf("SELECT max(len(%s)) FROM (%s)", ident, query))
+					if err != nil {
+						return nil, "", false, fmt.Errorf("failed getting max length of array: %s. error: %w", col.name, err)
+					}
+					size = int(n)
+				}
+				// arrays which are always empty are spread to a single column, so the column is not dropped
+				for i := 0; i < max(size, 1); i++ {
+					next = append(next, &flatColumn{
+						name:     fmt.Sprintf("%s%s%d", col.name, sep, i),
+						expr:     fmt.Sprintf("%s[%d]", ident, i+1),
+						dataType: col.dataType.Elem,
+						depth:    col.depth + 1,
+					})
+				}
+				changed = true
+				continue
+
+			case jsonparam.ExplodeArrays:
+				changed = true
+				if explode != "" {
+					// exploded in a later step
+					break
+				}
+				explode = col.name
+				next = append(next,
+					&flatColumn{
+						name:     col.name,
+						expr:     fmt.Sprintf("%s[%s]", ident, explodeIndex),
+						dataType: col.dataType.Elem,
+						depth:    col.depth + 1,
+						carried:  true,
+					},
+					&flatColumn{
+						name:     col.name + sep + "index",
+						expr:     explodeIndex + " - 1",
+						dataType: &model.DataType{Name: "BIGINT"},
+						depth:    col.depth + 1,
+					})
+				continue
+			}
+		}
+
+		next = append(next, carry)
+	}
+
+	return next, explode, changed, nil
+}
+
+// Renames or rejects the flattened columns whose names clash with another column.
+// The columns keeping their names from the previous step keep them.
+func resolveCollisions(cols []*flatColumn, config *jsonparam.FlattenConfig) error {
+	names := map[string]bool{}
+	for _, col := range cols {
+		if col.carried {
+			names[strings.ToLower(col.name)] = true
+		}
+	}
+
+	for _, col := range cols {
+		if col.carried {
+			continue
+		}
+
+		name := col.name
+		for i := 1; names[strings.ToLower(name)]; i++ {
+			if config.GetCollision() == jsonparam.CollisionError {
+				return fmt.Errorf("flattened column %q clashes with another column. use the rename collision option to rename it", col.name)
+			}
+			name = fmt.Sprintf("%s%s%d", col.name, config.GetSeparator(), i)
+		}
+		col.name = name
+		names[strings.ToLower(name)] = true
+	}
+
+	return nil
+}
+
+// Key of a nested object rebuilt from flattened columns.
+// Leaf keys hold the column, the other keys hold the nested keys in the order of the columns.
+type nestedKey struct {
+	name   string
+	column string
+	keys   []*nestedKey
+}
+
+// Returns the query selecting the columns of query with the flattened columns nested into STRUCT columns.
+// The column names are split at the flattened names of the groups, or of the prefixes shared by two or more columns if no groups are set.
+func (c *fileconv) getUnflattenedTableSelect(ctx context.Context, query string, config *jsonparam.UnflattenConfig) (string, error) {
+	if err := config.Validate(); err != nil {
+		return "", fmt.Errorf("invalid unflatten config. error: %w", err)
+	}
+
+	tableDesc, err := c.GetTableDesc(ctx, query)
+	if err != nil {
+		return "", fmt.Errorf("failed getting table desc. error: %w", err)
+	}
+
+	columns := make([]string, 0, len(tableDesc.ColumnDescs))
+	for _, colDesc := range tableDesc.ColumnDescs {
+		columns = append(columns, colDesc.ColName)
+	}
+	groups := getUnflattenGroups(columns, config)
+
+	root := &nestedKey{}
+	for _, column := range columns {
+		if err := root.add(unflattenPath(column, config.GetSeparator(), groups), column); err != nil {
+			return "", err
+		}
+	}
+
+	cols := make([]string, 0, len(root.keys))
+	for _, key := range root.keys {
+		cols = append(cols, fmt.Sprintf("%s AS %s", key.expr(), param.QuoteIdent(key.name)))
+	}
+
+	return fmt.Sprintf("SELECT %s FROM (%s)", strings.Join(cols, ","), query), nil
+}
+
+// Returns the groups of the config, or the flattened names of the nested objects shared by two or more of the columns,
+// e.g. address for address_city and address_zip but not first for first_name.
+// Names with leading, trailing or repeated separators share no prefixes.
+func getUnflattenGroups(columns []string, config *jsonparam.UnflattenConfig) []string {
+	if len(config.GetGroups()) > 0 {
+		return config.GetGroups()
+	}
+
+	sep := config.GetSeparator()
+	counts := map[string]int{}
+	for _, column := range columns {
+		keys := strings.Split(column, sep)
+		if slices.Contains(keys, "") {
+			continue
+		}
+		for i := 1; i < len(keys); i++ {
+			counts[strings.Join(keys[:i], sep)]++
+		}
+	}
+
+	groups := []string{}
+	for prefix, count := range counts {
+		if count > 1 {
+			groups = append(groups, prefix)
+		}
+	}
+	slices.Sort(groups)
+	return groups
+}
+
+// Returns the keys of the nested objects of the column followed by the key of the column
+func unflattenPath(column string, sep string, groups []string) []string {
+	// the groups of the column are prefixes of each other, so they are the nested objects in order of length
+	columnGroups := []string{}
+	for _, group := range groups {
+		if strings.HasPrefix(column, group+sep) && len(column) > len(group+sep) {
+			columnGroups = append(columnGroups, group)
+		}
+	}
+	slices.SortFunc(columnGroups, func(a, b string) int { return len(a) - len(b) })
+	columnGroups = slices.Compact(columnGroups)
+
+	path := []string{}
+	start := 0
+	for _, group := range columnGroups {
+		path = append(path, column[start:len(group)])
+		start = len(group) + len(sep)
+	}
+	return append(path, column[start:])
+}
+
+func (k *nestedKey) add(path []string, column string) error {
+	for _, key := range k.keys {
+		if !strings.EqualFold(key.name, path[0]) {
+			continue
+		}
+		if len(path) == 1 || key.column != "" {
+			return fmt.Errorf("column %q clashes with the nested object %q of another column. set the unflatten groups to nest only some columns",
+				column, path[0])
+		}
+		return key.add(path[1:], column)
+	}
+
+	key := &nestedKey{name: path[0]}
+	k.keys = append(k.keys, key)
+	if len(path) == 1 {
+		key.column = column
+		return nil
+	}
+	return key.add(path[1:], column)
+}
+
+// Returns the column of a leaf key or the STRUCT of the nested keys
+func (k *nestedKey) expr() string {
+	if k.column != "" {
+		return param.QuoteIdent(k.column)
+	}
+
+	fields := make([]string, 0, len(k.keys))
+	for _, key := range k.keys {
+		fields = append(fields, fmt.Sprintf("%s: %s", param.QuoteLiteral(key.name), key.expr()))
+	}
+	return fmt.Sprintf("{%s}", strings.Join(fields, ", "))
+}
@@ -0,0 +1,325 @@
+package fileconv
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hbbtekademy/go-fileconv/pkg/param/jsonparam"
+)
+
+func TestGetFlattenedTableSelect(t *testing.T) {
+	nestedTable := `CREATE TABLE %s AS
+SELECT 1 AS id, {'b': 1, 'c': {'d': 'x'}} AS a, [{'e': 1}, {'e': 2}] AS l
+UNION ALL SELECT 2, {'b': 2, 'c': {'d': 'z'}}, []`
+
+	tests := []struct {
+		name            string
+		table           string
+		options         []jsonparam.FlattenOption
+		expectedColumns []string
+		expectedRows    []string
+		expectedErr     string
+	}{
+		{
+			name:            "TC1",
+			table:           nestedTable,
+			expectedColumns: []string{"id", "a_b", "a_c_d", "l"},
+			expectedRows:    []string{"1|1|x|[{'e': 1}, {'e': 2}]", "2|2|z|[]"},
+		},
+		{
+			name:            "TC2",
+			table:           nestedTable,
+			options:         []jsonparam.FlattenOption{jsonparam.WithFlattenSeparator("."), jsonparam.WithFlattenMaxDepth(1)},
+			expectedColumns: []string{"id", "a.b", "a.c", "l"},
+			expectedRows:    []string{"1|1|{'d': x}|[{'e': 1}, {'e': 2}]", "2|2|{'d': z}|[]"},
+		},
+		{
+			name:            "TC3",
+			table:           nestedTable,
+			options:         []jsonparam.FlattenOption{jsonparam.WithArrayMode(jsonparam.ExplodeArrays)},
+			expectedColumns: []string{"id", "a_b", "a_c_d", "l_e", "l_index"},
+			expectedRows:    []string{"1|1|x|1|0", "1|1|x|2|1", "2|2|z|NULL|NULL"},
+		},
+		{
+			name:            "TC4",
+			table:           nestedTable,
+			options:         []jsonparam.FlattenOption{jsonparam.WithArrayMode(jsonparam.SpreadArrays)},
+			expectedColumns: []string{"id", "a_b", "a_c_d", "l_0_e", "l_1_e"},
+			expectedRows:    []string{"1|1|x|1|2", "2|2|z|NULL|NULL"},
+		},
+		{
+			name:        "TC5",
+			table:       `CREATE TABLE %s AS SELECT {'b': 1} AS a, 2 AS a_b`,
+			expectedErr: `flattened column "a_b" clashes with another column. use the rename collision option to rename it`,
+		},
+		{
+			name:            "TC6",
+			table:           `CREATE TABLE %s AS SELECT {'b': 1} AS a, 2 AS a_b`,
+			options:         []jsonparam.FlattenOption{jsonparam.WithCollision(jsonparam.CollisionRename)},
+			expectedColumns: []string{"a_b_1", "a_b"},
+			expectedRows:    []string{"1|2"},
+		},
+		{
+			name:        "TC7",
+			table:       nestedTable,
+			options:     []jsonparam.FlattenOption{jsonparam.WithArrayMode("zip")},
+			expectedErr: "invalid flatten config. error: invalid array mode: zip. must be one of (keep, explode, spread)",
+		},
+	}
+
+	dbFile := fmt.Sprintf("test_%d.db", time.Now().UnixNano())
+	defer os.RemoveAll(dbFile)
+	defer os.RemoveAll(dbFile + ".wal")
+
+	conv, err := newFileconv(context.Background(), dbFile)
+	if err != nil {
+		t.Fatalf("failed getting converter. error: %v", err)
+	}
+	defer conv.Close()
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			tableName := fmt.Sprintf("flatten_%d", time.Now().UnixNano())
+			err := conv.executeCmd(ctx, fmt.Sprintf(tc.table, tableName))
+			if err != nil {
+				t.Fatalf("failed creating table. error: %v", err)
+			}
+			defer conv.dropTable(ctx, tableName)
+
+			query, err := conv.getFlattenedTableSelect(ctx, fmt.Sprintf("SELECT * FROM %s", tableName), jsonparam.NewFlattenConfig(tc.options...))
+			if tc.expectedErr != "" {
+				if err == nil || err.Error() != tc.expectedErr {
+					t.Fatalf("expected error: %s but got: %v", tc.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("failed getting flattened table select. error: %v", err)
+			}
+
+			tableDesc, err := conv.GetTableDesc(ctx, query)
+			if err != nil {
+				t.Fatalf("failed getting table desc. error: %v", err)
+			}
+			columns := []string{}
+			for _, colDesc := range tableDesc.ColumnDescs {
+				columns = append(columns, colDesc.ColName)
+			}
+			if !reflect.DeepEqual(columns, tc.expectedColumns) {
+				t.Fatalf("expected columns: %v but got: %v", tc.expectedColumns, columns)
+			}
+
+			values, err := conv.queryStrings(ctx, fmt.Sprintf("SELECT * FROM (%s) ORDER BY ALL", query), len(columns))
+			if err != nil {
+				t.Fatalf("failed querying flattened table. error: %v", err)
+			}
+			rows := []string{}
+			for _, row := range values {
+				cells := []string{}
+				for _, v := range row {
+					if v == nil {
+						cells = append(cells, "NULL")
+						continue
+					}
+					cells = append(cells, *v)
+				}
+				rows = append(rows, strings.Join(cells, "|"))
+			}
+			if !reflect.DeepEqual(rows, tc.expectedRows) {
+				t.Fatalf("expected rows: %v but got: %v", tc.expectedRows, rows)
+			}
+		})
+	}
+}
+
+func TestUnflattenPath(t *testing.T) {
+	tests := []struct {
+		name         string
+		column       string
+		sep          string
+		groups       []string
+		expectedPath []string
+	}{
+		{name: "TC1", column: "address_geo_lat", sep: "_", groups: []string{"address", "address_geo"}, expectedPath: []string{"address", "geo", "lat"}},
+		{name: "TC2", column: "_id", sep: "_", groups: []string{}, expectedPath: []string{"_id"}},
+		{name: "TC3", column: "address.geo.lat", sep: ".", groups: []string{"address", "address.geo"}, expectedPath: []string{"address", "geo", "lat"}},
+		{name: "TC4", column: "home_address_zip_code", sep: "_", groups: []string{"home_address"}, expectedPath: []string{"home_address", "zip_code"}},
+		{name: "TC5", column: "address_geo_lat", sep: "_", groups: []string{"address_geo", "address"}, expectedPath: []string{"address", "geo", "lat"}},
+		{name: "TC6", column: "first_name", sep: "_", groups: []string{"address"}, expectedPath: []string{"first_name"}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			path := unflattenPath(tc.column, tc.sep, tc.groups)
+			if !reflect.DeepEqual(path, tc.expectedPath) {
+				t.Fatalf("expected: %v but got: %v", tc.expectedPath, path)
+			}
+		})
+	}
+}
+
+func TestGetUnflattenGroups(t *testing.T) {
+	tests := []struct {
+		name           string
+		columns        []string
+		options        []jsonparam.UnflattenOption
+		expectedGroups []string
+	}{
+		{
+			name:           "TC1",
+			columns:        []string{"id", "first_name", "address_city", "address_geo_lat", "address_geo_lon"},
+			expectedGroups: []string{"address", "address_geo"},
+		},
+		{
+			name:           "TC2",
+			columns:        []string{"first_name", "last_name", "address_city"},
+			expectedGroups: []string{},
+		},
+		{
+			name:           "TC3",
+			columns:        []string{"_id", "__v", "a__b", "a__c"},
+			expectedGroups: []string{},
+		},
+		{
+			name:           "TC4",
+			columns:        []string{"address.city", "address.zip", "first_name"},
+			options:        []jsonparam.UnflattenOption{jsonparam.WithUnflattenSeparator(".")},
+			expectedGroups: []string{"address"},
+		},
+		{
+			name:           "TC5",
+			columns:        []string{"address_city", "address_zip"},
+			options:        []jsonparam.UnflattenOption{jsonparam.WithUnflattenGroups("home")},
+			expectedGroups: []string{"home"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			groups := getUnflattenGroups(tc.columns, jsonparam.NewUnflattenConfig(tc.options...))
+			if !reflect.DeepEqual(groups, tc.expectedGroups) {
+				t.Fatalf("expected: %v but got: %v", tc.expectedGroups, groups)
+			}
+		})
+	}
+}
+
+func TestGetUnflattenedTableSelect(t *testing.T) {
+	tests := []struct {
+		name          string
+		table         string
+		options       []jsonparam.UnflattenOption
+		expectedTypes string
+		expectedErr   string
+	}{
+		{
+			name:          "TC1",
+			table:         `CREATE TABLE %s AS SELECT 1 AS id, 'x' AS address_city, 1.5 AS address_geo_lat, 2.5 AS address_geo_lon`,
+			expectedTypes: "id:INTEGER,address:STRUCT(city VARCHAR, geo STRUCT(lat DECIMAL(2,1), lon DECIMAL(2,1)))",
+		},
+		{
+			name:          "TC4",
+			table:         `CREATE TABLE %s AS SELECT 'a' AS first_name, 'b' AS last_name, 'x' AS address_city, 'y' AS address_zip`,
+			expectedTypes: "first_name:VARCHAR,last_name:VARCHAR,address:STRUCT(city VARCHAR, zip VARCHAR)",
+		},
+		{
+			name:          "TC2",
+			table:         `CREATE TABLE %s AS SELECT 'a' AS first_name, 'x' AS address_city, 'y' AS address_zip_code`,
+			options:       []jsonparam.UnflattenOption{jsonparam.WithUnflattenGroups("address")},
+			expectedTypes: "first_name:VARCHAR,address:STRUCT(city VARCHAR, zip_code VARCHAR)",
+		},
+		{
+			name:        "TC3",
+			table:       `CREATE TABLE %s AS SELECT 'x' AS address, 'y' AS address_city, 'z' AS address_zip`,
+			expectedErr: `column "address_city" clashes with the nested object "address" of another column. set the unflatten groups to nest only some columns`,
+		},
+	}
+
+	dbFile := fmt.Sprintf("test_%d.db", time.Now().UnixNano())
+	defer os.RemoveAll(dbFile)
+	defer os.RemoveAll(dbFile + ".wal")
+
+	conv, err := newFileconv(context.Background(), dbFile)
+	if err != nil {
+		t.Fatalf("failed getting converter. error: %v", err)
+	}
+	defer conv.Close()
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			tableName := fmt.Sprintf("unflatten_%d", time.Now().UnixNano())
+			err := conv.executeCmd(ctx, fmt.Sprintf(tc.table, tableName))
+			if err != nil {
+				t.Fatalf("failed creating table. error: %v", err)
+			}
+			defer conv.dropTable(ctx, tableName)
+
+			query, err := conv.getUnflattenedTableSelect(ctx, fmt.Sprintf("SELECT * FROM %s", tableName), jsonparam.NewUnflattenConfig(tc.options...))
+			if tc.expectedErr != "" {
+				if err == nil || err.Error() != tc.expectedErr {
+					t.Fatalf("expected error: %s but got: %v", tc.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("failed getting unflattened table select. error: %v", err)
+			}
+
+			tableDesc, err := conv.GetTableDesc(ctx, query)
+			if err != nil {
+				t.Fatalf("failed getting table desc. error: %v", err)
+			}
+			types := []string{}
+			for _, colDesc := range tableDesc.ColumnDescs {
+				types = append(types, fmt.Sprintf("%s:%s", colDesc.ColName, colDesc.ColType))
+			}
+			if strings.Join(types, ",") != tc.expectedTypes {
+				t.Fatalf("expected: %s but got: %s", tc.expectedTypes, strings.Join(types, ","))
+			}
+		})
+	}
+}
+
+func TestFlattenRoundTrip(t *testing.T) {
+	dbFile := fmt.Sprintf("test_%d.db", time.Now().UnixNano())
+	defer os.RemoveAll(dbFile)
+	defer os.RemoveAll(dbFile + ".wal")
+
+	conv, err := newFileconv(context.Background(), dbFile)
+	if err != nil {
+		t.Fatalf("failed getting converter. error: %v", err)
+	}
+	defer conv.Close()
+
+	ctx := context.Background()
+	tableName := fmt.Sprintf("roundtrip_%d", time.Now().UnixNano())
+	err = conv.executeCmd(ctx, fmt.Sprintf(`CREATE TABLE %s AS SELECT 1 AS id, 'a' AS first_name, {'city': 'x', 'geo': {'lat': 1.5, 'lon': 2.5}} AS address, [1, 2] AS tags`, tableName))
+	if err != nil {
+		t.Fatalf("failed creating table. error: %v", err)
+	}
+	defer conv.dropTable(ctx, tableName)
+
+	flattened, err := conv.getFlattenedTableSelect(ctx, fmt.Sprintf("SELECT * FROM %s", tableName), jsonparam.NewFlattenConfig())
+	if err != nil {
+		t.Fatalf("failed getting flattened table select. error: %v", err)
+	}
+	unflattened, err := conv.getUnflattenedTableSelect(ctx, flattened, jsonparam.NewUnflattenConfig())
+	if err != nil {
+		t.Fatalf("failed getting unflattened table select. error: %v", err)
+	}
+
+	count, err := conv.queryCount(ctx, fmt.Sprintf("SELECT count(*) FROM (SELECT * FROM %s EXCEPT SELECT * FROM (%s))", tableName, unflattened))
+	if err != nil {
+		t.Fatalf("failed comparing tables. error: %v", err)
+	}
+	if count != 0 {
+		t.Fatalf("expected the unflattened table to match the original table but got %d different rows", count)
+	}
+}
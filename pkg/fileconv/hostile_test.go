@@ -0,0 +1,101 @@
+package fileconv
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/hbbtekademy/go-fileconv/pkg/param"
+	"github.com/hbbtekademy/go-fileconv/pkg/param/csvparam"
+	"github.com/hbbtekademy/go-fileconv/pkg/param/pqparam"
+)
+
+func TestHostileNames(t *testing.T) {
+	tests := []struct {
+		name             string
+		inputCsv         string
+		content          string
+		csvReadParams    []csvparam.ReadParam
+		outputParquet    string
+		pqWriteParams    []pqparam.WriteParam
+		expectedRowCount int
+	}{
+		{
+			name:             "TC1",
+			inputCsv:         "../../testdata/hostile/o'brien.csv",
+			content:          "select,from\n1,a\n2,b\n",
+			csvReadParams:    []csvparam.ReadParam{csvparam.WithHeader(true)},
+			outputParquet:    "../../testdata/hostile/o'brien.parquet",
+			expectedRowCount: 2,
+		},
+		{
+			name:     "TC2",
+			inputCsv: "../../testdata/hostile/x'); DROP TABLE t; --.csv",
+			content:  "a\"b,c'd\n1,it's\n2,NULL'\n",
+			csvReadParams: []csvparam.ReadParam{
+				csvparam.WithHeader(true),
+				csvparam.WithNullStrings([]string{"NULL'"}),
+				csvparam.WithTypes(param.Columns{{Name: "c'd", Type: "VARCHAR"}}),
+			},
+			outputParquet:    "../../testdata/hostile/x'); DROP TABLE t; --.parquet",
+			expectedRowCount: 2,
+		},
+		{
+			name:     "TC3",
+			inputCsv: "../../testdata/hostile/partitioned.csv",
+			content:  "select,\"group\"\n1,x\n2,y\n",
+			csvReadParams: []csvparam.ReadParam{
+				csvparam.WithHeader(true),
+			},
+			outputParquet: "../../testdata/hostile/partitioned",
+			pqWriteParams: []pqparam.WriteParam{
+				pqparam.WithHivePartitionConfig(pqparam.WithPartitionBy("group")),
+			},
+			expectedRowCount: 2,
+		},
+	}
+
+	dbFile := fmt.Sprintf("test_%d.db", time.Now().UnixNano())
+	defer os.RemoveAll(dbFile)
+	defer os.RemoveAll(dbFile + ".wal")
+
+	conv, err := newFileconv(context.Background(), dbFile)
+	if err != nil {
+		t.Fatalf("failed getting duckdb client. error: %v", err)
+	}
+	defer conv.Close()
+
+	err = os.MkdirAll("../../testdata/hostile", 0755)
+	if err != nil {
+		t.Fatalf("failed creating test dir. error: %v", err)
+	}
+	defer deleteOutput("../../testdata/hostile")
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := os.WriteFile(tc.inputCsv, []byte(tc.content), 0644)
+			if err != nil {
+				t.Fatalf("failed writing test csv. error: %v", err)
+			}
+
+			tableDesc, err := conv.DescribeCsv(context.Background(), tc.inputCsv, tc.csvReadParams...)
+			if err != nil {
+				t.Fatalf("failed describing csv. error: %v", err)
+			}
+
+			result, err := conv.Csv2Parquet(context.Background(), tc.inputCsv, tc.outputParquet,
+				pqparam.NewWriteParams(tc.pqWriteParams...), tc.csvReadParams...)
+			if err != nil {
+				t.Fatalf("failed converting csv to parquet. error: %v", err)
+			}
+			if result.RowsWritten != int64(tc.expectedRowCount) {
+				t.Fatalf("expected rows written: %d but got: %d", tc.expectedRowCount, result.RowsWritten)
+			}
+			if len(result.Schema.ColumnDescs) != len(tableDesc.ColumnDescs) {
+				t.Fatalf("expected schema: %v but got: %v", tableDesc, result.Schema)
+			}
+		})
+	}
+}
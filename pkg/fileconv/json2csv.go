@@ -0,0 +1,17 @@
+package fileconv
+
+import (
+	"context"
+
+	"github.com/hbbtekademy/go-fileconv/pkg/param/csvparam"
+	"github.com/hbbtekademy/go-fileconv/pkg/param/jsonparam"
+)
+
+// Convert json files to csv files.
+// Nested json is always flattened, e.g. {"a": {"b": {"c": 1}}} is written to the a_b_c column.
+func (c *fileconv) Json2Csv(ctx context.Context, srcJson string, dest string, csvWriteParams *csvparam.WriteParams, jsonParams ...jsonparam.ReadParam) (*ConversionResult, error) {
+	src := newJsonSource(srcJson, jsonParams...)
+	jsonparam.WithFlatten(true)(src.params)
+
+	return c.Convert(ctx, src, NewCsvSink(dest, csvWriteParams))
+}
@@ -0,0 +1,76 @@
+package fileconv
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/hbbtekademy/go-fileconv/pkg/param/csvparam"
+	"github.com/hbbtekademy/go-fileconv/pkg/param/jsonparam"
+)
+
+func TestJson2Csv(t *testing.T) {
+	tests := []struct {
+		name             string
+		csvWriteParams   []csvparam.WriteParam
+		jsonReadParams   []jsonparam.ReadParam
+		inputJson        string
+		outputCsv        string
+		expectedRowCount int
+		expectedHeader   string
+	}{
+		{
+			name:             "TC1",
+			inputJson:        "../../testdata/json/iris150.json",
+			outputCsv:        "../../testdata/json/iris150.csv",
+			expectedRowCount: 150,
+			expectedHeader:   "sepalLength,sepalWidth,petalLength,petalWidth,species",
+		},
+		{
+			name: "TC2",
+			csvWriteParams: []csvparam.WriteParam{
+				csvparam.WithWriteDelim("|"),
+			},
+			inputJson:        "../../testdata/json/nested.json",
+			outputCsv:        "../../testdata/json/nested.csv",
+			expectedRowCount: 1,
+			expectedHeader:   "a1|a2_b1|a2_b2_c1|a2_b2_c2|a2_b2_c3_d1|a2_b3_d1|a2_b3_d2|a3|a4_b1",
+		},
+	}
+
+	dbFile := fmt.Sprintf("test_%d.db", time.Now().UnixNano())
+	defer os.RemoveAll(dbFile)
+	defer os.RemoveAll(dbFile + ".wal")
+
+	conv, err := newFileconv(context.Background(), dbFile)
+	if err != nil {
+		t.Fatalf("failed getting duckdb client. error: %v", err)
+	}
+	defer conv.Close()
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := conv.Json2Csv(context.Background(), tc.inputJson, tc.outputCsv,
+				csvparam.NewWriteParams(tc.csvWriteParams...), tc.jsonReadParams...)
+			if err != nil {
+				t.Fatalf("failed converting json to csv. error: %v", err)
+			}
+			defer deleteOutput(tc.outputCsv)
+
+			err = validateOutput(conv, tc.outputCsv, tc.expectedRowCount)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			header, err := readFirstLine(tc.outputCsv)
+			if err != nil {
+				t.Fatalf("failed reading csv header. error: %v", err)
+			}
+			if header != tc.expectedHeader {
+				t.Fatalf("expected header: %s but got: %s", tc.expectedHeader, header)
+			}
+		})
+	}
+}
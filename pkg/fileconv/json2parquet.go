@@ -6,74 +6,24 @@ import (
 	"strings"
 	"time"
 
+	"github.com/hbbtekademy/go-fileconv/pkg/model"
+	"github.com/hbbtekademy/go-fileconv/pkg/param"
 	"github.com/hbbtekademy/go-fileconv/pkg/param/jsonparam"
 	"github.com/hbbtekademy/go-fileconv/pkg/param/pqparam"
 )
 
 // Convert json files to parquet files
-func (c *fileconv) Json2Parquet(ctx context.Context, srcJson string, dest string, pqWriteParams *pqparam.WriteParams, jsonParams ...jsonparam.ReadParam) error {
-	jsonReadParams := jsonparam.NewReadParams(jsonParams...)
-
-	if jsonReadParams.GetDescribe() {
-		desc, err := c.describeJson(ctx, srcJson, jsonReadParams)
-		if err != nil {
-			return err
-		}
-
-		fmt.Println(desc)
-		return nil
-	}
-
-	if !jsonReadParams.GetFlatten() {
-		err := c.executeCmd(ctx, fmt.Sprintf(`
-		COPY (
-			SELECT * FROM read_json('%s' %s)
-		) TO '%s' %s`,
-			srcJson,
-			jsonReadParams.Params(),
-			dest,
-			pqWriteParams.Params()))
-		if err != nil {
-			return fmt.Errorf("failed converting json to parquet. error: %w", err)
-		}
-
-		return nil
-	}
-
-	// Flatten json and export
-
-	jsonTableName, err := c.ImportJson(ctx, srcJson, jsonReadParams, 0)
-	if err != nil {
-		return fmt.Errorf("failed importing json. error: %w", err)
-	}
-	defer c.dropTable(ctx, jsonTableName)
-
-	flattendTableSelect, err := c.getFlattenedTableSelect(ctx, jsonTableName)
-	if err != nil {
-		return fmt.Errorf("failed getting flattend table. error: %w", err)
-	}
-
-	err = c.executeCmd(ctx, fmt.Sprintf(`
-		COPY (
-			%s
-		) TO '%s' %s`,
-		flattendTableSelect,
-		dest,
-		pqWriteParams.Params()))
-	if err != nil {
-		return fmt.Errorf("failed converting flattened json to parquet. error: %w", err)
-	}
-
-	return nil
+func (c *fileconv) Json2Parquet(ctx context.Context, srcJson string, dest string, pqWriteParams *pqparam.WriteParams, jsonParams ...jsonparam.ReadParam) (*ConversionResult, error) {
+	return c.Convert(ctx, NewJsonSource(srcJson, jsonParams...), NewParquetSink(dest, pqWriteParams))
 }
 
-func (c *fileconv) ImportJson(ctx context.Context, srcJson string, jsonReadParams *jsonparam.ReadParams, sampleSize uint64) (string, error) {
+func (c *fileconv) importJson(ctx context.Context, srcJson string, jsonReadParams *jsonparam.ReadParams, sampleSize uint64) (string, error) {
 	tableName := fmt.Sprintf("tmp_%d", time.Now().UnixNano())
 
 	var sb strings.Builder
-	sb.WriteString(fmt.Sprintf(`CREATE TABLE %s AS SELECT * FROM read_json('%s' %s)`,
-		tableName,
-		srcJson,
+	sb.WriteString(fmt.Sprintf(`CREATE TABLE %s AS SELECT * FROM read_json(%s %s)`,
+		param.QuoteIdent(tableName),
+		param.QuoteLiteral(srcJson),
 		jsonReadParams.Params()))
 
 	if sampleSize > 0 {
@@ -88,36 +38,82 @@ func (c *fileconv) ImportJson(ctx context.Context, srcJson string, jsonReadParam
 	return tableName, nil
 }
 
-func (c *fileconv) describeJson(ctx context.Context, srcJson string, jsonReadParams *jsonparam.ReadParams) (string, error) {
-	if !jsonReadParams.GetFlatten() {
-		table := fmt.Sprintf(`SELECT * FROM read_json('%s' %s) USING SAMPLE %d`,
-			srcJson,
+// Returns the column names and types of the json files.
+// If the flatten param is set, the columns of the flattened json are returned.
+func (c *fileconv) DescribeJson(ctx context.Context, srcJson string, jsonParams ...jsonparam.ReadParam) (*model.TableDesc, error) {
+	return c.describeJson(ctx, srcJson, jsonparam.NewReadParams(jsonParams...))
+}
+
+func (c *fileconv) describeJson(ctx context.Context, srcJson string, jsonReadParams *jsonparam.ReadParams) (*model.TableDesc, error) {
+	if !jsonReadParams.GetFlatten() && jsonReadParams.GetRecordsPath() == "" {
+		table := fmt.Sprintf(`SELECT * FROM read_json(%s %s) USING SAMPLE %d`,
+			param.QuoteLiteral(srcJson),
 			jsonReadParams.Params(),
 			jsonReadParams.GetSampleSize())
 
 		tableDesc, err := c.GetTableDesc(ctx, table)
 		if err != nil {
-			return "", fmt.Errorf("failed getting json desc. error: %v", err)
+			return nil, fmt.Errorf("failed getting json desc. error: %v", err)
 		}
 
-		return tableDesc.String(), nil
+		return tableDesc, nil
 	}
 
-	jsonTableName, err := c.ImportJson(ctx, srcJson, jsonReadParams, jsonReadParams.GetSampleSize())
+	jsonSelect, release, err := c.getJsonSelect(ctx, srcJson, jsonReadParams, jsonReadParams.GetSampleSize())
 	if err != nil {
-		return "", fmt.Errorf("failed importing json. error: %w", err)
+		return nil, err
 	}
-	defer c.dropTable(ctx, jsonTableName)
+	defer release()
 
-	flattendTableSelect, err := c.getFlattenedTableSelect(ctx, jsonTableName)
+	tableDesc, err := c.GetTableDesc(ctx, jsonSelect)
 	if err != nil {
-		return "", fmt.Errorf("failed getting flattend table. error: %w", err)
+		return nil, fmt.Errorf("failed getting json desc. error: %v", err)
 	}
 
-	tableDesc, err := c.GetTableDesc(ctx, flattendTableSelect)
-	if err != nil {
-		return "", fmt.Errorf("failed getting json desc. error: %v", err)
+	return tableDesc, nil
+}
+
+// Returns the query selecting the rows of the json files, with the records at the records path and flattened if the params are set.
+// Flattened json is imported into a table with sampleSize rows, or all rows if 0, which is dropped by the returned func.
+func (c *fileconv) getJsonSelect(ctx context.Context, srcJson string, jsonReadParams *jsonparam.ReadParams, sampleSize uint64) (string, func(), error) {
+	// each document holding records is read as a single json value, so the maximum object size is raised to the largest document
+	if jsonReadParams.GetRecordsPath() != "" {
+		if size := getMaxDocumentSize(srcJson, jsonReadParams.GetCompression()); size > jsonReadParams.GetMaxObjSize() {
+			params := *jsonReadParams
+			jsonparam.WithMaxObjSize(size)(&params)
+			jsonReadParams = &params
+		}
+	}
+
+	query := fmt.Sprintf("SELECT * FROM read_json(%s %s)", param.QuoteLiteral(srcJson), jsonReadParams.Params())
+	release := func() {}
+
+	if jsonReadParams.GetFlatten() {
+		jsonTableName, err := c.importJson(ctx, srcJson, jsonReadParams, sampleSize)
+		if err != nil {
+			return "", nil, fmt.Errorf("failed importing json. error: %w", err)
+		}
+		release = func() { c.dropTable(ctx, jsonTableName) }
+		query = fmt.Sprintf("SELECT * FROM %s", param.QuoteIdent(jsonTableName))
+	}
+
+	if jsonReadParams.GetRecordsPath() != "" {
+		var err error
+		query, err = c.getRecordsSelect(ctx, query, jsonReadParams.GetRecordsPath(), jsonReadParams.GetRecordsCarry())
+		if err != nil {
+			release()
+			return "", nil, fmt.Errorf("failed getting records. error: %w", err)
+		}
+	}
+
+	if jsonReadParams.GetFlatten() {
+		var err error
+		query, err = c.getFlattenedTableSelect(ctx, query, jsonReadParams.GetFlattenConfig())
+		if err != nil {
+			release()
+			return "", nil, fmt.Errorf("failed getting flattend table. error: %w", err)
+		}
 	}
 
-	return tableDesc.String(), nil
+	return query, release, nil
 }
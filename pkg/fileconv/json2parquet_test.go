@@ -95,10 +95,11 @@ func TestJson2Parquet(t *testing.T) {
 			defer os.RemoveAll(dbFile)
 			defer os.RemoveAll(dbFile + ".wal")
 
-			conv, err := New(context.Background(), dbFile, tc.duckdbConfigs...)
+			conv, err := newFileconv(context.Background(), dbFile, tc.duckdbConfigs...)
 			if err != nil {
 				t.Fatalf("failed getting duckdb client. error: %v", err)
 			}
+			defer conv.Close()
 
 			if tc.setup != nil {
 				err := tc.setup()
@@ -107,7 +108,7 @@ func TestJson2Parquet(t *testing.T) {
 				}
 			}
 
-			err = conv.Json2Parquet(context.Background(), tc.inputJson, tc.outputParquet,
+			_, err = conv.Json2Parquet(context.Background(), tc.inputJson, tc.outputParquet,
 				pqparam.NewWriteParams(tc.pqParams...), tc.jsonReadParams...)
 			if err != nil {
 				t.Fatalf("failed converting json to parquet. error: %v", err)
@@ -171,17 +172,18 @@ a4     | STRUCT(b1 VARCHAR)
 			defer os.RemoveAll(dbFile)
 			defer os.RemoveAll(dbFile + ".wal")
 
-			conv, err := New(context.Background(), dbFile)
+			conv, err := newFileconv(context.Background(), dbFile)
 			if err != nil {
 				t.Fatalf("failed getting duckdb client. error: %v", err)
 			}
+			defer conv.Close()
 
-			actual, err := conv.describeJson(context.Background(), tc.inputJson, jsonparam.NewReadParams(tc.jsonReadParams...))
+			tableDesc, err := conv.DescribeJson(context.Background(), tc.inputJson, tc.jsonReadParams...)
 			if err != nil {
 				t.Fatalf("failed getting json desc. error: %v", err)
 			}
 
-			if actual != tc.expectedDesc {
+			if actual := tableDesc.String(); actual != tc.expectedDesc {
 				t.Fatalf("expected:\n%s\nbut got:\n%s\n", tc.expectedDesc, actual)
 			}
 		})
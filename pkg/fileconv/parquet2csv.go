@@ -0,0 +1,34 @@
+package fileconv
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hbbtekademy/go-fileconv/pkg/model"
+	"github.com/hbbtekademy/go-fileconv/pkg/param"
+	"github.com/hbbtekademy/go-fileconv/pkg/param/csvparam"
+	"github.com/hbbtekademy/go-fileconv/pkg/param/pqparam"
+)
+
+// Convert parquet files to csv files
+func (c *fileconv) Parquet2Csv(ctx context.Context, srcParquet string, dest string, csvWriteParams *csvparam.WriteParams, pqParams ...pqparam.ReadParam) (*ConversionResult, error) {
+	return c.Convert(ctx, NewParquetSource(srcParquet, pqParams...), NewCsvSink(dest, csvWriteParams))
+}
+
+// Returns the column names and types of the parquet files
+func (c *fileconv) DescribeParquet(ctx context.Context, srcParquet string, pqParams ...pqparam.ReadParam) (*model.TableDesc, error) {
+	return c.describeParquet(ctx, srcParquet, pqparam.NewReadParams(pqParams...))
+}
+
+func (c *fileconv) describeParquet(ctx context.Context, srcParquet string, pqReadParams *pqparam.ReadParams) (*model.TableDesc, error) {
+	table := fmt.Sprintf(`SELECT * FROM read_parquet(%s %s)`,
+		param.QuoteLiteral(srcParquet),
+		pqReadParams.Params())
+
+	tableDesc, err := c.GetTableDesc(ctx, table)
+	if err != nil {
+		return nil, fmt.Errorf("failed getting parquet desc. error: %v", err)
+	}
+
+	return tableDesc, nil
+}
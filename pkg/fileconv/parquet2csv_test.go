@@ -0,0 +1,78 @@
+package fileconv
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/hbbtekademy/go-fileconv/pkg/param"
+	"github.com/hbbtekademy/go-fileconv/pkg/param/csvparam"
+	"github.com/hbbtekademy/go-fileconv/pkg/param/pqparam"
+)
+
+func TestParquet2Csv(t *testing.T) {
+	tests := []struct {
+		name             string
+		csvWriteParams   []csvparam.WriteParam
+		pqReadParams     []pqparam.ReadParam
+		inputParquet     string
+		outputCsv        string
+		expectedRowCount int
+	}{
+		{
+			name:             "TC1",
+			inputParquet:     "../../testdata/parquet/iris150.parquet",
+			outputCsv:        "../../testdata/parquet/iris150.csv",
+			expectedRowCount: 150,
+		},
+		{
+			name: "TC2",
+			csvWriteParams: []csvparam.WriteParam{
+				csvparam.WithWriteDelim("|"),
+				csvparam.WithWriteNullStr("NULL"),
+				csvparam.WithForceQuote("species"),
+				csvparam.WithWriteCompression(param.Gzip),
+			},
+			pqReadParams: []pqparam.ReadParam{
+				pqparam.WithFilename(true),
+				pqparam.WithFileRowNum(true),
+			},
+			inputParquet:     "../../testdata/parquet/iris150.parquet",
+			outputCsv:        "../../testdata/parquet/iris150.csv.gz",
+			expectedRowCount: 150,
+		},
+	}
+
+	dbFile := fmt.Sprintf("test_%d.db", time.Now().UnixNano())
+	defer os.RemoveAll(dbFile)
+	defer os.RemoveAll(dbFile + ".wal")
+
+	conv, err := newFileconv(context.Background(), dbFile)
+	if err != nil {
+		t.Fatalf("failed getting duckdb client. error: %v", err)
+	}
+	defer conv.Close()
+
+	err = createTestParquet(conv, "../../testdata/csv/iris150.csv", "../../testdata/parquet/iris150.parquet")
+	if err != nil {
+		t.Fatalf("failed creating test parquet. error: %v", err)
+	}
+	defer deleteOutput("../../testdata/parquet")
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := conv.Parquet2Csv(context.Background(), tc.inputParquet, tc.outputCsv,
+				csvparam.NewWriteParams(tc.csvWriteParams...), tc.pqReadParams...)
+			if err != nil {
+				t.Fatalf("failed converting parquet to csv. error: %v", err)
+			}
+
+			err = validateOutput(conv, tc.outputCsv, tc.expectedRowCount)
+			if err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package fileconv
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hbbtekademy/go-fileconv/pkg/param"
+	"github.com/hbbtekademy/go-fileconv/pkg/param/jsonparam"
+	"github.com/hbbtekademy/go-fileconv/pkg/param/pqparam"
+)
+
+// Convert parquet files to json files
+func (c *fileconv) Parquet2Json(ctx context.Context, srcParquet string, dest string, jsonWriteParams *jsonparam.WriteParams, pqParams ...pqparam.ReadParam) (*ConversionResult, error) {
+	return c.Convert(ctx, NewParquetSource(srcParquet, pqParams...), NewJsonSink(dest, jsonWriteParams))
+}
+
+// Returns a select on the table with all STRUCT, LIST and MAP columns converted to json strings
+func (c *fileconv) getNestedAsStringTableSelect(ctx context.Context, table string) (string, error) {
+	tableDesc, err := c.GetTableDesc(ctx, table)
+	if err != nil {
+		return "", fmt.Errorf("failed getting table desc. error: %w", err)
+	}
+
+	cols := make([]string, 0, len(tableDesc.ColumnDescs))
+	for _, colDesc := range tableDesc.ColumnDescs {
+		col := param.QuoteIdent(colDesc.ColName)
+		if colDesc.ColType.IsNested() {
+			cols = append(cols, fmt.Sprintf("CAST(to_json(%s) AS VARCHAR) AS %s", col, col))
+			continue
+		}
+		cols = append(cols, col)
+	}
+
+	return fmt.Sprintf("SELECT %s FROM (%s)", strings.Join(cols, ","), table), nil
+}
@@ -0,0 +1,96 @@
+package fileconv
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/hbbtekademy/go-fileconv/pkg/param"
+	"github.com/hbbtekademy/go-fileconv/pkg/param/jsonparam"
+	"github.com/hbbtekademy/go-fileconv/pkg/param/pqparam"
+)
+
+func TestParquet2Json(t *testing.T) {
+	tests := []struct {
+		name             string
+		jsonWriteParams  []jsonparam.WriteParam
+		pqReadParams     []pqparam.ReadParam
+		inputParquet     string
+		outputJson       string
+		expectedRowCount int
+	}{
+		{
+			name:             "TC1",
+			inputParquet:     "../../testdata/parquet/iris150.parquet",
+			outputJson:       "../../testdata/parquet/iris150.json",
+			expectedRowCount: 150,
+		},
+		{
+			name: "TC2",
+			jsonWriteParams: []jsonparam.WriteParam{
+				jsonparam.WithWriteFormat(jsonparam.Array),
+				jsonparam.WithWriteCompression(param.Gzip),
+			},
+			pqReadParams: []pqparam.ReadParam{
+				pqparam.WithFileRowNum(true),
+			},
+			inputParquet:     "../../testdata/parquet/iris150.parquet",
+			outputJson:       "../../testdata/parquet/iris150.json.gz",
+			expectedRowCount: 150,
+		},
+		{
+			name:             "TC3",
+			inputParquet:     "../../testdata/parquet/nested.parquet",
+			outputJson:       "../../testdata/parquet/nested.json",
+			expectedRowCount: 1,
+		},
+		{
+			name: "TC4",
+			jsonWriteParams: []jsonparam.WriteParam{
+				jsonparam.WithNestedAsString(true),
+			},
+			inputParquet:     "../../testdata/parquet/nested.parquet",
+			outputJson:       "../../testdata/parquet/nested_str.json",
+			expectedRowCount: 1,
+		},
+	}
+
+	dbFile := fmt.Sprintf("test_%d.db", time.Now().UnixNano())
+	defer os.RemoveAll(dbFile)
+	defer os.RemoveAll(dbFile + ".wal")
+
+	conv, err := newFileconv(context.Background(), dbFile)
+	if err != nil {
+		t.Fatalf("failed getting duckdb client. error: %v", err)
+	}
+	defer conv.Close()
+
+	err = createTestParquet(conv, "../../testdata/csv/iris150.csv", "../../testdata/parquet/iris150.parquet")
+	if err != nil {
+		t.Fatalf("failed creating test parquet. error: %v", err)
+	}
+	defer deleteOutput("../../testdata/parquet")
+
+	_, err = conv.Json2Parquet(context.Background(), "../../testdata/json/nested.json", "../../testdata/parquet/nested.parquet",
+		pqparam.NewWriteParams())
+	if err != nil {
+		t.Fatalf("failed creating nested test parquet. error: %v", err)
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := conv.Parquet2Json(context.Background(), tc.inputParquet, tc.outputJson,
+				jsonparam.NewWriteParams(tc.jsonWriteParams...), tc.pqReadParams...)
+			if err != nil {
+				t.Fatalf("failed converting parquet to json. error: %v", err)
+			}
+
+			err = validateOutput(conv, tc.outputJson, tc.expectedRowCount)
+			if err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
@@ -0,0 +1,214 @@
+package fileconv
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hbbtekademy/go-fileconv/pkg/model"
+	"github.com/hbbtekademy/go-fileconv/pkg/param"
+	"github.com/hbbtekademy/go-fileconv/pkg/param/profileparam"
+)
+
+// Returns the column names, types and statistics of the rows of the source, after the transformations of the source are applied.
+// The rows are staged to a table which is scanned once for the statistics and once per column for the top values.
+func (c *fileconv) Profile(ctx context.Context, src Source, params ...profileparam.ProfileParam) (*model.TableProfile, error) {
+	profileParams := profileparam.NewProfileParams(params...)
+
+	src, releaseSrc, err := openSource(ctx, src)
+	if err != nil {
+		return nil, fmt.Errorf("failed opening %s source. error: %w", src.Format(), err)
+	}
+	defer releaseSrc()
+
+	query, release, err := src.query(ctx, c)
+	if err != nil {
+		return nil, fmt.Errorf("failed getting %s source query. error: %w", src.Format(), err)
+	}
+	defer release()
+
+	query, tableDesc, err := c.applyTransform(ctx, query, src.getTransform())
+	if err != nil {
+		return nil, err
+	}
+
+	cmd, table := stageCmd(query)
+	err = c.executeCmd(ctx, cmd)
+	if err != nil {
+		return nil, fmt.Errorf("failed staging %s rows. error: %w", src.Format(), err)
+	}
+	defer c.dropTable(context.WithoutCancel(ctx), table)
+
+	profile, err := c.getColumnStats(ctx, table, tableDesc)
+	if err != nil {
+		return nil, fmt.Errorf("failed getting column stats. error: %w", err)
+	}
+
+	if profileParams.GetTopK() > 0 {
+		for _, col := range profile.Columns {
+			col.TopValues, err = c.getTopValues(ctx, table, col.ColName, profileParams.GetTopK())
+			if err != nil {
+				return nil, fmt.Errorf("failed getting top values of column %q. error: %w", col.ColName, err)
+			}
+		}
+	}
+
+	return profile, nil
+}
+
+// Returns the profile of the columns of table without the top values
+func (c *fileconv) getColumnStats(ctx context.Context, table string, tableDesc *model.TableDesc) (*model.TableProfile, error) {
+	exprs := []string{"count(*)"}
+	for _, colDesc := range tableDesc.ColumnDescs {
+		exprs = append(exprs, columnStatsExprs(colDesc)...)
+	}
+
+	rows, err := c.queryStrings(ctx, fmt.Sprintf("SELECT %s FROM %s", strings.Join(exprs, ","), param.QuoteIdent(table)), len(exprs))
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) != 1 {
+		return nil, fmt.Errorf("expected 1 row of stats but got: %d", len(rows))
+	}
+
+	stats := &statsParser{values: rows[0]}
+	profile := &model.TableProfile{
+		Rows:    stats.int(),
+		Columns: make([]*model.ColumnProfile, 0, len(tableDesc.ColumnDescs)),
+	}
+	for _, colDesc := range tableDesc.ColumnDescs {
+		col := &model.ColumnProfile{
+			ColumnDesc:    *colDesc,
+			NullCount:     stats.int(),
+			DistinctCount: stats.int(),
+			Min:           stats.string(),
+			Max:           stats.string(),
+			Mean:          stats.float(),
+			StdDev:        stats.float(),
+		}
+		length := &model.LengthStats{
+			Min:    stats.int(),
+			P25:    stats.int(),
+			Median: stats.int(),
+			P75:    stats.int(),
+			Max:    stats.int(),
+		}
+		mean := stats.float()
+		if colDesc.ColType.IsString() && mean != nil {
+			length.Mean = *mean
+			col.Length = length
+		}
+		profile.Columns = append(profile.Columns, col)
+	}
+
+	if stats.err != nil {
+		return nil, stats.err
+	}
+
+	return profile, nil
+}
+
+// Returns the expressions selecting the statistics of the column.
+// Statistics which do not apply to the column type are selected as NULL.
+func columnStatsExprs(colDesc *model.ColumnDesc) []string {
+	col := param.QuoteIdent(colDesc.ColName)
+	colType := colDesc.ColType
+
+	exprs := []string{fmt.Sprintf("count(*) - count(%s)", col)}
+	if colType.IsNested() {
+		exprs = append(exprs, fmt.Sprintf("approx_count_distinct(CAST(%s AS VARCHAR))", col), "NULL", "NULL")
+	} else {
+		exprs = append(exprs, fmt.Sprintf("approx_count_distinct(%s)", col), fmt.Sprintf("min(%s)", col), fmt.Sprintf("max(%s)", col))
+	}
+
+	if colType.IsNumeric() {
+		exprs = append(exprs, fmt.Sprintf("avg(CAST(%s AS DOUBLE))", col), fmt.Sprintf("stddev_samp(CAST(%s AS DOUBLE))", col))
+	} else {
+		exprs = append(exprs, "NULL", "NULL")
+	}
+
+	if colType.IsString() {
+		length := fmt.Sprintf("length(%s)", col)
+		exprs = append(exprs,
+			fmt.Sprintf("min(%s)", length),
+			fmt.Sprintf("approx_quantile(%s, 0.25)", length),
+			fmt.Sprintf("approx_quantile(%s, 0.5)", length),
+			fmt.Sprintf("approx_quantile(%s, 0.75)", length),
+			fmt.Sprintf("max(%s)", length),
+			fmt.Sprintf("avg(%s)", length))
+	} else {
+		exprs = append(exprs, "NULL", "NULL", "NULL", "NULL", "NULL", "NULL")
+	}
+
+	return exprs
+}
+
+// Returns the most frequent non NULL values of the column of table, most frequent first
+func (c *fileconv) getTopValues(ctx context.Context, table string, colName string, topK int) ([]*model.ValueCount, error) {
+	col := param.QuoteIdent(colName)
+	rows, err := c.queryStrings(ctx, fmt.Sprintf("SELECT CAST(%s AS VARCHAR) AS value, count(*) AS count FROM %s WHERE %s IS NOT NULL GROUP BY 1 ORDER BY 2 DESC, 1 LIMIT %d",
+		col, param.QuoteIdent(table), col, topK), 2)
+	if err != nil {
+		return nil, err
+	}
+
+	topValues := make([]*model.ValueCount, 0, len(rows))
+	for _, row := range rows {
+		stats := &statsParser{values: row}
+		value := stats.string()
+		count := stats.int()
+		if stats.err != nil {
+			return nil, stats.err
+		}
+		if value != nil {
+			topValues = append(topValues, &model.ValueCount{Value: *value, Count: count})
+		}
+	}
+
+	return topValues, nil
+}
+
+// Parses the values of a row returned by queryStrings in order. The first parse error is kept in err.
+type statsParser struct {
+	values []*string
+	next   int
+	err    error
+}
+
+func (p *statsParser) string() *string {
+	if p.next >= len(p.values) {
+		p.err = fmt.Errorf("expected more than %d values", len(p.values))
+		return nil
+	}
+	value := p.values[p.next]
+	p.next++
+	return value
+}
+
+func (p *statsParser) int() int64 {
+	value := p.string()
+	if value == nil {
+		return 0
+	}
+	i, err := strconv.ParseInt(*value, 10, 64)
+	if err != nil && p.err == nil {
+		p.err = fmt.Errorf("failed parsing int: %s. error: %w", *value, err)
+	}
+	return i
+}
+
+func (p *statsParser) float() *float64 {
+	value := p.string()
+	if value == nil {
+		return nil
+	}
+	f, err := strconv.ParseFloat(*value, 64)
+	if err != nil {
+		if p.err == nil {
+			p.err = fmt.Errorf("failed parsing float: %s. error: %w", *value, err)
+		}
+		return nil
+	}
+	return &f
+}
@@ -0,0 +1,94 @@
+package fileconv
+
+import (
+	"context"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/hbbtekademy/go-fileconv/pkg/model"
+	"github.com/hbbtekademy/go-fileconv/pkg/param/csvparam"
+	"github.com/hbbtekademy/go-fileconv/pkg/param/profileparam"
+	"github.com/hbbtekademy/go-fileconv/pkg/param/transformparam"
+)
+
+func TestProfile(t *testing.T) {
+	tests := []struct {
+		name              string
+		src               Source
+		params            []profileparam.ProfileParam
+		expectedRows      int64
+		expectedSpecies   *model.ColumnProfile
+		expectedSepalMean float64
+	}{
+		{
+			name:         "TC1",
+			src:          NewCsvSource("../../testdata/csv/iris150.csv", csvparam.WithHeader(true)),
+			params:       []profileparam.ProfileParam{profileparam.WithTopK(2)},
+			expectedRows: 150,
+			expectedSpecies: &model.ColumnProfile{
+				ColumnDesc:    model.ColumnDesc{ColName: "species", ColType: "VARCHAR"},
+				NullCount:     0,
+				DistinctCount: 3,
+				Min:           ptr("Iris-setosa"),
+				Max:           ptr("Iris-virginica"),
+				TopValues: []*model.ValueCount{
+					{Value: "Iris-setosa", Count: 50},
+					{Value: "Iris-versicolor", Count: 50},
+				},
+				Length: &model.LengthStats{Min: 11, P25: 11, Median: 14, P75: 15, Max: 15, Mean: 13.333333333333334},
+			},
+			expectedSepalMean: 5.843333,
+		},
+		{
+			name: "TC2",
+			src: NewCsvSource("../../testdata/csv/iris150.csv", csvparam.WithHeader(true),
+				csvparam.WithTransform(transformparam.WithWhere("species = 'Iris-setosa'"))),
+			params:       []profileparam.ProfileParam{profileparam.WithTopK(0)},
+			expectedRows: 50,
+			expectedSpecies: &model.ColumnProfile{
+				ColumnDesc:    model.ColumnDesc{ColName: "species", ColType: "VARCHAR"},
+				NullCount:     0,
+				DistinctCount: 1,
+				Min:           ptr("Iris-setosa"),
+				Max:           ptr("Iris-setosa"),
+				Length:        &model.LengthStats{Min: 11, P25: 11, Median: 11, P75: 11, Max: 11, Mean: 11},
+			},
+			expectedSepalMean: 5.006,
+		},
+	}
+
+	conv, err := newFileconv(context.Background(), "")
+	if err != nil {
+		t.Fatalf("failed getting duckdb client. error: %v", err)
+	}
+	defer conv.Close()
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			profile, err := conv.Profile(context.Background(), tc.src, tc.params...)
+			if err != nil {
+				t.Fatalf("failed profiling csv. error: %v", err)
+			}
+
+			if profile.Rows != tc.expectedRows || len(profile.Columns) != 5 {
+				t.Fatalf("expected rows: %d and columns: 5 but got: %d and %d", tc.expectedRows, profile.Rows, len(profile.Columns))
+			}
+
+			sepalLength := profile.Columns[0]
+			if sepalLength.Mean == nil || math.Abs(*sepalLength.Mean-tc.expectedSepalMean) > 0.0001 ||
+				sepalLength.StdDev == nil || sepalLength.Length != nil {
+				t.Fatalf("expected numeric stats with mean: %f but got: %+v", tc.expectedSepalMean, sepalLength)
+			}
+
+			species := profile.Columns[4]
+			if !reflect.DeepEqual(tc.expectedSpecies, species) {
+				t.Fatalf("expected: %+v but got: %+v", tc.expectedSpecies, species)
+			}
+		})
+	}
+}
+
+func ptr(s string) *string {
+	return &s
+}
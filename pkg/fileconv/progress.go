@@ -0,0 +1,48 @@
+package fileconv
+
+import (
+	"context"
+	"time"
+)
+
+// Progress of the rows converted by a conversion
+type Progress struct {
+	// Percentage of the rows processed, between 0 and 100
+	Percentage float64 `json:"percentage"`
+	// Number of rows processed
+	RowsProcessed uint64 `json:"rows_processed"`
+	// Estimated total number of rows to process
+	TotalRowsToProcess uint64 `json:"total_rows_to_process"`
+}
+
+// Func receiving the progress of a conversion
+type ProgressFunc func(Progress)
+
+// Interval at which the progress of a running conversion is reported
+const ProgressInterval = 250 * time.Millisecond
+
+type progressKey struct{}
+
+// Returns a context reporting the progress of the conversions run with it to progress.
+// The go-duckdb driver in use does not expose the query progress of DuckDB, so progress is not yet called every ProgressInterval
+// while the rows are converted. It is only called once with all the rows processed when the conversion completes.
+func WithProgress(ctx context.Context, progress ProgressFunc) context.Context {
+	return context.WithValue(ctx, progressKey{}, progress)
+}
+
+// Returns the progress func of the context, or nil if the progress is not reported
+func getProgressFunc(ctx context.Context) ProgressFunc {
+	progress, _ := ctx.Value(progressKey{}).(ProgressFunc)
+	return progress
+}
+
+// Reports the completion of a conversion which wrote rows
+func reportDone(ctx context.Context, rows int64) {
+	if progress := getProgressFunc(ctx); progress != nil {
+		progress(Progress{
+			Percentage:         100,
+			RowsProcessed:      uint64(rows),
+			TotalRowsToProcess: uint64(rows),
+		})
+	}
+}
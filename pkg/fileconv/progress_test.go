@@ -0,0 +1,83 @@
+package fileconv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hbbtekademy/go-fileconv/pkg/param/csvparam"
+	"github.com/hbbtekademy/go-fileconv/pkg/param/pqparam"
+)
+
+func TestConvertProgress(t *testing.T) {
+	conv, err := newFileconv(context.Background(), "")
+	if err != nil {
+		t.Fatalf("failed getting duckdb client. error: %v", err)
+	}
+	defer conv.Close()
+
+	output := filepath.Join(t.TempDir(), "iris150.parquet")
+
+	var mu sync.Mutex
+	reported := []Progress{}
+	ctx := WithProgress(context.Background(), func(p Progress) {
+		mu.Lock()
+		defer mu.Unlock()
+		reported = append(reported, p)
+	})
+
+	_, err = conv.Convert(ctx,
+		NewCsvSource("../../testdata/csv/iris150.csv", csvparam.WithHeader(true)),
+		NewParquetSink(output, pqparam.NewWriteParams()))
+	if err != nil {
+		t.Fatalf("failed converting csv to parquet. error: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	expected := []Progress{{Percentage: 100, RowsProcessed: 150, TotalRowsToProcess: 150}}
+	if !reflect.DeepEqual(reported, expected) {
+		t.Fatalf("expected progress: %+v but got: %+v", expected, reported)
+	}
+}
+
+func TestConvertCancelled(t *testing.T) {
+	conv, err := newFileconv(context.Background(), "")
+	if err != nil {
+		t.Fatalf("failed getting duckdb client. error: %v", err)
+	}
+	defer conv.Close()
+
+	dir := t.TempDir()
+	input := filepath.Join(dir, "range.csv")
+	err = conv.executeCmd(context.Background(),
+		fmt.Sprintf("COPY (SELECT range AS id, md5(range::VARCHAR) AS hash FROM range(5000000)) TO '%s' (HEADER)", input))
+	if err != nil {
+		t.Fatalf("failed writing input csv. error: %v", err)
+	}
+
+	output := filepath.Join(dir, "range.parquet")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// cancel the conversion while the rows are converted
+	timer := time.AfterFunc(100*time.Millisecond, cancel)
+	defer timer.Stop()
+
+	_, err = conv.Convert(ctx,
+		NewCsvSource(input, csvparam.WithHeader(true)),
+		NewParquetSink(output, pqparam.NewWriteParams()))
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context canceled error but got: %v", err)
+	}
+
+	if _, err := os.Stat(output); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected partial output: %s to be removed. error: %v", output, err)
+	}
+}
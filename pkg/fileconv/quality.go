@@ -0,0 +1,106 @@
+package fileconv
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/hbbtekademy/go-fileconv/pkg/param"
+	"github.com/hbbtekademy/go-fileconv/pkg/param/transformparam"
+)
+
+// Report of the data quality rules checked on the rows to write
+type QualityReport struct {
+	// True if no rule with severity error failed
+	Passed bool `json:"passed"`
+	// Number of rows checked
+	Rows int64 `json:"rows"`
+	// Results of the rules, in the order of the rules spec
+	Rules []*RuleResult `json:"rules"`
+}
+
+type RuleResult struct {
+	Name     string                  `json:"name"`
+	Check    transformparam.Check    `json:"check"`
+	Column   string                  `json:"column,omitempty"`
+	Severity transformparam.Severity `json:"severity"`
+	Passed   bool                    `json:"passed"`
+	// Number of rows failing the rule, or the number of rows for row_count
+	Value int64 `json:"value"`
+}
+
+// Error returned by Convert when a rule with severity error fails. No rows are written.
+type QualityError struct {
+	Report *QualityReport
+}
+
+func (e *QualityError) Error() string {
+	return fmt.Sprintf("data quality rules failed: %s", strings.Join(e.Report.Failed(transformparam.SeverityError), ", "))
+}
+
+// Returns the names of the failed rules with the given severity
+func (r *QualityReport) Failed(severity transformparam.Severity) []string {
+	names := []string{}
+	for _, rule := range r.Rules {
+		if !rule.Passed && rule.Severity == severity {
+			names = append(names, rule.Name)
+		}
+	}
+	return names
+}
+
+// Returns the command staging the rows selected by query to a new table, and the name of the table
+func stageCmd(query string) (string, string) {
+	table := fmt.Sprintf("stage_tmp_%d", time.Now().UnixNano())
+	return fmt.Sprintf("CREATE TABLE %s AS %s", param.QuoteIdent(table), query), table
+}
+
+// Checks the rules on the rows staged in table and writes the report to reportPath if it is set
+func (c *fileconv) checkQuality(ctx context.Context, table string, rules *transformparam.Rules, reportPath string) (*QualityReport, error) {
+	rows, err := c.queryCount(ctx, fmt.Sprintf("SELECT count(*) FROM %s", param.QuoteIdent(table)))
+	if err != nil {
+		return nil, err
+	}
+
+	report := &QualityReport{
+		Passed: true,
+		Rows:   rows,
+		Rules:  make([]*RuleResult, 0, len(rules.Rules)),
+	}
+	for i := range rules.Rules {
+		rule := &rules.Rules[i]
+		value, err := c.queryCount(ctx, rule.Query(table))
+		if err != nil {
+			return nil, fmt.Errorf("failed checking rule %q. error: %w", rule.GetName(), err)
+		}
+
+		result := &RuleResult{
+			Name:     rule.GetName(),
+			Check:    rule.Check,
+			Column:   rule.Column,
+			Severity: rule.GetSeverity(),
+			Passed:   rule.Passed(value),
+			Value:    value,
+		}
+		if !result.Passed && result.Severity == transformparam.SeverityError {
+			report.Passed = false
+		}
+		report.Rules = append(report.Rules, result)
+	}
+
+	if reportPath != "" {
+		b, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed marshalling rules report. error: %w", err)
+		}
+		err = os.WriteFile(reportPath, append(b, '\n'), 0644)
+		if err != nil {
+			return nil, fmt.Errorf("failed writing rules report. error: %w", err)
+		}
+	}
+
+	return report, nil
+}
@@ -0,0 +1,264 @@
+package fileconv
+
+import (
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hbbtekademy/go-fileconv/pkg/model"
+	"github.com/hbbtekademy/go-fileconv/pkg/param"
+	"github.com/hbbtekademy/go-fileconv/pkg/param/jsonparam"
+	"github.com/klauspost/compress/zstd"
+)
+
+// Element of the array unnested by a records path step
+const recordColumn = `"__fileconv_record"`
+
+// Column carried onto the records from the json documents
+type carryColumn struct {
+	name string
+	expr string
+}
+
+// Returns the query selecting the elements of the array at the records path of the documents selected by query.
+// Each wildcard of the path unnests an array in a separate step, and the carried fields of the documents are selected
+// in the first step and passed through the others.
+func (c *fileconv) getRecordsSelect(ctx context.Context, query string, recordsPath string, carry []string) (string, error) {
+	segments, err := jsonparam.ParsePath(recordsPath)
+	if err != nil {
+		return "", fmt.Errorf("invalid records path. error: %w", err)
+	}
+	if len(segments) == 0 || segments[0].Wildcard {
+		return "", fmt.Errorf("invalid records path: %s. the path must start with a key, e.g. $.data.items[*]", recordsPath)
+	}
+
+	tableDesc, err := c.GetTableDesc(ctx, query)
+	if err != nil {
+		return "", fmt.Errorf("failed getting json desc. error: %w", err)
+	}
+	root, err := documentType(tableDesc)
+	if err != nil {
+		return "", err
+	}
+
+	carryCols := make([]*carryColumn, 0, len(carry))
+	for _, field := range carry {
+		col, err := getCarryColumn(root, field)
+		if err != nil {
+			return "", err
+		}
+		carryCols = append(carryCols, col)
+	}
+
+	// a path ending at an array selects its elements
+	if !segments[len(segments)-1].Wildcard {
+		_, dataType, err := pathExpr(root, "", segments)
+		if err != nil {
+			return "", fmt.Errorf("invalid records path: %s. error: %w", recordsPath, err)
+		}
+		if dataType.Name == model.TypeList || dataType.Name == model.TypeArray {
+			segments = append(segments, jsonparam.PathSegment{Wildcard: true})
+		}
+	}
+
+	dataType := root
+	expr := ""
+	start := 0
+	for i, segment := range segments {
+		if !segment.Wildcard {
+			continue
+		}
+
+		expr, dataType, err = pathExpr(dataType, expr, segments[start:i])
+		if err != nil {
+			return "", fmt.Errorf("invalid records path: %s. error: %w", recordsPath, err)
+		}
+		if dataType.Name != model.TypeList && dataType.Name != model.TypeArray {
+			return "", fmt.Errorf("invalid records path: %s. error: %s is not an array", recordsPath, dataType)
+		}
+
+		cols := []string{fmt.Sprintf("UNNEST(%s) AS %s", expr, recordColumn)}
+		for _, col := range carryCols {
+			cols = append(cols, fmt.Sprintf("%s AS %s", col.expr, param.QuoteIdent(col.name)))
+			// the carried columns are selected by name after the first step
+			col.expr = param.QuoteIdent(col.name)
+		}
+		query = fmt.Sprintf("SELECT %s FROM (%s)", strings.Join(cols, ","), query)
+
+		expr, dataType, start = recordColumn, dataType.Elem, i+1
+	}
+
+	expr, dataType, err = pathExpr(dataType, expr, segments[start:])
+	if err != nil {
+		return "", fmt.Errorf("invalid records path: %s. error: %w", recordsPath, err)
+	}
+
+	names := map[string]bool{}
+	cols := []string{}
+	if dataType.Name == model.TypeStruct {
+		for _, field := range dataType.Fields {
+			names[strings.ToLower(field.Name)] = true
+			cols = append(cols, fmt.Sprintf("struct_extract(%s, %s) AS %s", expr, param.QuoteLiteral(field.Name), param.QuoteIdent(field.Name)))
+		}
+	} else {
+		// records which are not objects are read into a value column
+		names["value"] = true
+		cols = append(cols, fmt.Sprintf("%s AS value", expr))
+	}
+
+	for _, col := range carryCols {
+		if names[strings.ToLower(col.name)] {
+			return "", fmt.Errorf("carried field %q clashes with a field of the records", col.name)
+		}
+		cols = append(cols, fmt.Sprintf("%s AS %s", col.expr, param.QuoteIdent(col.name)))
+	}
+
+	return fmt.Sprintf("SELECT %s FROM (%s)", strings.Join(cols, ","), query), nil
+}
+
+// Returns the type of the documents as a STRUCT of the columns
+func documentType(tableDesc *model.TableDesc) (*model.DataType, error) {
+	root := &model.DataType{Name: model.TypeStruct}
+	for _, colDesc := range tableDesc.ColumnDescs {
+		dataType, err := colDesc.ColType.Parse()
+		if err != nil {
+			return nil, fmt.Errorf("failed parsing type of column: %s. error: %w", colDesc.ColName, err)
+		}
+		root.Fields = append(root.Fields, &model.Field{Name: colDesc.ColName, Type: dataType})
+	}
+	return root, nil
+}
+
+// Returns the expression and type of the keys of the path in the object selected by expr.
+// An empty expr is the document, whose keys are the columns.
+func pathExpr(dataType *model.DataType, expr string, segments []jsonparam.PathSegment) (string, *model.DataType, error) {
+	for _, segment := range segments {
+		if dataType.Name != model.TypeStruct {
+			return "", nil, fmt.Errorf("%s is not an object", dataType)
+		}
+
+		var field *model.Field
+		for _, f := range dataType.Fields {
+			if strings.EqualFold(f.Name, segment.Key) {
+				field = f
+				break
+			}
+		}
+		if field == nil {
+			return "", nil, fmt.Errorf("key %q not found", segment.Key)
+		}
+
+		if expr == "" {
+			expr = param.QuoteIdent(field.Name)
+		} else {
+			expr = fmt.Sprintf("struct_extract(%s, %s)", expr, param.QuoteLiteral(field.Name))
+		}
+		dataType = field.Type
+	}
+
+	return expr, dataType, nil
+}
+
+func getCarryColumn(root *model.DataType, field string) (*carryColumn, error) {
+	segments, err := jsonparam.ParsePath(field)
+	if err != nil {
+		return nil, fmt.Errorf("invalid carried field. error: %w", err)
+	}
+
+	keys := make([]string, 0, len(segments))
+	for _, segment := range segments {
+		if segment.Wildcard {
+			return nil, fmt.Errorf("invalid carried field: %s. carried fields cannot select array elements", field)
+		}
+		keys = append(keys, segment.Key)
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("invalid carried field: %s. the field must have a key", field)
+	}
+
+	expr, _, err := pathExpr(root, "", segments)
+	if err != nil {
+		return nil, fmt.Errorf("invalid carried field: %s. error: %w", field, err)
+	}
+
+	return &carryColumn{name: strings.Join(keys, "_"), expr: expr}, nil
+}
+
+// Returns the uncompressed size of the largest json file matching path, or 0 if no local files match.
+// Compressed files are read through a decompressor, as their headers don't hold a reliable uncompressed size.
+func getMaxDocumentSize(path string, compression param.Compression) uint64 {
+	files, err := filepath.Glob(path)
+	if err != nil {
+		return 0
+	}
+
+	var maxSize uint64
+	for _, file := range files {
+		info, err := os.Stat(file)
+		if err != nil || info.IsDir() {
+			continue
+		}
+
+		size := uint64(info.Size())
+		if fileCompression := getFileCompression(file, compression); fileCompression != param.None {
+			size, err = getUncompressedSize(file, fileCompression)
+			if err != nil {
+				continue
+			}
+		}
+		maxSize = max(maxSize, size)
+	}
+	return maxSize
+}
+
+// Returns the compression of the file, detected from its extension as DuckDB does if compression is auto
+func getFileCompression(file string, compression param.Compression) param.Compression {
+	if compression != param.AutoCompression {
+		return compression
+	}
+
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".gz":
+		return param.Gzip
+	case ".zst":
+		return param.Zstd
+	default:
+		return param.None
+	}
+}
+
+// Returns the uncompressed size of the file by reading it through the decompressor of compression
+func getUncompressedSize(file string, compression param.Compression) (uint64, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	var r io.Reader
+	switch compression {
+	case param.Gzip:
+		zr, err := gzip.NewReader(f)
+		if err != nil {
+			return 0, err
+		}
+		defer zr.Close()
+		r = zr
+	case param.Zstd:
+		zr, err := zstd.NewReader(f)
+		if err != nil {
+			return 0, err
+		}
+		defer zr.Close()
+		r = zr
+	default:
+		return 0, fmt.Errorf("unsupported compression: %s", compression)
+	}
+
+	size, err := io.Copy(io.Discard, r)
+	return uint64(size), err
+}
@@ -0,0 +1,237 @@
+package fileconv
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hbbtekademy/go-fileconv/pkg/param"
+	"github.com/hbbtekademy/go-fileconv/pkg/param/jsonparam"
+	"github.com/hbbtekademy/go-fileconv/pkg/param/pqparam"
+	"github.com/klauspost/compress/zstd"
+)
+
+func TestGetRecordsSelect(t *testing.T) {
+	document := `CREATE TABLE %s AS SELECT
+{'generated_at': '2024-01-01', 'source': 'api'} AS meta,
+{'items': [{'id': 1, 'name': 'a'}, {'id': 2, 'name': 'b'}], 'tags': ['x', 'y']} AS data,
+[{'n': 1, 'items': [{'id': 3}]}, {'n': 2, 'items': [{'id': 4}, {'id': 5}]}] AS pages`
+
+	tests := []struct {
+		name            string
+		recordsPath     string
+		carry           []string
+		expectedColumns []string
+		expectedRows    []string
+		expectedErr     string
+	}{
+		{
+			name:            "TC1",
+			recordsPath:     "$.data.items[*]",
+			carry:           []string{"$.meta.generated_at", "meta.source"},
+			expectedColumns: []string{"id", "name", "meta_generated_at", "meta_source"},
+			expectedRows:    []string{"1|a|2024-01-01|api", "2|b|2024-01-01|api"},
+		},
+		{
+			name:            "TC2",
+			recordsPath:     "data.items",
+			expectedColumns: []string{"id", "name"},
+			expectedRows:    []string{"1|a", "2|b"},
+		},
+		{
+			name:            "TC3",
+			recordsPath:     "$.pages[*].items[*]",
+			carry:           []string{"meta.source"},
+			expectedColumns: []string{"id", "meta_source"},
+			expectedRows:    []string{"3|api", "4|api", "5|api"},
+		},
+		{
+			name:            "TC4",
+			recordsPath:     "$.data.tags[*]",
+			expectedColumns: []string{"value"},
+			expectedRows:    []string{"x", "y"},
+		},
+		{
+			name:        "TC5",
+			recordsPath: "$.data.rows[*]",
+			expectedErr: `invalid records path: $.data.rows[*]. error: key "rows" not found`,
+		},
+		{
+			name:        "TC6",
+			recordsPath: "$.meta[*]",
+			expectedErr: "invalid records path: $.meta[*]. error: STRUCT(generated_at VARCHAR, source VARCHAR) is not an array",
+		},
+		{
+			name:        "TC7",
+			recordsPath: "$.data.items[*]",
+			carry:       []string{"$.pages[*].n"},
+			expectedErr: "invalid carried field: $.pages[*].n. carried fields cannot select array elements",
+		},
+	}
+
+	dbFile := fmt.Sprintf("test_%d.db", time.Now().UnixNano())
+	defer os.RemoveAll(dbFile)
+	defer os.RemoveAll(dbFile + ".wal")
+
+	conv, err := newFileconv(context.Background(), dbFile)
+	if err != nil {
+		t.Fatalf("failed getting converter. error: %v", err)
+	}
+	defer conv.Close()
+
+	ctx := context.Background()
+	tableName := fmt.Sprintf("records_%d", time.Now().UnixNano())
+	err = conv.executeCmd(ctx, fmt.Sprintf(document, tableName))
+	if err != nil {
+		t.Fatalf("failed creating table. error: %v", err)
+	}
+	defer conv.dropTable(ctx, tableName)
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			query, err := conv.getRecordsSelect(ctx, fmt.Sprintf("SELECT * FROM %s", tableName), tc.recordsPath, tc.carry)
+			if tc.expectedErr != "" {
+				if err == nil || err.Error() != tc.expectedErr {
+					t.Fatalf("expected error: %s but got: %v", tc.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("failed getting records select. error: %v", err)
+			}
+
+			tableDesc, err := conv.GetTableDesc(ctx, query)
+			if err != nil {
+				t.Fatalf("failed getting table desc. error: %v", err)
+			}
+			columns := []string{}
+			for _, colDesc := range tableDesc.ColumnDescs {
+				columns = append(columns, colDesc.ColName)
+			}
+			if !reflect.DeepEqual(columns, tc.expectedColumns) {
+				t.Fatalf("expected columns: %v but got: %v", tc.expectedColumns, columns)
+			}
+
+			values, err := conv.queryStrings(ctx, fmt.Sprintf("SELECT * FROM (%s) ORDER BY ALL", query), len(columns))
+			if err != nil {
+				t.Fatalf("failed querying records. error: %v", err)
+			}
+			rows := []string{}
+			for _, row := range values {
+				cells := []string{}
+				for _, v := range row {
+					cells = append(cells, *v)
+				}
+				rows = append(rows, strings.Join(cells, "|"))
+			}
+			if !reflect.DeepEqual(rows, tc.expectedRows) {
+				t.Fatalf("expected rows: %v but got: %v", tc.expectedRows, rows)
+			}
+		})
+	}
+}
+
+func TestConvertRecordsLargeDocument(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "large.json")
+
+	// a single document above the default maximum object size of 16 MB
+	items := make([]string, 0, 20000)
+	for i := 0; i < 20000; i++ {
+		items = append(items, fmt.Sprintf(`{"id": %d, "name": "%s"}`, i, strings.Repeat("x", 1000)))
+	}
+	document := fmt.Sprintf(`{"meta": {"source": "api"}, "data": {"items": [%s]}}`, strings.Join(items, ","))
+	err := os.WriteFile(input, []byte(document), 0644)
+	if err != nil {
+		t.Fatalf("failed writing input json. error: %v", err)
+	}
+
+	conv, err := newFileconv(context.Background(), "")
+	if err != nil {
+		t.Fatalf("failed getting converter. error: %v", err)
+	}
+	defer conv.Close()
+
+	src := newJsonSource(input, jsonparam.WithFormat(jsonparam.AutoFormat), jsonparam.WithRecordsPath("$.data.items[*]"))
+	result, err := conv.Convert(context.Background(), src,
+		NewParquetSink(filepath.Join(dir, "large.parquet"), pqparam.NewWriteParams()))
+	if err != nil {
+		t.Fatalf("failed converting json to parquet. error: %v", err)
+	}
+	if result.RowsWritten != 20000 {
+		t.Fatalf("expected rows written: 20000 but got: %d", result.RowsWritten)
+	}
+
+	// the maximum object size is raised on a copy of the params of the source
+	if expected := jsonparam.NewReadParams().GetMaxObjSize(); src.params.GetMaxObjSize() != expected {
+		t.Fatalf("expected max object size of the source: %d but got: %d", expected, src.params.GetMaxObjSize())
+	}
+}
+
+func TestGetMaxDocumentSize(t *testing.T) {
+	dir := t.TempDir()
+	err := os.WriteFile(filepath.Join(dir, "a.json"), []byte(`{"a": 1}`), 0644)
+	if err != nil {
+		t.Fatalf("failed writing json. error: %v", err)
+	}
+
+	// the gzip trailer of the last member holds the size of that member only
+	var buf bytes.Buffer
+	for _, member := range []string{strings.Repeat(" ", 1000), `{"a": 1}`} {
+		zw := gzip.NewWriter(&buf)
+		zw.Write([]byte(member))
+		zw.Close()
+	}
+	err = os.WriteFile(filepath.Join(dir, "b.json.gz"), buf.Bytes(), 0644)
+	if err != nil {
+		t.Fatalf("failed writing gzip json. error: %v", err)
+	}
+
+	zw, err := zstd.NewWriter(nil)
+	if err != nil {
+		t.Fatalf("failed getting zstd writer. error: %v", err)
+	}
+	err = os.WriteFile(filepath.Join(dir, "c.json.zst"), zw.EncodeAll([]byte(strings.Repeat(" ", 2000)+`{"a": 1}`), nil), 0644)
+	if err != nil {
+		t.Fatalf("failed writing zstd json. error: %v", err)
+	}
+
+	buf.Reset()
+	gw := gzip.NewWriter(&buf)
+	gw.Write([]byte(strings.Repeat(" ", 3000) + `{"a": 1}`))
+	gw.Close()
+	err = os.WriteFile(filepath.Join(dir, "d.json"), buf.Bytes(), 0644)
+	if err != nil {
+		t.Fatalf("failed writing gzip json. error: %v", err)
+	}
+
+	tests := []struct {
+		name         string
+		path         string
+		compression  param.Compression
+		expectedSize uint64
+	}{
+		{name: "TC1", path: filepath.Join(dir, "a.json"), compression: param.AutoCompression, expectedSize: 8},
+		{name: "TC2", path: filepath.Join(dir, "b.json.gz"), compression: param.AutoCompression, expectedSize: 1008},
+		{name: "TC3", path: filepath.Join(dir, "c.json.zst"), compression: param.AutoCompression, expectedSize: 2008},
+		{name: "TC4", path: filepath.Join(dir, "*.json*"), compression: param.AutoCompression, expectedSize: 2008},
+		{name: "TC5", path: filepath.Join(dir, "d.json"), compression: param.Gzip, expectedSize: 3008},
+		{name: "TC6", path: filepath.Join(dir, "missing.json"), compression: param.AutoCompression, expectedSize: 0},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			size := getMaxDocumentSize(tc.path, tc.compression)
+			if size != tc.expectedSize {
+				t.Fatalf("expected size: %d but got: %d", tc.expectedSize, size)
+			}
+		})
+	}
+}
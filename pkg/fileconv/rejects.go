@@ -0,0 +1,91 @@
+package fileconv
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/hbbtekademy/go-fileconv/pkg/param"
+	"github.com/hbbtekademy/go-fileconv/pkg/param/jsonparam"
+)
+
+// Selects the rows rejected by the last read_csv scan with store_rejects from the reject tables of the connection
+const csvRejectsQuery = `SELECT s.file_path AS file, e.line, e.column_name AS "column", e.error_message AS error, trim(e.csv_line, chr(13) || chr(10)) AS rejected_line
+FROM reject_errors e JOIN reject_scans s ON e.scan_id = s.scan_id AND e.file_id = s.file_id`
+
+// Drops the reject tables of the connection, which otherwise accumulate the rejects of all the scans
+var clearRejectTablesCmds = []string{"DROP TABLE IF EXISTS reject_errors", "DROP TABLE IF EXISTS reject_scans"}
+
+/*
+Returns the query selecting the rejected lines of the newline delimited json files matching path: the malformed lines,
+which read_json skips when ignore_errors is set, and the lines with a value of a column of the params which cannot be
+converted to the type of the column.
+The lines are read with read_csv, which decompresses the files like read_json and streams them instead of
+loading whole files. Both \n and \r\n end a line. The files are scanned by a single thread, so that the row numbers
+follow the order of the lines.
+*/
+func jsonRejectsQuery(path string, params *jsonparam.ReadParams) string {
+	failure := "WHEN NOT json_valid(text) THEN [NULL, 'malformed json']"
+	for _, col := range params.GetColumns() {
+		pointer := param.QuoteLiteral("/" + strings.NewReplacer("~", "~0", "/", "~1").Replace(col.Name))
+		failure += fmt.Sprintf(" WHEN json_type(text, %s) <> 'NULL' AND TRY_CAST(json_extract(text, %s) AS %s) IS NULL THEN [%s, %s]",
+			pointer, pointer, col.Type, param.QuoteLiteral(col.Name),
+			param.QuoteLiteral(fmt.Sprintf("Error when converting column %q to '%s'", col.Name, col.Type)))
+	}
+
+	// the line numbers are counted from the row numbers, which follow the order of the single threaded scan
+	lines := fmt.Sprintf(`SELECT file, n - min(n) OVER (PARTITION BY file) + 1 AS line, text
+FROM (SELECT filename AS file, row_number() OVER () AS n, text
+FROM read_csv(%s, columns = {'text': 'VARCHAR'}, header = false, auto_detect = false, delim = E'\x01', quote = '', escape = '',
+new_line = '\r\n', compression = %s, max_line_size = %d, filename = true, parallel = false))`,
+		param.QuoteLiteral(path), param.QuoteLiteral(string(params.GetCompression())), params.GetMaxObjSize())
+
+	return fmt.Sprintf(`SELECT file, line, failure[1] AS "column", failure[2] AS error, text AS rejected_line
+FROM (SELECT file, line, text, CASE %s END AS failure FROM (%s) WHERE trim(text) <> '')
+WHERE failure IS NOT NULL`, failure, lines)
+}
+
+// Returns the command saving the rows selected by the rejects query to a new table, and the name of the table
+func saveRejectsCmd(rejectsQuery string) (string, string) {
+	table := fmt.Sprintf("rejects_tmp_%d", time.Now().UnixNano())
+	return fmt.Sprintf("CREATE TABLE %s AS %s", param.QuoteIdent(table), rejectsQuery), table
+}
+
+// Writes the rejected rows saved in table to the rejects file and returns the number of rejected rows.
+// The rejects file is written as json if it has a .json extension, otherwise as csv.
+func (c *fileconv) writeRejects(ctx context.Context, table string, path string) (int64, error) {
+	rejected, err := c.queryCount(ctx, fmt.Sprintf("SELECT count(*) FROM %s", param.QuoteIdent(table)))
+	if err != nil {
+		return 0, err
+	}
+
+	format := "FORMAT CSV, HEADER true"
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		format = "FORMAT JSON"
+	}
+
+	err = c.executeCmd(ctx, fmt.Sprintf("COPY (SELECT * FROM %s ORDER BY file, line) TO %s (%s)",
+		param.QuoteIdent(table), param.QuoteLiteral(path), format))
+	if err != nil {
+		return 0, err
+	}
+
+	return rejected, nil
+}
+
+// Writes the rejected rows saved in table to the rejects file of the source and returns the number of rejected rows.
+// Returns an error if the number of rejected rows exceeds the max rejects of the source.
+func (c *fileconv) checkRejects(ctx context.Context, src Source, table string) (int64, error) {
+	rejectsPath, maxRejects := src.getRejects()
+	rejected, err := c.writeRejects(ctx, table, rejectsPath)
+	if err != nil {
+		return 0, fmt.Errorf("failed writing %s rejects. error: %w", src.Format(), err)
+	}
+	if maxRejects >= 0 && rejected > maxRejects {
+		return 0, fmt.Errorf("rejected rows: %d exceed max rejects: %d. rejects written to: %s", rejected, maxRejects, rejectsPath)
+	}
+
+	return rejected, nil
+}
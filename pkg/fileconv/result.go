@@ -0,0 +1,81 @@
+package fileconv
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/hbbtekademy/go-fileconv/pkg/model"
+	"github.com/hbbtekademy/go-fileconv/pkg/param"
+)
+
+// Result of a conversion
+type ConversionResult struct {
+	// Number of rows read from the source files, including the rejected rows
+	RowsRead int64 `json:"rows_read"`
+	// Number of rows written to the output files
+	RowsWritten int64 `json:"rows_written"`
+	// Number of rows rejected by the source and written to the rejects file
+	RowsRejected int64 `json:"rows_rejected"`
+	// Report of the data quality rules, if any rules are set
+	Quality *QualityReport `json:"quality"`
+	// Files written by the conversion
+	OutputFiles []*OutputFile `json:"output_files"`
+	// Time taken by the conversion
+	Elapsed time.Duration `json:"elapsed"`
+	// Column names and types of the converted rows
+	Schema *model.TableDesc `json:"schema"`
+}
+
+type OutputFile struct {
+	Path string `json:"path"`
+	Size int64  `json:"size"`
+}
+
+// Returns the files written to dest. If dest is a directory, e.g. hive partitioned parquet output,
+// all the files within the directory are returned.
+func getOutputFiles(dest string) ([]*OutputFile, error) {
+	info, err := os.Stat(dest)
+	if err != nil {
+		return nil, err
+	}
+
+	if !info.IsDir() {
+		return []*OutputFile{{Path: dest, Size: info.Size()}}, nil
+	}
+
+	outputFiles := []*OutputFile{}
+	err = filepath.WalkDir(dest, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return fmt.Errorf("failed getting file info of %s. error: %w", path, err)
+		}
+		outputFiles = append(outputFiles, &OutputFile{Path: path, Size: info.Size()})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return outputFiles, nil
+}
+
+// Returns the total number of rows in the parquet files using the parquet metadata
+func (c *fileconv) getParquetRowCount(ctx context.Context, outputFiles []*OutputFile) (int64, error) {
+	files := make([]string, 0, len(outputFiles))
+	for _, outputFile := range outputFiles {
+		files = append(files, outputFile.Path)
+	}
+
+	return c.queryCount(ctx, fmt.Sprintf("SELECT sum(num_rows) FROM parquet_file_metadata(%s)", param.ListLiteral(files)))
+}
@@ -0,0 +1,297 @@
+package fileconv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/hbbtekademy/go-fileconv/pkg/param"
+	"github.com/hbbtekademy/go-fileconv/pkg/param/csvparam"
+	"github.com/hbbtekademy/go-fileconv/pkg/param/jsonparam"
+	"github.com/hbbtekademy/go-fileconv/pkg/param/pqparam"
+)
+
+type csvSink struct {
+	path   string
+	params *csvparam.WriteParams
+}
+
+// Returns a Sink writing a csv file to path
+func NewCsvSink(path string, params *csvparam.WriteParams) Sink {
+	return &csvSink{
+		path:   path,
+		params: params,
+	}
+}
+
+func (s *csvSink) getPath() string {
+	return s.path
+}
+
+func (s *csvSink) isMultiFile() bool {
+	return false
+}
+
+func (s *csvSink) withPath(path string) Sink {
+	return NewCsvSink(path, s.params)
+}
+
+func (s *csvSink) getOverwriteOrIgnore() bool {
+	return false
+}
+
+func (s *csvSink) getKeepPartialOutput() bool {
+	return s.params.GetKeepPartialOutput()
+}
+
+func (s *csvSink) Format() Format {
+	return Csv
+}
+
+func (s *csvSink) copyCmd(ctx context.Context, c *fileconv, query string) (string, error) {
+	return fmt.Sprintf("COPY (%s) TO %s %s", query, param.QuoteLiteral(s.path), s.params.Params()), nil
+}
+
+type jsonSink struct {
+	path   string
+	params *jsonparam.WriteParams
+}
+
+// Returns a Sink writing a json file to path
+func NewJsonSink(path string, params *jsonparam.WriteParams) Sink {
+	return &jsonSink{
+		path:   path,
+		params: params,
+	}
+}
+
+func (s *jsonSink) getPath() string {
+	return s.path
+}
+
+func (s *jsonSink) isMultiFile() bool {
+	return false
+}
+
+func (s *jsonSink) withPath(path string) Sink {
+	return NewJsonSink(path, s.params)
+}
+
+func (s *jsonSink) getOverwriteOrIgnore() bool {
+	return false
+}
+
+func (s *jsonSink) getKeepPartialOutput() bool {
+	return s.params.GetKeepPartialOutput()
+}
+
+func (s *jsonSink) Format() Format {
+	return Json
+}
+
+func (s *jsonSink) copyCmd(ctx context.Context, c *fileconv, query string) (string, error) {
+	if s.params.GetUnflatten() {
+		var err error
+		query, err = c.getUnflattenedTableSelect(ctx, query, s.params.GetUnflattenConfig())
+		if err != nil {
+			return "", fmt.Errorf("failed getting unflattened table. error: %w", err)
+		}
+	}
+
+	if s.params.GetNestedAsString() {
+		var err error
+		query, err = c.getNestedAsStringTableSelect(ctx, query)
+		if err != nil {
+			return "", fmt.Errorf("failed getting stringified table. error: %w", err)
+		}
+	}
+
+	return fmt.Sprintf("COPY (%s) TO %s %s", query, param.QuoteLiteral(s.path), s.params.Params()), nil
+}
+
+type parquetSink struct {
+	path   string
+	params *pqparam.WriteParams
+}
+
+// Returns a Sink writing a parquet file to path.
+// If the write params partition the output, path is the directory in which the hive partitioned files are written.
+func NewParquetSink(path string, params *pqparam.WriteParams) Sink {
+	return &parquetSink{
+		path:   path,
+		params: params,
+	}
+}
+
+func (s *parquetSink) getPath() string {
+	return s.path
+}
+
+func (s *parquetSink) isMultiFile() bool {
+	return s.params.IsMultiFileOutput()
+}
+
+func (s *parquetSink) withPath(path string) Sink {
+	return NewParquetSink(path, s.params)
+}
+
+func (s *parquetSink) getOverwriteOrIgnore() bool {
+	return s.params.GetOverwriteOrIgnore()
+}
+
+func (s *parquetSink) getKeepPartialOutput() bool {
+	return s.params.GetKeepPartialOutput()
+}
+
+func (s *parquetSink) Format() Format {
+	return Parquet
+}
+
+func (s *parquetSink) copyCmd(ctx context.Context, c *fileconv, query string) (string, error) {
+	return fmt.Sprintf("COPY (%s) TO %s %s", query, param.QuoteLiteral(s.path), s.params.Params()), nil
+}
+
+// Returns a sink writing to a temp directory next to the path of sink and a func closing the sink.
+// Closing the sink renames the temp output to the path of sink if write is true and removes the temp directory.
+// As the temp directory is on the same file system as the path, the rename is atomic and the path never holds
+// partial output. Multi file output written to an existing directory is merged with the files of the directory
+// in the temp directory, which is then swapped with the existing directory.
+func openFileSink(sink Sink) (Sink, func(ctx context.Context, write bool) error, error) {
+	dest := sink.getPath()
+
+	if sink.isMultiFile() && !sink.getOverwriteOrIgnore() {
+		entries, err := os.ReadDir(dest)
+		if err == nil && len(entries) > 0 {
+			return nil, nil, fmt.Errorf("directory %s is not empty. enable overwrite or ignore to write into it", dest)
+		}
+	}
+
+	tmpDir, err := os.MkdirTemp(filepath.Dir(dest), ".fileconv-tmp-*")
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed creating temp dir. error: %w", err)
+	}
+
+	// the temp output has the base name of the path so that DuckDB detects the compression from the extension
+	path := filepath.Join(tmpDir, filepath.Base(dest))
+	closeSink := func(ctx context.Context, write bool) error {
+		if !write {
+			return os.RemoveAll(tmpDir)
+		}
+
+		err := commitOutput(path, dest)
+		if err != nil {
+			if sink.getKeepPartialOutput() {
+				return fmt.Errorf("%w. partial output kept in: %s", err, path)
+			}
+			os.RemoveAll(tmpDir)
+			return err
+		}
+		return os.RemoveAll(tmpDir)
+	}
+
+	return sink.withPath(path), closeSink, nil
+}
+
+// Renames used to commit the output, replaced in the tests
+var (
+	rename = os.Rename
+	link   = os.Link
+)
+
+// Renames the temp output to dest.
+// If the temp output is a directory and dest exists, the files of dest missing in the temp output are linked into it
+// and dest is swapped with the temp output. dest is left unchanged if the commit fails.
+func commitOutput(tmp string, dest string) error {
+	info, err := os.Stat(tmp)
+	if err != nil {
+		return err
+	}
+
+	if info.IsDir() {
+		if _, err := os.Stat(dest); !errors.Is(err, os.ErrNotExist) {
+			return swapDir(tmp, dest)
+		}
+	}
+
+	return rename(tmp, dest)
+}
+
+// Replaces the dest directory by the tmp directory merged with the files of dest which are not in tmp.
+// dest is moved to a backup next to tmp, which is removed with the temp directory, and restored if tmp can't be renamed.
+func swapDir(tmp string, dest string) error {
+	err := linkFiles(dest, tmp)
+	if err != nil {
+		return fmt.Errorf("failed merging the existing files of %s. error: %w", dest, err)
+	}
+
+	backup := tmp + ".bak"
+	err = rename(dest, backup)
+	if err != nil {
+		return err
+	}
+
+	err = rename(tmp, dest)
+	if err != nil {
+		if restoreErr := rename(backup, dest); restoreErr != nil {
+			return fmt.Errorf("%w. failed restoring %s from: %s. error: %v", err, dest, backup, restoreErr)
+		}
+		return err
+	}
+
+	return nil
+}
+
+// Links the files in the src directory which are missing in the dest directory into dest
+func linkFiles(src string, dest string) error {
+	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+
+		target := filepath.Join(dest, rel)
+		if _, err := os.Lstat(target); !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+
+		err = os.MkdirAll(filepath.Dir(target), 0755)
+		if err != nil {
+			return err
+		}
+		return link(path, target)
+	})
+}
+
+// Returns the output files with the paths in the temp output replaced by the paths in dest
+func relocateOutputFiles(outputFiles []*OutputFile, tmp string, dest string) []*OutputFile {
+	for _, outputFile := range outputFiles {
+		rel, err := filepath.Rel(tmp, outputFile.Path)
+		if err != nil || rel == "." {
+			outputFile.Path = dest
+			continue
+		}
+		outputFile.Path = filepath.Join(dest, rel)
+	}
+	return outputFiles
+}
+
+// Returns true if the temp output of a sink holds any data
+func hasPartialOutput(path string) bool {
+	outputFiles, err := getOutputFiles(path)
+	if err != nil {
+		return false
+	}
+	for _, outputFile := range outputFiles {
+		if outputFile.Size > 0 {
+			return true
+		}
+	}
+	return false
+}
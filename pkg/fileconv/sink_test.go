@@ -0,0 +1,126 @@
+package fileconv
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCommitOutput(t *testing.T) {
+	errInjected := errors.New("injected error")
+
+	tests := []struct {
+		name          string
+		existing      map[string]string
+		failRename    int
+		failLink      int
+		expectedErr   string
+		expectedFiles map[string]string
+	}{
+		{
+			name:          "TC1",
+			expectedFiles: map[string]string{"a=1/data_0.csv": "new a1", "a=2/data_0.csv": "new a2"},
+		},
+		{
+			name:          "TC2",
+			existing:      map[string]string{"a=1/data_0.csv": "old a1", "a=3/data_0.csv": "old a3", "a=3/data_1.csv": "old a3 1"},
+			expectedFiles: map[string]string{"a=1/data_0.csv": "new a1", "a=2/data_0.csv": "new a2", "a=3/data_0.csv": "old a3", "a=3/data_1.csv": "old a3 1"},
+		},
+		{
+			name:          "TC3",
+			existing:      map[string]string{"a=1/data_0.csv": "old a1", "a=3/data_0.csv": "old a3"},
+			failRename:    2,
+			expectedErr:   "injected error",
+			expectedFiles: map[string]string{"a=1/data_0.csv": "old a1", "a=3/data_0.csv": "old a3"},
+		},
+		{
+			name:          "TC4",
+			existing:      map[string]string{"a=1/data_0.csv": "old a1", "a=3/data_0.csv": "old a3", "a=3/data_1.csv": "old a3 1"},
+			failLink:      2,
+			expectedErr:   "failed merging the existing files",
+			expectedFiles: map[string]string{"a=1/data_0.csv": "old a1", "a=3/data_0.csv": "old a3", "a=3/data_1.csv": "old a3 1"},
+		},
+	}
+
+	defer func() { rename, link = os.Rename, os.Link }()
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			tmp := filepath.Join(dir, ".fileconv-tmp-1", "dest")
+			dest := filepath.Join(dir, "dest")
+
+			writeFiles(t, tmp, map[string]string{"a=1/data_0.csv": "new a1", "a=2/data_0.csv": "new a2"})
+			if tc.existing != nil {
+				writeFiles(t, dest, tc.existing)
+			}
+
+			renames, links := 0, 0
+			rename = func(oldpath string, newpath string) error {
+				renames++
+				if renames == tc.failRename {
+					return errInjected
+				}
+				return os.Rename(oldpath, newpath)
+			}
+			link = func(oldname string, newname string) error {
+				links++
+				if links == tc.failLink {
+					return errInjected
+				}
+				return os.Link(oldname, newname)
+			}
+
+			err := commitOutput(tmp, dest)
+			if tc.expectedErr == "" && err != nil {
+				t.Fatalf("failed committing output. error: %v", err)
+			}
+			if tc.expectedErr != "" && (err == nil || !strings.Contains(err.Error(), tc.expectedErr)) {
+				t.Fatalf("expected error: %s but got: %v", tc.expectedErr, err)
+			}
+
+			actualFiles := readFiles(t, dest)
+			if !reflect.DeepEqual(actualFiles, tc.expectedFiles) {
+				t.Fatalf("expected files: %v but got: %v", tc.expectedFiles, actualFiles)
+			}
+		})
+	}
+}
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("failed creating dir. error: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("failed writing file. error: %v", err)
+		}
+	}
+}
+
+func readFiles(t *testing.T, dir string) map[string]string {
+	files := map[string]string{}
+	err := filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		files[filepath.ToSlash(rel)] = string(content)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("failed reading files. error: %v", err)
+	}
+	return files
+}
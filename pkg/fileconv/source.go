@@ -0,0 +1,149 @@
+package fileconv
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hbbtekademy/go-fileconv/pkg/model"
+	"github.com/hbbtekademy/go-fileconv/pkg/param"
+	"github.com/hbbtekademy/go-fileconv/pkg/param/csvparam"
+	"github.com/hbbtekademy/go-fileconv/pkg/param/jsonparam"
+	"github.com/hbbtekademy/go-fileconv/pkg/param/pqparam"
+	"github.com/hbbtekademy/go-fileconv/pkg/param/transformparam"
+)
+
+type csvSource struct {
+	path   string
+	params *csvparam.ReadParams
+}
+
+// Returns a Source reading the csv files matching path
+func NewCsvSource(path string, params ...csvparam.ReadParam) Source {
+	return &csvSource{
+		path:   path,
+		params: csvparam.NewReadParams(params...),
+	}
+}
+
+func (s *csvSource) Format() Format {
+	return Csv
+}
+
+func (s *csvSource) query(ctx context.Context, c *fileconv) (string, func(), error) {
+	return fmt.Sprintf("SELECT * FROM read_csv(%s %s)", param.QuoteLiteral(s.path), s.params.Params()), func() {}, nil
+}
+
+func (s *csvSource) describe(ctx context.Context, c *fileconv) (*model.TableDesc, error) {
+	return c.describeCsv(ctx, s.path, s.params)
+}
+
+func (s *csvSource) getDescribe() bool {
+	return s.params.GetDescribe()
+}
+
+func (s *csvSource) getTransform() *transformparam.TransformParams {
+	return s.params.GetTransform()
+}
+
+func (s *csvSource) rejectsQuery() (string, error) {
+	if s.params.GetRejects() == "" {
+		return "", nil
+	}
+	return csvRejectsQuery, nil
+}
+
+func (s *csvSource) getRejects() (string, int64) {
+	return s.params.GetRejects(), s.params.GetMaxRejects()
+}
+
+type jsonSource struct {
+	path   string
+	params *jsonparam.ReadParams
+}
+
+// Returns a Source reading the json files matching path
+func NewJsonSource(path string, params ...jsonparam.ReadParam) Source {
+	return newJsonSource(path, params...)
+}
+
+func newJsonSource(path string, params ...jsonparam.ReadParam) *jsonSource {
+	return &jsonSource{
+		path:   path,
+		params: jsonparam.NewReadParams(params...),
+	}
+}
+
+func (s *jsonSource) Format() Format {
+	return Json
+}
+
+func (s *jsonSource) query(ctx context.Context, c *fileconv) (string, func(), error) {
+	return c.getJsonSelect(ctx, s.path, s.params, 0)
+}
+
+func (s *jsonSource) describe(ctx context.Context, c *fileconv) (*model.TableDesc, error) {
+	return c.describeJson(ctx, s.path, s.params)
+}
+
+func (s *jsonSource) getDescribe() bool {
+	return s.params.GetDescribe()
+}
+
+func (s *jsonSource) getTransform() *transformparam.TransformParams {
+	return s.params.GetTransform()
+}
+
+func (s *jsonSource) rejectsQuery() (string, error) {
+	if s.params.GetRejects() == "" {
+		return "", nil
+	}
+	if s.params.GetFormat() != jsonparam.NewlineDelimited {
+		return "", fmt.Errorf("rejects can only be captured when format is '%s'", jsonparam.NewlineDelimited)
+	}
+	return jsonRejectsQuery(s.path, s.params), nil
+}
+
+func (s *jsonSource) getRejects() (string, int64) {
+	return s.params.GetRejects(), s.params.GetMaxRejects()
+}
+
+type parquetSource struct {
+	path   string
+	params *pqparam.ReadParams
+}
+
+// Returns a Source reading the parquet files matching path
+func NewParquetSource(path string, params ...pqparam.ReadParam) Source {
+	return &parquetSource{
+		path:   path,
+		params: pqparam.NewReadParams(params...),
+	}
+}
+
+func (s *parquetSource) Format() Format {
+	return Parquet
+}
+
+func (s *parquetSource) query(ctx context.Context, c *fileconv) (string, func(), error) {
+	return fmt.Sprintf("SELECT * FROM read_parquet(%s %s)", param.QuoteLiteral(s.path), s.params.Params()), func() {}, nil
+}
+
+func (s *parquetSource) describe(ctx context.Context, c *fileconv) (*model.TableDesc, error) {
+	return c.describeParquet(ctx, s.path, s.params)
+}
+
+func (s *parquetSource) getDescribe() bool {
+	return s.params.GetDescribe()
+}
+
+func (s *parquetSource) getTransform() *transformparam.TransformParams {
+	return s.params.GetTransform()
+}
+
+func (s *parquetSource) rejectsQuery() (string, error) {
+	return "", nil
+}
+
+func (s *parquetSource) getRejects() (string, int64) {
+	return "", -1
+}
@@ -0,0 +1,276 @@
+package fileconv
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/hbbtekademy/go-fileconv/pkg/model"
+	"github.com/hbbtekademy/go-fileconv/pkg/param/csvparam"
+	"github.com/hbbtekademy/go-fileconv/pkg/param/jsonparam"
+	"github.com/hbbtekademy/go-fileconv/pkg/param/pqparam"
+	"github.com/hbbtekademy/go-fileconv/pkg/param/transformparam"
+)
+
+var (
+	gzipMagic = []byte{0x1f, 0x8b}
+	zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+)
+
+// Convert csv read from r to a parquet file written to w
+func (c *fileconv) Csv2ParquetStream(ctx context.Context, r io.Reader, w io.Writer, pqWriteParams *pqparam.WriteParams, csvParams ...csvparam.ReadParam) (*ConversionResult, error) {
+	return c.Convert(ctx, NewCsvReaderSource(r, csvParams...), NewParquetWriterSink(w, pqWriteParams))
+}
+
+// Convert json read from r to a parquet file written to w
+func (c *fileconv) Json2ParquetStream(ctx context.Context, r io.Reader, w io.Writer, pqWriteParams *pqparam.WriteParams, jsonParams ...jsonparam.ReadParam) (*ConversionResult, error) {
+	return c.Convert(ctx, NewJsonReaderSource(r, jsonParams...), NewParquetWriterSink(w, pqWriteParams))
+}
+
+// Source reading from an io.Reader.
+// The reader is spooled to a temp file before the conversion as DuckDB can only read files.
+// Gzip and zstd compressed input is detected from the leading bytes of the reader.
+type readerSource struct {
+	format    Format
+	r         io.Reader
+	newSource func(path string) Source
+}
+
+// Returns a Source reading csv from r
+func NewCsvReaderSource(r io.Reader, params ...csvparam.ReadParam) Source {
+	return &readerSource{
+		format:    Csv,
+		r:         r,
+		newSource: func(path string) Source { return NewCsvSource(path, params...) },
+	}
+}
+
+// Returns a Source reading json from r
+func NewJsonReaderSource(r io.Reader, params ...jsonparam.ReadParam) Source {
+	return &readerSource{
+		format:    Json,
+		r:         r,
+		newSource: func(path string) Source { return NewJsonSource(path, params...) },
+	}
+}
+
+// Returns a Source reading a parquet file from r
+func NewParquetReaderSource(r io.Reader, params ...pqparam.ReadParam) Source {
+	return &readerSource{
+		format:    Parquet,
+		r:         r,
+		newSource: func(path string) Source { return NewParquetSource(path, params...) },
+	}
+}
+
+func (s *readerSource) Format() Format {
+	return s.format
+}
+
+func (s *readerSource) query(ctx context.Context, c *fileconv) (string, func(), error) {
+	return "", nil, fmt.Errorf("%s reader source must be spooled before querying", s.format)
+}
+
+func (s *readerSource) describe(ctx context.Context, c *fileconv) (*model.TableDesc, error) {
+	return nil, fmt.Errorf("%s reader source must be spooled before describing", s.format)
+}
+
+func (s *readerSource) getDescribe() bool {
+	return s.newSource("").getDescribe()
+}
+
+func (s *readerSource) getTransform() *transformparam.TransformParams {
+	return s.newSource("").getTransform()
+}
+
+func (s *readerSource) rejectsQuery() (string, error) {
+	return "", fmt.Errorf("%s reader source must be spooled before querying rejects", s.format)
+}
+
+func (s *readerSource) getRejects() (string, int64) {
+	return s.newSource("").getRejects()
+}
+
+// Sink writing to an io.Writer.
+// The output is written to a temp file which is copied to the writer once the conversion completes.
+type writerSink struct {
+	format  Format
+	w       io.Writer
+	newSink func(path string) Sink
+}
+
+// Returns a Sink writing csv to w
+func NewCsvWriterSink(w io.Writer, params *csvparam.WriteParams) Sink {
+	return &writerSink{
+		format:  Csv,
+		w:       w,
+		newSink: func(path string) Sink { return NewCsvSink(path, params) },
+	}
+}
+
+// Returns a Sink writing json to w
+func NewJsonWriterSink(w io.Writer, params *jsonparam.WriteParams) Sink {
+	return &writerSink{
+		format:  Json,
+		w:       w,
+		newSink: func(path string) Sink { return NewJsonSink(path, params) },
+	}
+}
+
+// Returns a Sink writing a parquet file to w.
+// Hive partitioned and per thread output cannot be written to a writer.
+func NewParquetWriterSink(w io.Writer, params *pqparam.WriteParams) Sink {
+	return &writerSink{
+		format:  Parquet,
+		w:       w,
+		newSink: func(path string) Sink { return NewParquetSink(path, params) },
+	}
+}
+
+func (s *writerSink) Format() Format {
+	return s.format
+}
+
+func (s *writerSink) copyCmd(ctx context.Context, c *fileconv, query string) (string, error) {
+	return "", fmt.Errorf("%s writer sink must be opened before copying", s.format)
+}
+
+func (s *writerSink) getPath() string {
+	return ""
+}
+
+func (s *writerSink) isMultiFile() bool {
+	return s.newSink("").isMultiFile()
+}
+
+// Returns a file sink of the writer format writing to path
+func (s *writerSink) withPath(path string) Sink {
+	return s.newSink(path)
+}
+
+func (s *writerSink) getOverwriteOrIgnore() bool {
+	return false
+}
+
+// The temp output of writer sinks is always removed
+func (s *writerSink) getKeepPartialOutput() bool {
+	return false
+}
+
+// Returns the file source to convert. Reader sources are spooled to a temp file.
+// The release func removes the temp file and must be called once the conversion completes.
+func openSource(ctx context.Context, src Source) (Source, func(), error) {
+	rs, ok := src.(*readerSource)
+	if !ok {
+		return src, func() {}, nil
+	}
+
+	tmpDir, err := os.MkdirTemp("", "fileconv-input-*")
+	if err != nil {
+		return src, nil, fmt.Errorf("failed creating temp dir. error: %w", err)
+	}
+	release := func() { os.RemoveAll(tmpDir) }
+
+	br := bufio.NewReader(rs.r)
+	ext := string(rs.format) + compressionExt(br)
+
+	path := filepath.Join(tmpDir, "input."+ext)
+	err = spool(ctx, br, path)
+	if err != nil {
+		release()
+		return src, nil, fmt.Errorf("failed spooling input. error: %w", err)
+	}
+
+	return rs.newSource(path), release, nil
+}
+
+// Returns the file sink to convert to and a func closing the sink.
+// For writer sinks the output is written to a temp file. Closing the sink copies the temp file
+// to the writer if write is true and removes the temp file.
+// For file sinks see openFileSink.
+func openSink(sink Sink) (Sink, func(ctx context.Context, write bool) error, error) {
+	ws, ok := sink.(*writerSink)
+	if !ok {
+		return openFileSink(sink)
+	}
+
+	if ws.isMultiFile() {
+		return nil, nil, fmt.Errorf("%s output to multiple files cannot be written to a writer", ws.format)
+	}
+
+	tmpDir, err := os.MkdirTemp("", "fileconv-output-*")
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed creating temp dir. error: %w", err)
+	}
+
+	path := filepath.Join(tmpDir, "output."+string(ws.format))
+	closeSink := func(ctx context.Context, write bool) error {
+		defer os.RemoveAll(tmpDir)
+		if !write {
+			return nil
+		}
+		return unspool(ctx, path, ws.w)
+	}
+
+	return ws.withPath(path), closeSink, nil
+}
+
+// Returns the file extension of the compression of the buffered input
+// so that DuckDB can auto detect the compression.
+func compressionExt(br *bufio.Reader) string {
+	header, _ := br.Peek(len(zstdMagic))
+
+	switch {
+	case bytes.HasPrefix(header, gzipMagic):
+		return ".gz"
+	case bytes.HasPrefix(header, zstdMagic):
+		return ".zst"
+	default:
+		return ""
+	}
+}
+
+// Copies r to the file at path
+func spool(ctx context.Context, r io.Reader, path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(f, &ctxReader{ctx: ctx, r: r})
+	if err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
+
+// Copies the file at path to w
+func unspool(ctx context.Context, path string, w io.Writer) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = io.Copy(w, &ctxReader{ctx: ctx, r: f})
+	return err
+}
+
+// Reader which stops reading once the context is done
+type ctxReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (r *ctxReader) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return r.r.Read(p)
+}
@@ -0,0 +1,162 @@
+package fileconv
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/hbbtekademy/go-fileconv/pkg/param/csvparam"
+	"github.com/hbbtekademy/go-fileconv/pkg/param/jsonparam"
+	"github.com/hbbtekademy/go-fileconv/pkg/param/pqparam"
+)
+
+func TestConvertStream(t *testing.T) {
+	tests := []struct {
+		name             string
+		convert          func(conv *fileconv, input *os.File, output *os.File) (*ConversionResult, error)
+		input            string
+		outputParquet    string
+		expectedRowCount int
+	}{
+		{
+			name: "TC1",
+			convert: func(conv *fileconv, input *os.File, output *os.File) (*ConversionResult, error) {
+				return conv.Csv2ParquetStream(context.Background(), input, output,
+					pqparam.NewWriteParams(), csvparam.WithHeader(true))
+			},
+			input:            "../../testdata/csv/iris150.csv",
+			outputParquet:    "../../testdata/stream/iris150.parquet",
+			expectedRowCount: 150,
+		},
+		{
+			name: "TC2",
+			convert: func(conv *fileconv, input *os.File, output *os.File) (*ConversionResult, error) {
+				return conv.Json2ParquetStream(context.Background(), input, output,
+					pqparam.NewWriteParams(), jsonparam.WithFormat(jsonparam.Array))
+			},
+			input:            "../../testdata/json/iris150.json",
+			outputParquet:    "../../testdata/stream/iris150_json.parquet",
+			expectedRowCount: 150,
+		},
+	}
+
+	dbFile := fmt.Sprintf("test_%d.db", time.Now().UnixNano())
+	defer os.RemoveAll(dbFile)
+	defer os.RemoveAll(dbFile + ".wal")
+
+	conv, err := newFileconv(context.Background(), dbFile)
+	if err != nil {
+		t.Fatalf("failed getting duckdb client. error: %v", err)
+	}
+	defer conv.Close()
+
+	err = os.MkdirAll("../../testdata/stream", 0755)
+	if err != nil {
+		t.Fatalf("failed creating output dir. error: %v", err)
+	}
+	defer deleteOutput("../../testdata/stream")
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			input, err := os.Open(tc.input)
+			if err != nil {
+				t.Fatalf("failed opening input. error: %v", err)
+			}
+			defer input.Close()
+
+			output, err := os.Create(tc.outputParquet)
+			if err != nil {
+				t.Fatalf("failed creating output. error: %v", err)
+			}
+			defer output.Close()
+
+			result, err := tc.convert(conv, input, output)
+			if err != nil {
+				t.Fatalf("failed converting stream. error: %v", err)
+			}
+			if result.RowsWritten != int64(tc.expectedRowCount) {
+				t.Fatalf("expected rows written: %d but got: %d", tc.expectedRowCount, result.RowsWritten)
+			}
+
+			err = validateOutput(conv, tc.outputParquet, tc.expectedRowCount)
+			if err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestConvertStreamCancelled(t *testing.T) {
+	conv, err := newFileconv(context.Background(), "")
+	if err != nil {
+		t.Fatalf("failed getting duckdb client. error: %v", err)
+	}
+	defer conv.Close()
+
+	input, err := os.Open("../../testdata/csv/iris150.csv")
+	if err != nil {
+		t.Fatalf("failed opening input. error: %v", err)
+	}
+	defer input.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err = conv.Csv2ParquetStream(ctx, input, io.Discard, pqparam.NewWriteParams(), csvparam.WithHeader(true))
+	if err == nil {
+		t.Fatal("expected error converting with cancelled context")
+	}
+}
+
+func TestCompressionExt(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       []byte
+		expectedExt string
+	}{
+		{
+			name:        "TC1",
+			input:       []byte("col1,col2\n1,2\n"),
+			expectedExt: "",
+		},
+		{
+			name:        "TC2",
+			input:       []byte{0x1f, 0x8b, 0x08, 0x00},
+			expectedExt: ".gz",
+		},
+		{
+			name:        "TC3",
+			input:       []byte{0x28, 0xb5, 0x2f, 0xfd, 0x00},
+			expectedExt: ".zst",
+		},
+		{
+			name:        "TC4",
+			input:       []byte{},
+			expectedExt: "",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			br := bufio.NewReader(bytes.NewReader(tc.input))
+			actual := compressionExt(br)
+			if actual != tc.expectedExt {
+				t.Fatalf("expected: %q but got: %q", tc.expectedExt, actual)
+			}
+
+			// peeking must not consume the input
+			rest, err := io.ReadAll(br)
+			if err != nil {
+				t.Fatalf("failed reading input. error: %v", err)
+			}
+			if !bytes.Equal(rest, tc.input) {
+				t.Fatalf("expected input: %v but got: %v", tc.input, rest)
+			}
+		})
+	}
+}
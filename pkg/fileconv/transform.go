@@ -0,0 +1,48 @@
+package fileconv
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hbbtekademy/go-fileconv/pkg/model"
+	"github.com/hbbtekademy/go-fileconv/pkg/param/transformparam"
+)
+
+// Returns the source query with the transformations applied and the column names and types of the transformed rows.
+// The mapping is validated against the source columns and the other transformations against the mapped columns before any rows are read.
+func (c *fileconv) applyTransform(ctx context.Context, query string, transform *transformparam.TransformParams) (string, *model.TableDesc, error) {
+	tableDesc, err := c.GetTableDesc(ctx, query)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed getting source desc. error: %w", err)
+	}
+
+	if transform.IsEmpty() {
+		return query, tableDesc, nil
+	}
+
+	if mapping := transform.GetMapping(); mapping != nil {
+		err = mapping.Validate(tableDesc)
+		if err != nil {
+			return "", nil, fmt.Errorf("invalid mapping. error: %w", err)
+		}
+
+		query = mapping.Query(query, tableDesc)
+		tableDesc, err = c.GetTableDesc(ctx, query)
+		if err != nil {
+			return "", nil, fmt.Errorf("invalid mapping. error: %w", err)
+		}
+	}
+
+	err = transform.Validate(tableDesc)
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid transform. error: %w", err)
+	}
+
+	query = transform.Query(query)
+	tableDesc, err = c.GetTableDesc(ctx, query)
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid transform. where: %s. error: %w", transform.GetWhere(), err)
+	}
+
+	return query, tableDesc, nil
+}
@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"github.com/hbbtekademy/go-fileconv/pkg/model"
+	"github.com/hbbtekademy/go-fileconv/pkg/param"
 )
 
 func (c *fileconv) FlattenStructColumn(ctx context.Context, columnDesc *model.ColumnDesc) ([]*model.ColumnDesc, error) {
@@ -20,7 +21,7 @@ func (c *fileconv) FlattenStructColumn(ctx context.Context, columnDesc *model.Co
 	}
 	defer c.dropTable(ctx, tableName)
 
-	tableDesc, err := c.GetTableDesc(ctx, fmt.Sprintf("SELECT C1.* FROM %s", tableName))
+	tableDesc, err := c.GetTableDesc(ctx, fmt.Sprintf("SELECT C1.* FROM %s", param.QuoteIdent(tableName)))
 	if err != nil {
 		return nil, err
 	}
@@ -52,62 +53,18 @@ func (c *fileconv) FlattenStructColumn(ctx context.Context, columnDesc *model.Co
 	return columns, nil
 }
 
-func (c *fileconv) getFlattenedTableSelect(ctx context.Context, tableName string) (string, error) {
-
-	tableDesc, err := c.GetTableDesc(ctx, tableName)
-	if err != nil {
-		return "", fmt.Errorf("failed getting imported json table desc. error: %w", err)
-	}
-
-	flattenedColumns := []*model.ColumnDesc{}
-	for i := range tableDesc.ColumnDescs {
-		if tableDesc.ColumnDescs[i].ColType.IsStruct() {
-			cols, err := c.FlattenStructColumn(ctx, tableDesc.ColumnDescs[i])
-			if err != nil {
-				return "", fmt.Errorf("failed flattening column: %s. error: %w",
-					tableDesc.ColumnDescs[i].ColName, err)
-			}
-			flattenedColumns = append(flattenedColumns, cols...)
-			continue
-		}
-		flattenedColumns = append(flattenedColumns, tableDesc.ColumnDescs[i])
-
-	}
-
-	unnestedCols, err := tableDesc.GetUnnestedColumns()
-	if err != nil {
-		return "", fmt.Errorf("failed getting unnested columns. error: %w", err)
-	}
-
-	unnestedTableSelect := fmt.Sprintf("SELECT %s FROM %s", unnestedCols, tableName)
-	unnestedTableDesc, err := c.GetTableDesc(ctx, unnestedTableSelect)
-	if err != nil {
-		return "", fmt.Errorf("failed getting unnested table desc. error: %w", err)
-	}
-
-	if len(unnestedTableDesc.ColumnDescs) != len(flattenedColumns) {
-		return "", fmt.Errorf("unnested table columns and flattened columns not matching. unnested table cols: %v, flattened cols: %v",
-			unnestedTableDesc.ColumnDescs, flattenedColumns)
-	}
-
-	l := len(flattenedColumns)
-	var sb strings.Builder
-
-	sb.WriteString("SELECT ")
-	for i := range flattenedColumns {
-		sb.WriteString(fmt.Sprintf("%s AS %s",
-			unnestedTableDesc.ColumnDescs[i].ColName,
-			flattenedColumns[i].ColName))
+func getDescribeQuery(table string) string {
+	return fmt.Sprintf("SELECT COLUMN_NAME, COLUMN_TYPE FROM (DESCRIBE %s)", table)
+}
 
-		if i < l-1 {
-			sb.WriteRune(',')
-		}
+// Returns the query selecting the columns of query cast to VARCHAR and named v0 to vN
+func getVarcharQuery(query string, numCols int) string {
+	names := make([]string, 0, numCols)
+	cols := make([]string, 0, numCols)
+	for i := 0; i < numCols; i++ {
+		names = append(names, fmt.Sprintf("v%d", i))
+		cols = append(cols, fmt.Sprintf("CAST(v%d AS VARCHAR) AS v%d", i, i))
 	}
 
-	sb.WriteString(fmt.Sprintf(" FROM (%s)", unnestedTableSelect))
-	return sb.String(), nil
-}
-
-func getDescribeQuery(table string) string {
-	return fmt.Sprintf("SELECT COLUMN_NAME, COLUMN_TYPE FROM (DESCRIBE %s)", table)
+	return fmt.Sprintf("SELECT %s FROM (%s) AS t(%s)", strings.Join(cols, ","), query, strings.Join(names, ","))
 }
@@ -20,10 +20,11 @@ func TestFlattenStructColumn(t *testing.T) {
 	defer os.RemoveAll(dbFile)
 	defer os.RemoveAll(dbFile + ".wal")
 
-	conv, err := New(context.Background(), dbFile)
+	conv, err := newFileconv(context.Background(), dbFile)
 	if err != nil {
 		t.Fatalf("failed getting converter. error: %v", err)
 	}
+	defer conv.Close()
 
 	cols, err := conv.FlattenStructColumn(context.Background(), colDesc)
 	if err != nil {
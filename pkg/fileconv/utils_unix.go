@@ -4,10 +4,12 @@ package fileconv
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"time"
 
 	"github.com/hbbtekademy/go-fileconv/pkg/model"
+	"github.com/hbbtekademy/go-fileconv/pkg/param"
 )
 
 func (c *fileconv) GetTableDesc(ctx context.Context, table string) (*model.TableDesc, error) {
@@ -35,7 +37,7 @@ func (c *fileconv) GetTableDesc(ctx context.Context, table string) (*model.Table
 
 func (c *fileconv) createStructColTable(ctx context.Context, columnDesc *model.ColumnDesc) (string, error) {
 	tableName := fmt.Sprintf("%s_tmp_%d", columnDesc.ColName, time.Now().UnixNano())
-	_, err := c.db.ExecContext(ctx, fmt.Sprintf("CREATE TABLE %s (C1 %s)", tableName, columnDesc.ColType))
+	_, err := c.db.ExecContext(ctx, fmt.Sprintf("CREATE TABLE %s (C1 %s)", param.QuoteIdent(tableName), columnDesc.ColType))
 	if err != nil {
 		return "", err
 	}
@@ -44,7 +46,7 @@ func (c *fileconv) createStructColTable(ctx context.Context, columnDesc *model.C
 }
 
 func (c *fileconv) dropTable(ctx context.Context, tableName string) error {
-	_, err := c.db.ExecContext(ctx, fmt.Sprintf("DROP TABLE %s", tableName))
+	_, err := c.db.ExecContext(ctx, fmt.Sprintf("DROP TABLE %s", param.QuoteIdent(tableName)))
 	return err
 }
 
@@ -56,3 +58,76 @@ func (c *fileconv) executeCmd(ctx context.Context, cmd string) error {
 
 	return nil
 }
+
+// Executes a COPY command between beforeCmds and afterCmds and returns the number of rows copied.
+func (c *fileconv) executeCopyCmd(ctx context.Context, cmd string, beforeCmds []string, afterCmds []string) (int64, error) {
+	// the reject tables are tracked per connection, so all the commands must run on the same connection
+	conn, err := c.db.Conn(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close()
+
+	for _, beforeCmd := range beforeCmds {
+		_, err := conn.ExecContext(ctx, beforeCmd)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	result, err := conn.ExecContext(ctx, cmd)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, afterCmd := range afterCmds {
+		_, err := conn.ExecContext(ctx, afterCmd)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return result.RowsAffected()
+}
+
+// Executes a query returning a single count
+func (c *fileconv) queryCount(ctx context.Context, query string) (int64, error) {
+	var count sql.NullInt64
+	err := c.db.QueryRowContext(ctx, query).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+
+	return count.Int64, nil
+}
+
+// Executes a query selecting numCols columns and returns its rows with the values as strings. NULL values are nil.
+func (c *fileconv) queryStrings(ctx context.Context, query string, numCols int) ([][]*string, error) {
+	rows, err := c.db.QueryContext(ctx, getVarcharQuery(query, numCols))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := [][]*string{}
+	for rows.Next() {
+		values := make([]sql.NullString, numCols)
+		dest := make([]any, numCols)
+		for i := range values {
+			dest[i] = &values[i]
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+
+		row := make([]*string, numCols)
+		for i := range values {
+			if values[i].Valid {
+				row[i] = &values[i].String
+			}
+		}
+		result = append(result, row)
+	}
+
+	return result, rows.Err()
+}
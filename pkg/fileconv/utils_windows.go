@@ -7,9 +7,11 @@ import (
 	"fmt"
 	"io"
 	"os/exec"
+	"strings"
 	"time"
 
 	"github.com/hbbtekademy/go-fileconv/pkg/model"
+	"github.com/hbbtekademy/go-fileconv/pkg/param"
 )
 
 func (c *fileconv) GetTableDesc(ctx context.Context, table string) (*model.TableDesc, error) {
@@ -38,7 +40,7 @@ func (c *fileconv) GetTableDesc(ctx context.Context, table string) (*model.Table
 
 func (c *fileconv) createStructColTable(ctx context.Context, columnDesc *model.ColumnDesc) (string, error) {
 	tableName := fmt.Sprintf("%s_tmp_%d", columnDesc.ColName, time.Now().UnixNano())
-	_, stderr, err := c.execDuckDbCli(ctx, []string{fmt.Sprintf("CREATE TABLE %s (C1 %s)", tableName, columnDesc.ColType)})
+	_, stderr, err := c.execDuckDbCli(ctx, []string{fmt.Sprintf("CREATE TABLE %s (C1 %s)", param.QuoteIdent(tableName), columnDesc.ColType)})
 	if err != nil {
 		return "", fmt.Errorf("failed creating table: %s. stderr: %s. error: %v", tableName, stderr, err)
 	}
@@ -47,7 +49,7 @@ func (c *fileconv) createStructColTable(ctx context.Context, columnDesc *model.C
 }
 
 func (c *fileconv) dropTable(ctx context.Context, tableName string) error {
-	_, stderr, err := c.execDuckDbCli(ctx, []string{}, "-c", fmt.Sprintf("DROP TABLE %s", tableName))
+	_, stderr, err := c.execDuckDbCli(ctx, []string{}, "-c", fmt.Sprintf("DROP TABLE %s", param.QuoteIdent(tableName)))
 	if err != nil {
 		return fmt.Errorf("failed dropping table: %s. stderr: %s. error: %v", tableName, stderr, err)
 	}
@@ -63,6 +65,90 @@ func (c *fileconv) executeCmd(ctx context.Context, cmd string) error {
 	return nil
 }
 
+// Executes a COPY command between beforeCmds and afterCmds in the same duckdb process and returns the number of rows copied
+func (c *fileconv) executeCopyCmd(ctx context.Context, cmd string, beforeCmds []string, afterCmds []string) (int64, error) {
+	cmds := []string{}
+	for _, beforeCmd := range beforeCmds {
+		cmds = append(cmds, beforeCmd+";\n")
+	}
+	cmds = append(cmds, cmd+";\n")
+	for _, afterCmd := range afterCmds {
+		cmds = append(cmds, afterCmd+";\n")
+	}
+
+	stdout, stderr, err := c.execDuckDbCli(ctx, cmds, "-json")
+	if err != nil {
+		return 0, fmt.Errorf("failed executing cmd: %s. stderr: %s. error: %v", cmd, stderr, err)
+	}
+
+	counts := []struct {
+		Count int64 `json:"Count"`
+	}{}
+	if len(strings.TrimSpace(stdout)) == 0 {
+		return 0, nil
+	}
+	// the count of the COPY command is the first result
+	err = json.NewDecoder(strings.NewReader(stdout)).Decode(&counts)
+	if err != nil {
+		return 0, fmt.Errorf("failed unmarshalling copy count json. error: %v", err)
+	}
+	if len(counts) == 0 {
+		return 0, nil
+	}
+
+	return counts[0].Count, nil
+}
+
+// Executes a query returning a single count
+func (c *fileconv) queryCount(ctx context.Context, query string) (int64, error) {
+	stdout, stderr, err := c.execDuckDbCli(ctx, []string{fmt.Sprintf("SELECT (%s) AS count;", query)}, "-json")
+	if err != nil {
+		return 0, fmt.Errorf("failed executing query: %s. stderr: %s. error: %v", query, stderr, err)
+	}
+
+	counts := []struct {
+		Count int64 `json:"count"`
+	}{}
+	err = json.Unmarshal([]byte(stdout), &counts)
+	if err != nil {
+		return 0, fmt.Errorf("failed unmarshalling count json. error: %v", err)
+	}
+	if len(counts) == 0 {
+		return 0, nil
+	}
+
+	return counts[0].Count, nil
+}
+
+// Executes a query selecting numCols columns and returns its rows with the values as strings. NULL values are nil.
+func (c *fileconv) queryStrings(ctx context.Context, query string, numCols int) ([][]*string, error) {
+	stdout, stderr, err := c.execDuckDbCli(ctx, []string{getVarcharQuery(query, numCols) + ";"}, "-json")
+	if err != nil {
+		return nil, fmt.Errorf("failed executing query: %s. stderr: %s. error: %v", query, stderr, err)
+	}
+
+	result := [][]*string{}
+	if len(strings.TrimSpace(stdout)) == 0 {
+		return result, nil
+	}
+
+	rows := []map[string]*string{}
+	err = json.Unmarshal([]byte(stdout), &rows)
+	if err != nil {
+		return nil, fmt.Errorf("failed unmarshalling query json. error: %v", err)
+	}
+
+	for _, values := range rows {
+		row := make([]*string, numCols)
+		for i := range row {
+			row[i] = values[fmt.Sprintf("v%d", i)]
+		}
+		result = append(result, row)
+	}
+
+	return result, nil
+}
+
 func (c *fileconv) execDuckDbCli(ctx context.Context, cmds []string, args ...string) (string, string, error) {
 	duckdbArgs := make([]string, 0, len(args)+1)
 	duckdbArgs = append(duckdbArgs, c.dbFile)
@@ -0,0 +1,213 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Avro record schema
+type AvroSchema struct {
+	Type   string       `json:"type"`
+	Name   string       `json:"name"`
+	Fields []*AvroField `json:"fields"`
+}
+
+// Field of an Avro record. The type is a type name, a schema or a union of them.
+// The doc holds the column name of the fields whose name was sanitized.
+type AvroField struct {
+	Name    string `json:"name"`
+	Doc     string `json:"doc,omitempty"`
+	Type    any    `json:"type"`
+	Default any    `json:"default"`
+}
+
+/*
+Returns the columns as an Avro record schema with the name, which is sanitized to a valid Avro name.
+Column and STRUCT field names which are not valid Avro names are sanitized and made unique, with the original name kept in the doc of the field.
+All the fields are nullable with a null default. STRUCT columns are records named after the path of their column,
+LIST and ARRAY columns are arrays and MAP columns with VARCHAR keys are maps.
+Temporal, DECIMAL and UUID columns have logical types. TIMESTAMP columns are local timestamps, and the nanoseconds of TIMESTAMP_NS are truncated to microseconds.
+*/
+func (t *TableDesc) AvroSchema(name string) (*AvroSchema, error) {
+	name = avroName(name)
+	schema := &AvroSchema{Type: "record", Name: name, Fields: []*AvroField{}}
+
+	names := make([]string, 0, len(t.ColumnDescs))
+	for _, colDesc := range t.ColumnDescs {
+		names = append(names, colDesc.ColName)
+	}
+	fieldNames := avroFieldNames(names)
+
+	for i, colDesc := range t.ColumnDescs {
+		dataType, err := colDesc.ColType.Parse()
+		if err != nil {
+			return nil, err
+		}
+
+		field, err := avroField(colDesc.ColName, fieldNames[i], name+"_"+fieldNames[i], dataType)
+		if err != nil {
+			return nil, fmt.Errorf("unsupported type of column %q. error: %w", colDesc.ColName, err)
+		}
+		schema.Fields = append(schema.Fields, field)
+	}
+
+	return schema, nil
+}
+
+// Returns the nullable field named fieldName, the avro name of the column or STRUCT field name.
+// path is the unique name of the named types of the field.
+func avroField(name string, fieldName string, path string, dataType *DataType) (*AvroField, error) {
+	avroType, err := avroTypeOf(path, dataType)
+	if err != nil {
+		return nil, err
+	}
+
+	field := &AvroField{Name: fieldName, Type: []any{"null", avroType}}
+	if fieldName != name {
+		field.Doc = name
+	}
+	return field, nil
+}
+
+// Returns the avro names of the fields. Valid names are kept, and the other names are sanitized
+// with avroName and suffixed with a number if the sanitized name is already used by another field.
+func avroFieldNames(names []string) []string {
+	fieldNames := make([]string, len(names))
+	used := map[string]bool{}
+	for i, name := range names {
+		if isAvroName(name) && !used[name] {
+			fieldNames[i] = name
+			used[name] = true
+		}
+	}
+
+	for i, name := range names {
+		if fieldNames[i] != "" {
+			continue
+		}
+
+		fieldName := avroName(name)
+		for n := 2; used[fieldName]; n++ {
+			fieldName = fmt.Sprintf("%s_%d", avroName(name), n)
+		}
+		fieldNames[i] = fieldName
+		used[fieldName] = true
+	}
+	return fieldNames
+}
+
+func avroTypeOf(path string, dataType *DataType) (any, error) {
+	switch dataType.Name {
+	case TypeStruct:
+		record := &AvroSchema{Type: "record", Name: path, Fields: []*AvroField{}}
+		names := make([]string, 0, len(dataType.Fields))
+		for _, f := range dataType.Fields {
+			names = append(names, f.Name)
+		}
+		fieldNames := avroFieldNames(names)
+
+		for i, f := range dataType.Fields {
+			field, err := avroField(f.Name, fieldNames[i], path+"_"+fieldNames[i], f.Type)
+			if err != nil {
+				return nil, err
+			}
+			record.Fields = append(record.Fields, field)
+		}
+		return record, nil
+
+	case TypeList, TypeArray:
+		items, err := avroTypeOf(path, dataType.Elem)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]any{"type": "array", "items": []any{"null", items}}, nil
+
+	case TypeMap:
+		if dataType.Key.Name != "VARCHAR" {
+			return nil, fmt.Errorf("avro maps must have VARCHAR keys: %s", dataType)
+		}
+		values, err := avroTypeOf(path, dataType.Value)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]any{"type": "map", "values": []any{"null", values}}, nil
+
+	case TypeDecimal:
+		return map[string]any{"type": "bytes", "logicalType": "decimal", "precision": dataType.Precision, "scale": dataType.Scale}, nil
+
+	case "ENUM":
+		symbols := enumValues(dataType.Params)
+		for _, symbol := range symbols {
+			if !isAvroName(symbol) {
+				// enums with values which are not valid avro symbols are strings
+				return "string", nil
+			}
+		}
+		return map[string]any{"type": "enum", "name": path, "symbols": symbols}, nil
+
+	case "INTERVAL":
+		return map[string]any{"type": "fixed", "name": path, "size": 12, "logicalType": "duration"}, nil
+	}
+
+	avroType, ok := avroTypes[dataType.Name]
+	if !ok {
+		return nil, fmt.Errorf("no avro type for: %s", dataType)
+	}
+	return avroType, nil
+}
+
+var avroTypes = map[string]any{
+	"BOOLEAN":                  "boolean",
+	"TINYINT":                  "int",
+	"SMALLINT":                 "int",
+	"INTEGER":                  "int",
+	"UTINYINT":                 "int",
+	"USMALLINT":                "int",
+	"BIGINT":                   "long",
+	"UINTEGER":                 "long",
+	"UBIGINT":                  map[string]any{"type": "bytes", "logicalType": "decimal", "precision": 20, "scale": 0},
+	"HUGEINT":                  map[string]any{"type": "bytes", "logicalType": "decimal", "precision": 39, "scale": 0},
+	"UHUGEINT":                 map[string]any{"type": "bytes", "logicalType": "decimal", "precision": 39, "scale": 0},
+	"FLOAT":                    "float",
+	"DOUBLE":                   "double",
+	"VARCHAR":                  "string",
+	"JSON":                     "string",
+	"UUID":                     map[string]any{"type": "string", "logicalType": "uuid"},
+	"BLOB":                     "bytes",
+	"BIT":                      "bytes",
+	"DATE":                     map[string]any{"type": "int", "logicalType": "date"},
+	"TIME":                     map[string]any{"type": "long", "logicalType": "time-micros"},
+	"TIMESTAMP":                map[string]any{"type": "long", "logicalType": "local-timestamp-micros"},
+	"TIMESTAMP_S":              map[string]any{"type": "long", "logicalType": "local-timestamp-millis"},
+	"TIMESTAMP_MS":             map[string]any{"type": "long", "logicalType": "local-timestamp-millis"},
+	"TIMESTAMP_NS":             map[string]any{"type": "long", "logicalType": "local-timestamp-micros"},
+	"TIMESTAMP WITH TIME ZONE": map[string]any{"type": "long", "logicalType": "timestamp-micros"},
+}
+
+func isAvroName(name string) bool {
+	if name == "" || name[0] >= '0' && name[0] <= '9' {
+		return false
+	}
+	for _, c := range name {
+		if !(c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9') {
+			return false
+		}
+	}
+	return true
+}
+
+// Returns the name with the characters which are not valid in avro names replaced with _
+func avroName(name string) string {
+	var sb strings.Builder
+	for i, c := range name {
+		if c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || i > 0 && c >= '0' && c <= '9' {
+			sb.WriteRune(c)
+		} else {
+			sb.WriteRune('_')
+		}
+	}
+	if sb.Len() == 0 {
+		return "_"
+	}
+	return sb.String()
+}
@@ -0,0 +1,418 @@
+package model
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// DuckDB type parsed from a type string, e.g. STRUCT(a BIGINT, b VARCHAR[]) or DECIMAL(18,3)
+type DataType struct {
+	// Type name without parameters in upper case, e.g. VARCHAR, DECIMAL, STRUCT, LIST, ARRAY, MAP
+	Name string
+	// Precision and scale of a DECIMAL
+	Precision int
+	Scale     int
+	// Fields of a STRUCT or UNION
+	Fields []*Field
+	// Element type of a LIST or ARRAY
+	Elem *DataType
+	// Size of a fixed size ARRAY
+	Size int
+	// Key and value types of a MAP
+	Key   *DataType
+	Value *DataType
+	// Parameters of the other parameterized types, e.g. the values of an ENUM
+	Params string
+}
+
+// Field of a STRUCT or UNION
+type Field struct {
+	Name string
+	Type *DataType
+}
+
+const (
+	TypeStruct  string = "STRUCT"
+	TypeUnion   string = "UNION"
+	TypeList    string = "LIST"
+	TypeArray   string = "ARRAY"
+	TypeMap     string = "MAP"
+	TypeDecimal string = "DECIMAL"
+)
+
+// Parses the column type
+func (ct ColumnType) Parse() (*DataType, error) {
+	return ParseType(string(ct))
+}
+
+// Parses a DuckDB type string as returned by DESCRIBE
+func ParseType(s string) (*DataType, error) {
+	p := &typeParser{s: s}
+	dataType, err := p.parseType()
+	if err != nil {
+		return nil, fmt.Errorf("invalid type: %s. error: %w", s, err)
+	}
+
+	p.skipSpaces()
+	if p.pos < len(p.s) {
+		return nil, fmt.Errorf("invalid type: %s. error: unexpected %q at %d", s, p.s[p.pos:], p.pos)
+	}
+
+	return dataType, nil
+}
+
+// Parses the type string and validates the type names against the DuckDB types
+func ValidateType(s string) error {
+	dataType, err := ParseType(s)
+	if err != nil {
+		return err
+	}
+	if err := dataType.Validate(); err != nil {
+		return fmt.Errorf("invalid type: %s. error: %w", s, err)
+	}
+	return nil
+}
+
+// Validates that the type and its nested types are DuckDB types or their aliases
+func (t *DataType) Validate() error {
+	switch t.Name {
+	case TypeStruct, TypeUnion:
+		names := map[string]bool{}
+		for _, field := range t.Fields {
+			if names[strings.ToLower(field.Name)] {
+				return fmt.Errorf("duplicate field name: %s", field.Name)
+			}
+			names[strings.ToLower(field.Name)] = true
+			if err := field.Type.Validate(); err != nil {
+				return err
+			}
+		}
+		return nil
+	case TypeList:
+		return t.Elem.Validate()
+	case TypeArray:
+		if t.Size <= 0 {
+			return fmt.Errorf("array size must be positive: %d", t.Size)
+		}
+		return t.Elem.Validate()
+	case TypeMap:
+		if err := t.Key.Validate(); err != nil {
+			return err
+		}
+		return t.Value.Validate()
+	case TypeDecimal:
+		if t.Precision < 1 || t.Precision > 38 {
+			return fmt.Errorf("decimal precision must be between 1 and 38: %d", t.Precision)
+		}
+		if t.Scale < 0 || t.Scale > t.Precision {
+			return fmt.Errorf("decimal scale must be between 0 and the precision: %d", t.Scale)
+		}
+		return nil
+	case "ENUM":
+		if strings.TrimSpace(t.Params) == "" {
+			return fmt.Errorf("enum values missing")
+		}
+		if !enumParams.MatchString(t.Params) {
+			return fmt.Errorf("enum values must be quoted strings: %s", t.Params)
+		}
+		return nil
+	}
+
+	if !duckdbTypes[t.Name] {
+		return fmt.Errorf("unknown type: %s", t.Name)
+	}
+	switch t.Name {
+	case "VARCHAR", "CHAR", "BPCHAR", "TEXT", "STRING":
+		// the length of a VARCHAR is accepted and ignored by DuckDB
+		if t.Params != "" && !lengthParams.MatchString(t.Params) {
+			return fmt.Errorf("type %s takes a length: %s", t.Name, t.Params)
+		}
+	default:
+		if t.Params != "" {
+			return fmt.Errorf("type %s does not take parameters: %s", t.Name, t.Params)
+		}
+	}
+	return nil
+}
+
+var (
+	// Single quoted values with quotes escaped by doubling them, e.g. 'a', 'it''s'
+	enumParams   = regexp.MustCompile(`^\s*'(?:[^']|'')*'(?:\s*,\s*'(?:[^']|'')*')*\s*$`)
+	lengthParams = regexp.MustCompile(`^\s*[0-9]+\s*$`)
+)
+
+// Names of the DuckDB types without parameters and their aliases
+var duckdbTypes = map[string]bool{
+	"BIGINT": true, "INT8": true, "LONG": true,
+	"INTEGER": true, "INT4": true, "INT": true, "SIGNED": true,
+	"SMALLINT": true, "INT2": true, "SHORT": true,
+	"TINYINT": true, "INT1": true,
+	"HUGEINT": true, "UBIGINT": true, "UINTEGER": true, "USMALLINT": true, "UTINYINT": true, "UHUGEINT": true,
+	"DOUBLE": true, "FLOAT8": true,
+	"FLOAT": true, "FLOAT4": true, "REAL": true,
+	"BOOLEAN": true, "BOOL": true, "LOGICAL": true,
+	"VARCHAR": true, "CHAR": true, "BPCHAR": true, "TEXT": true, "STRING": true,
+	"BLOB": true, "BYTEA": true, "BINARY": true, "VARBINARY": true,
+	"BIT": true, "BITSTRING": true,
+	"DATE": true, "TIME": true, "TIMETZ": true, "TIME WITH TIME ZONE": true,
+	"TIMESTAMP": true, "DATETIME": true, "TIMESTAMP_S": true, "TIMESTAMP_MS": true, "TIMESTAMP_NS": true,
+	"TIMESTAMPTZ": true, "TIMESTAMP WITH TIME ZONE": true,
+	"INTERVAL": true, "UUID": true, "JSON": true,
+}
+
+// Returns the DuckDB type string of the type
+func (t *DataType) String() string {
+	switch t.Name {
+	case TypeStruct, TypeUnion:
+		fields := make([]string, 0, len(t.Fields))
+		for _, field := range t.Fields {
+			fields = append(fields, fmt.Sprintf("%s %s", quoteFieldName(field.Name), field.Type))
+		}
+		return fmt.Sprintf("%s(%s)", t.Name, strings.Join(fields, ", "))
+	case TypeList:
+		return t.Elem.String() + "[]"
+	case TypeArray:
+		return fmt.Sprintf("%s[%d]", t.Elem, t.Size)
+	case TypeMap:
+		return fmt.Sprintf("MAP(%s, %s)", t.Key, t.Value)
+	case TypeDecimal:
+		return fmt.Sprintf("DECIMAL(%d,%d)", t.Precision, t.Scale)
+	}
+
+	if t.Params != "" {
+		return fmt.Sprintf("%s(%s)", t.Name, t.Params)
+	}
+	return t.Name
+}
+
+type typeParser struct {
+	s   string
+	pos int
+}
+
+func (p *typeParser) parseType() (*DataType, error) {
+	p.skipSpaces()
+	start := p.pos
+	for p.pos < len(p.s) && !strings.ContainsRune("(),[]", rune(p.s[p.pos])) {
+		p.pos++
+	}
+	name := strings.ToUpper(strings.TrimSpace(p.s[start:p.pos]))
+	if name == "" {
+		return nil, fmt.Errorf("missing type name at %d", start)
+	}
+
+	dataType := &DataType{Name: name}
+	if p.peek() == '(' {
+		p.pos++
+		var err error
+		switch name {
+		case TypeStruct, TypeUnion:
+			dataType.Fields, err = p.parseFields()
+		case TypeMap:
+			dataType.Key, dataType.Value, err = p.parseMap()
+		case TypeDecimal, "NUMERIC":
+			dataType.Name = TypeDecimal
+			dataType.Precision, dataType.Scale, err = p.parseDecimal()
+		default:
+			dataType.Params, err = p.parseParams()
+		}
+		if err != nil {
+			return nil, err
+		}
+	} else if name == TypeDecimal || name == "NUMERIC" {
+		// DuckDB defaults DECIMAL to DECIMAL(18,3)
+		dataType.Name, dataType.Precision, dataType.Scale = TypeDecimal, 18, 3
+	}
+
+	for p.peek() == '[' {
+		p.pos++
+		end := strings.IndexByte(p.s[p.pos:], ']')
+		if end < 0 {
+			return nil, fmt.Errorf("missing ] at %d", p.pos)
+		}
+		size := strings.TrimSpace(p.s[p.pos : p.pos+end])
+		p.pos += end + 1
+
+		if size == "" {
+			dataType = &DataType{Name: TypeList, Elem: dataType}
+			continue
+		}
+		n, err := strconv.Atoi(size)
+		if err != nil {
+			return nil, fmt.Errorf("invalid array size: %s", size)
+		}
+		dataType = &DataType{Name: TypeArray, Elem: dataType, Size: n}
+	}
+
+	return dataType, nil
+}
+
+func (p *typeParser) parseFields() ([]*Field, error) {
+	fields := []*Field{}
+	for {
+		p.skipSpaces()
+		name, err := p.parseFieldName()
+		if err != nil {
+			return nil, err
+		}
+		fieldType, err := p.parseType()
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, &Field{Name: name, Type: fieldType})
+
+		done, err := p.parseSeparator()
+		if err != nil {
+			return nil, err
+		}
+		if done {
+			return fields, nil
+		}
+	}
+}
+
+// Parses a field name, which is double quoted if it is not a plain identifier
+func (p *typeParser) parseFieldName() (string, error) {
+	if p.peek() != '"' {
+		start := p.pos
+		for p.pos < len(p.s) && p.s[p.pos] != ' ' {
+			p.pos++
+		}
+		if p.pos == start {
+			return "", fmt.Errorf("missing field name at %d", start)
+		}
+		return p.s[start:p.pos], nil
+	}
+
+	var sb strings.Builder
+	p.pos++
+	for p.pos < len(p.s) {
+		c := p.s[p.pos]
+		p.pos++
+		if c != '"' {
+			sb.WriteByte(c)
+			continue
+		}
+		if p.peek() == '"' {
+			sb.WriteByte('"')
+			p.pos++
+			continue
+		}
+		return sb.String(), nil
+	}
+
+	return "", fmt.Errorf("unterminated field name")
+}
+
+func (p *typeParser) parseMap() (*DataType, *DataType, error) {
+	key, err := p.parseType()
+	if err != nil {
+		return nil, nil, err
+	}
+	if p.peek() != ',' {
+		return nil, nil, fmt.Errorf("missing , after map key type at %d", p.pos)
+	}
+	p.pos++
+
+	value, err := p.parseType()
+	if err != nil {
+		return nil, nil, err
+	}
+	if p.peek() != ')' {
+		return nil, nil, fmt.Errorf("missing ) after map value type at %d", p.pos)
+	}
+	p.pos++
+
+	return key, value, nil
+}
+
+func (p *typeParser) parseDecimal() (int, int, error) {
+	params, err := p.parseParams()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	parts := strings.Split(params, ",")
+	precision, err := strconv.Atoi(strings.TrimSpace(parts[0]))
+	if err != nil || len(parts) > 2 {
+		return 0, 0, fmt.Errorf("invalid decimal params: %s", params)
+	}
+
+	scale := 0
+	if len(parts) == 2 {
+		scale, err = strconv.Atoi(strings.TrimSpace(parts[1]))
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid decimal params: %s", params)
+		}
+	}
+
+	return precision, scale, nil
+}
+
+// Returns the raw params up to the closing parenthesis, skipping quoted strings
+func (p *typeParser) parseParams() (string, error) {
+	start := p.pos
+	depth := 0
+	for p.pos < len(p.s) {
+		switch c := p.s[p.pos]; c {
+		case '\'', '"':
+			end := strings.IndexByte(p.s[p.pos+1:], c)
+			if end < 0 {
+				return "", fmt.Errorf("unterminated string at %d", p.pos)
+			}
+			p.pos += end + 1
+		case '(':
+			depth++
+		case ')':
+			if depth == 0 {
+				params := p.s[start:p.pos]
+				p.pos++
+				return params, nil
+			}
+			depth--
+		}
+		p.pos++
+	}
+
+	return "", fmt.Errorf("missing ) at %d", p.pos)
+}
+
+// Parses a , or the closing ) of a list. Returns true if the list is closed.
+func (p *typeParser) parseSeparator() (bool, error) {
+	switch p.peek() {
+	case ',':
+		p.pos++
+		return false, nil
+	case ')':
+		p.pos++
+		return true, nil
+	default:
+		return false, fmt.Errorf("expected , or ) at %d", p.pos)
+	}
+}
+
+// Returns the next non space character without consuming it
+func (p *typeParser) peek() byte {
+	p.skipSpaces()
+	if p.pos >= len(p.s) {
+		return 0
+	}
+	return p.s[p.pos]
+}
+
+func (p *typeParser) skipSpaces() {
+	for p.pos < len(p.s) && p.s[p.pos] == ' ' {
+		p.pos++
+	}
+}
+
+func quoteFieldName(name string) string {
+	for _, c := range name {
+		if !(c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9') {
+			return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+		}
+	}
+	return name
+}
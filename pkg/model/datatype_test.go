@@ -0,0 +1,129 @@
+package model
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseType(t *testing.T) {
+	tests := []struct {
+		name           string
+		input          string
+		expectedOutput *DataType
+		expectedString string
+	}{
+		{
+			name:           "TC1",
+			input:          "BIGINT",
+			expectedOutput: &DataType{Name: "BIGINT"},
+			expectedString: "BIGINT",
+		},
+		{
+			name:           "TC2",
+			input:          "decimal(10, 2)",
+			expectedOutput: &DataType{Name: TypeDecimal, Precision: 10, Scale: 2},
+			expectedString: "DECIMAL(10,2)",
+		},
+		{
+			name:           "TC3",
+			input:          "TIMESTAMP WITH TIME ZONE[]",
+			expectedOutput: &DataType{Name: TypeList, Elem: &DataType{Name: "TIMESTAMP WITH TIME ZONE"}},
+			expectedString: "TIMESTAMP WITH TIME ZONE[]",
+		},
+		{
+			name:  "TC4",
+			input: `STRUCT(a VARCHAR, "b c" STRUCT(d BIGINT[3]), "e""f" MAP(VARCHAR, DOUBLE[]))[]`,
+			expectedOutput: &DataType{Name: TypeList, Elem: &DataType{Name: TypeStruct, Fields: []*Field{
+				{Name: "a", Type: &DataType{Name: "VARCHAR"}},
+				{Name: "b c", Type: &DataType{Name: TypeStruct, Fields: []*Field{
+					{Name: "d", Type: &DataType{Name: TypeArray, Size: 3, Elem: &DataType{Name: "BIGINT"}}},
+				}}},
+				{Name: `e"f`, Type: &DataType{Name: TypeMap, Key: &DataType{Name: "VARCHAR"},
+					Value: &DataType{Name: TypeList, Elem: &DataType{Name: "DOUBLE"}}}},
+			}}},
+			expectedString: `STRUCT(a VARCHAR, "b c" STRUCT(d BIGINT[3]), "e""f" MAP(VARCHAR, DOUBLE[]))[]`,
+		},
+		{
+			name:           "TC5",
+			input:          "ENUM('a', 'b)c')",
+			expectedOutput: &DataType{Name: "ENUM", Params: "'a', 'b)c'"},
+			expectedString: "ENUM('a', 'b)c')",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := ParseType(tc.input)
+			if err != nil {
+				t.Fatalf("failed parsing type. error: %v", err)
+			}
+
+			if !reflect.DeepEqual(tc.expectedOutput, actual) {
+				t.Fatalf("expected: %+v but got: %+v", tc.expectedOutput, actual)
+			}
+			if actual.String() != tc.expectedString {
+				t.Fatalf("expected: %s but got: %s", tc.expectedString, actual.String())
+			}
+		})
+	}
+}
+
+func TestParseTypeErr(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		expectedErr string
+	}{
+		{name: "TC1", input: "STRUCT(a VARCHAR", expectedErr: "invalid type: STRUCT(a VARCHAR. error: expected , or ) at 16"},
+		{name: "TC2", input: "BIGINT[x]", expectedErr: "invalid type: BIGINT[x]. error: invalid array size: x"},
+		{name: "TC3", input: "MAP(VARCHAR)", expectedErr: "invalid type: MAP(VARCHAR). error: missing , after map key type at 11"},
+		{name: "TC4", input: "", expectedErr: "invalid type: . error: missing type name at 0"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ParseType(tc.input)
+			if err == nil || err.Error() != tc.expectedErr {
+				t.Fatalf("expected error: %s but got: %v", tc.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestValidateType(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		expectedErr string
+	}{
+		{name: "TC1", input: "STRUCT(a INT, b TEXT[], c MAP(VARCHAR, DECIMAL(38,10)))", expectedErr: ""},
+		{name: "TC2", input: "timestamptz", expectedErr: ""},
+		{name: "TC3", input: "VARCHAR(10)", expectedErr: ""},
+		{name: "TC4", input: "ENUM('a', 'b')", expectedErr: ""},
+		{name: "TC5", input: "INTEGR", expectedErr: "invalid type: INTEGR. error: unknown type: INTEGR"},
+		{name: "TC6", input: "STRUCT(a BIGINT, b STRNG)", expectedErr: "invalid type: STRUCT(a BIGINT, b STRNG). error: unknown type: STRNG"},
+		{name: "TC7", input: "DECIMAL(40,2)", expectedErr: "invalid type: DECIMAL(40,2). error: decimal precision must be between 1 and 38: 40"},
+		{name: "TC8", input: "DECIMAL(5,6)", expectedErr: "invalid type: DECIMAL(5,6). error: decimal scale must be between 0 and the precision: 6"},
+		{name: "TC9", input: "BIGINT(10)", expectedErr: "invalid type: BIGINT(10). error: type BIGINT does not take parameters: 10"},
+		{name: "TC10", input: "STRUCT(a BIGINT, A VARCHAR)", expectedErr: "invalid type: STRUCT(a BIGINT, A VARCHAR). error: duplicate field name: A"},
+		{name: "TC11", input: "DOUBLE[0]", expectedErr: "invalid type: DOUBLE[0]. error: array size must be positive: 0"},
+		{name: "TC12", input: "ENUM('it''s', 'b')", expectedErr: ""},
+		{name: "TC13", input: "ENUM('a', (SELECT 'b'))", expectedErr: "invalid type: ENUM('a', (SELECT 'b')). error: enum values must be quoted strings: 'a', (SELECT 'b')"},
+		{name: "TC14", input: "VARCHAR((SELECT 1))", expectedErr: "invalid type: VARCHAR((SELECT 1)). error: type VARCHAR takes a length: (SELECT 1)"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateType(tc.input)
+			if tc.expectedErr == "" {
+				if err != nil {
+					t.Fatalf("expected no error but got: %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tc.expectedErr {
+				t.Fatalf("expected error: %s but got: %v", tc.expectedErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,224 @@
+package model
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+const jsonSchemaDraft string = "https://json-schema.org/draft/2020-12/schema"
+
+// JSON Schema of a value written by the json writer. Values of the JSON type have an empty schema.
+type JSONSchema struct {
+	Schema               string               `json:"$schema,omitempty"`
+	Title                string               `json:"title,omitempty"`
+	Type                 []string             `json:"type,omitempty"`
+	Format               string               `json:"format,omitempty"`
+	Enum                 []any                `json:"enum,omitempty"`
+	Minimum              *int                 `json:"minimum,omitempty"`
+	Properties           JSONSchemaProperties `json:"properties,omitempty"`
+	Required             []string             `json:"required,omitempty"`
+	AdditionalProperties *JSONSchema          `json:"additionalProperties,omitempty"`
+	Items                *JSONSchema          `json:"items,omitempty"`
+	MinItems             *int                 `json:"minItems,omitempty"`
+	MaxItems             *int                 `json:"maxItems,omitempty"`
+	AnyOf                []*JSONSchema        `json:"anyOf,omitempty"`
+}
+
+// Property of an object schema
+type JSONSchemaProperty struct {
+	Name   string
+	Schema *JSONSchema
+}
+
+// Properties of an object schema, marshalled in the order of the columns or fields
+type JSONSchemaProperties []*JSONSchemaProperty
+
+/*
+Returns the columns as the JSON Schema of the records written by the json writer, with the title of the schema.
+All the values are nullable and all the keys are required, as the writer writes NULL values as null.
+STRUCT columns are objects, LIST and ARRAY columns are arrays and MAP columns are objects of their values.
+Temporal and UUID values are strings with the date-time, date, time and uuid formats, and ENUM values are enums of strings.
+*/
+func (t *TableDesc) JSONSchema(title string) (*JSONSchema, error) {
+	schema := &JSONSchema{Schema: jsonSchemaDraft, Title: title, Type: []string{"object"}}
+	for _, colDesc := range t.ColumnDescs {
+		dataType, err := colDesc.ColType.Parse()
+		if err != nil {
+			return nil, err
+		}
+
+		property, err := jsonSchemaOf(dataType)
+		if err != nil {
+			return nil, fmt.Errorf("unsupported type of column %q. error: %w", colDesc.ColName, err)
+		}
+		schema.Properties = append(schema.Properties, &JSONSchemaProperty{Name: colDesc.ColName, Schema: property})
+		schema.Required = append(schema.Required, colDesc.ColName)
+	}
+
+	return schema, nil
+}
+
+func jsonSchemaOf(dataType *DataType) (*JSONSchema, error) {
+	switch dataType.Name {
+	case TypeStruct:
+		schema := nullableSchema("object")
+		for _, field := range dataType.Fields {
+			property, err := jsonSchemaOf(field.Type)
+			if err != nil {
+				return nil, err
+			}
+			schema.Properties = append(schema.Properties, &JSONSchemaProperty{Name: field.Name, Schema: property})
+			schema.Required = append(schema.Required, field.Name)
+		}
+		return schema, nil
+
+	case TypeList, TypeArray:
+		items, err := jsonSchemaOf(dataType.Elem)
+		if err != nil {
+			return nil, err
+		}
+		schema := nullableSchema("array")
+		schema.Items = items
+		if dataType.Name == TypeArray {
+			schema.MinItems, schema.MaxItems = &dataType.Size, &dataType.Size
+		}
+		return schema, nil
+
+	case TypeMap:
+		// the keys are written as strings
+		values, err := jsonSchemaOf(dataType.Value)
+		if err != nil {
+			return nil, err
+		}
+		schema := nullableSchema("object")
+		schema.AdditionalProperties = values
+		return schema, nil
+
+	case TypeUnion:
+		schema := &JSONSchema{}
+		for _, field := range dataType.Fields {
+			member, err := jsonSchemaOf(field.Type)
+			if err != nil {
+				return nil, err
+			}
+			schema.AnyOf = append(schema.AnyOf, member)
+		}
+		return schema, nil
+
+	case TypeDecimal:
+		return nullableSchema("number"), nil
+
+	case "ENUM":
+		schema := nullableSchema("string")
+		for _, value := range enumValues(dataType.Params) {
+			schema.Enum = append(schema.Enum, value)
+		}
+		// null is not an enum value otherwise
+		schema.Enum = append(schema.Enum, nil)
+		return schema, nil
+
+	case "JSON":
+		return &JSONSchema{}, nil
+	}
+
+	jsonType, ok := jsonSchemaTypes[dataType.Name]
+	if !ok {
+		return nil, fmt.Errorf("no json schema type for: %s", dataType)
+	}
+	schema := nullableSchema(jsonType.name)
+	schema.Format = jsonType.format
+	if jsonType.unsigned {
+		schema.Minimum = new(int)
+	}
+	return schema, nil
+}
+
+func nullableSchema(jsonType string) *JSONSchema {
+	return &JSONSchema{Type: []string{jsonType, "null"}}
+}
+
+// Returns the values of the params of an ENUM, e.g. 'a', 'b'
+func enumValues(params string) []string {
+	values := []string{}
+	for i := 0; i < len(params); i++ {
+		if params[i] != '\'' {
+			continue
+		}
+
+		var sb strings.Builder
+		for i++; i < len(params); i++ {
+			if params[i] == '\'' {
+				// quotes are escaped by doubling them
+				if i+1 < len(params) && params[i+1] == '\'' {
+					sb.WriteByte('\'')
+					i++
+					continue
+				}
+				break
+			}
+			sb.WriteByte(params[i])
+		}
+		values = append(values, sb.String())
+	}
+	return values
+}
+
+type jsonSchemaType struct {
+	name     string
+	format   string
+	unsigned bool
+}
+
+var jsonSchemaTypes = map[string]jsonSchemaType{
+	"BOOLEAN":                  {name: "boolean"},
+	"TINYINT":                  {name: "integer"},
+	"SMALLINT":                 {name: "integer"},
+	"INTEGER":                  {name: "integer"},
+	"BIGINT":                   {name: "integer"},
+	"HUGEINT":                  {name: "integer"},
+	"UTINYINT":                 {name: "integer", unsigned: true},
+	"USMALLINT":                {name: "integer", unsigned: true},
+	"UINTEGER":                 {name: "integer", unsigned: true},
+	"UBIGINT":                  {name: "integer", unsigned: true},
+	"UHUGEINT":                 {name: "integer", unsigned: true},
+	"FLOAT":                    {name: "number"},
+	"DOUBLE":                   {name: "number"},
+	"VARCHAR":                  {name: "string"},
+	"UUID":                     {name: "string", format: "uuid"},
+	"BLOB":                     {name: "string"},
+	"BIT":                      {name: "string"},
+	"DATE":                     {name: "string", format: "date"},
+	"TIME":                     {name: "string", format: "time"},
+	"TIME WITH TIME ZONE":      {name: "string", format: "time"},
+	"TIMESTAMP":                {name: "string", format: "date-time"},
+	"TIMESTAMP_S":              {name: "string", format: "date-time"},
+	"TIMESTAMP_MS":             {name: "string", format: "date-time"},
+	"TIMESTAMP_NS":             {name: "string", format: "date-time"},
+	"TIMESTAMP WITH TIME ZONE": {name: "string", format: "date-time"},
+	"INTERVAL":                 {name: "string"},
+}
+
+func (p JSONSchemaProperties) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, property := range p {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		name, err := json.Marshal(property.Name)
+		if err != nil {
+			return nil, err
+		}
+		schema, err := json.Marshal(property.Schema)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(schema)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
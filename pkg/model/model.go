@@ -3,18 +3,60 @@ package model
 import (
 	"fmt"
 	"strings"
+
+	"github.com/hbbtekademy/go-fileconv/pkg/param"
 )
 
 type ColumnType string
 
+// Returns the name of the parsed column type, or an empty string if the type can't be parsed
+func (ct ColumnType) typeName() string {
+	dataType, err := ct.Parse()
+	if err != nil {
+		return ""
+	}
+	return dataType.Name
+}
+
 func (ct ColumnType) IsStruct() bool {
-	str := strings.ToUpper(string(ct))
-	return strings.HasPrefix(str, "STRUCT(") && strings.HasSuffix(str, ")")
+	return ct.typeName() == TypeStruct
+}
+
+// Returns true for variable size lists and fixed size arrays
+func (ct ColumnType) IsList() bool {
+	name := ct.typeName()
+	return name == TypeList || name == TypeArray
+}
+
+func (ct ColumnType) IsMap() bool {
+	return ct.typeName() == TypeMap
+}
+
+func (ct ColumnType) IsNested() bool {
+	return ct.IsStruct() || ct.IsList() || ct.IsMap()
+}
+
+func (ct ColumnType) IsNumeric() bool {
+	switch ct.typeName() {
+	case "TINYINT", "INT1", "SMALLINT", "INT2", "SHORT", "INTEGER", "INT4", "INT", "SIGNED", "BIGINT", "INT8", "LONG", "HUGEINT",
+		"UTINYINT", "USMALLINT", "UINTEGER", "UBIGINT", "UHUGEINT",
+		"FLOAT", "FLOAT4", "REAL", "DOUBLE", "FLOAT8", TypeDecimal:
+		return true
+	}
+	return false
+}
+
+func (ct ColumnType) IsString() bool {
+	switch ct.typeName() {
+	case "VARCHAR", "CHAR", "BPCHAR", "TEXT", "STRING":
+		return true
+	}
+	return false
 }
 
 type ColumnDesc struct {
-	ColName string     `json:"column_name"`
-	ColType ColumnType `json:"column_type"`
+	ColName string     `json:"column_name" yaml:"column_name"`
+	ColType ColumnType `json:"column_type" yaml:"column_type"`
 }
 
 type TableDesc struct {
@@ -27,9 +69,9 @@ func (t *TableDesc) GetUnnestedColumns() (string, error) {
 	for i := range t.ColumnDescs {
 		var err error
 		if t.ColumnDescs[i].ColType.IsStruct() {
-			_, err = sb.WriteString(fmt.Sprintf("unnest(%s, recursive := true)", t.ColumnDescs[i].ColName))
+			_, err = sb.WriteString(fmt.Sprintf("unnest(%s, recursive := true)", param.QuoteIdent(t.ColumnDescs[i].ColName)))
 		} else {
-			_, err = sb.WriteString(t.ColumnDescs[i].ColName)
+			_, err = sb.WriteString(param.QuoteIdent(t.ColumnDescs[i].ColName))
 		}
 		if err != nil {
 			return "", err
@@ -19,7 +19,7 @@ func TestGetUnnestedColumns(t *testing.T) {
 					{ColName: "col3", ColType: "double"},
 				},
 			},
-			expectedOutput: "col1,col2,col3",
+			expectedOutput: `"col1","col2","col3"`,
 		},
 		{
 			name: "TC2",
@@ -31,7 +31,17 @@ func TestGetUnnestedColumns(t *testing.T) {
 					{ColName: "a4", ColType: "STRUCT(b1 VARCHAR)"},
 				},
 			},
-			expectedOutput: "a1,unnest(a2, recursive := true),a3,unnest(a4, recursive := true)",
+			expectedOutput: `"a1",unnest("a2", recursive := true),"a3",unnest("a4", recursive := true)`,
+		},
+		{
+			name: "TC3",
+			input: &TableDesc{
+				ColumnDescs: []*ColumnDesc{
+					{ColName: "select", ColType: "varchar"},
+					{ColName: `a"b`, ColType: "STRUCT(c VARCHAR)"},
+				},
+			},
+			expectedOutput: `"select",unnest("a""b", recursive := true)`,
 		},
 	}
 
@@ -48,3 +58,125 @@ func TestGetUnnestedColumns(t *testing.T) {
 		})
 	}
 }
+
+func TestIsNested(t *testing.T) {
+	tests := []struct {
+		name           string
+		input          ColumnType
+		expectedOutput bool
+	}{
+		{name: "TC1", input: "VARCHAR", expectedOutput: false},
+		{name: "TC2", input: "STRUCT(b1 VARCHAR)", expectedOutput: true},
+		{name: "TC3", input: "BIGINT[]", expectedOutput: true},
+		{name: "TC4", input: "STRUCT(b1 VARCHAR)[]", expectedOutput: true},
+		{name: "TC5", input: "INTEGER[3]", expectedOutput: true},
+		{name: "TC6", input: "MAP(VARCHAR, BIGINT)", expectedOutput: true},
+		{name: "TC7", input: "DECIMAL(18,3)", expectedOutput: false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			actual := tc.input.IsNested()
+			if actual != tc.expectedOutput {
+				t.Fatalf("expected: %v but got: %v", tc.expectedOutput, actual)
+			}
+		})
+	}
+}
+
+func TestIsNumeric(t *testing.T) {
+	tests := []struct {
+		name           string
+		input          ColumnType
+		expectedOutput bool
+	}{
+		{name: "TC1", input: "VARCHAR", expectedOutput: false},
+		{name: "TC2", input: "BIGINT", expectedOutput: true},
+		{name: "TC3", input: "double", expectedOutput: true},
+		{name: "TC4", input: "DECIMAL(18,3)", expectedOutput: true},
+		{name: "TC5", input: "BIGINT[]", expectedOutput: false},
+		{name: "TC6", input: "TIMESTAMP", expectedOutput: false},
+		{name: "TC7", input: "INT", expectedOutput: true},
+		{name: "TC8", input: "REAL", expectedOutput: true},
+		{name: "TC9", input: "DECIMAL", expectedOutput: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			actual := tc.input.IsNumeric()
+			if actual != tc.expectedOutput {
+				t.Fatalf("expected: %v but got: %v", tc.expectedOutput, actual)
+			}
+		})
+	}
+}
+
+func TestIsStruct(t *testing.T) {
+	tests := []struct {
+		name           string
+		input          ColumnType
+		expectedOutput bool
+	}{
+		{name: "TC1", input: "STRUCT(a INTEGER)", expectedOutput: true},
+		{name: "TC2", input: "STRUCT(a INTEGER[])", expectedOutput: true},
+		{name: "TC3", input: "STRUCT(a INTEGER)[]", expectedOutput: false},
+		{name: "TC4", input: "struct(\"a b\" VARCHAR)", expectedOutput: true},
+		{name: "TC5", input: "VARCHAR", expectedOutput: false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			actual := tc.input.IsStruct()
+			if actual != tc.expectedOutput {
+				t.Fatalf("expected: %v but got: %v", tc.expectedOutput, actual)
+			}
+		})
+	}
+}
+
+func TestIsList(t *testing.T) {
+	tests := []struct {
+		name           string
+		input          ColumnType
+		expectedOutput bool
+	}{
+		{name: "TC1", input: "BIGINT[]", expectedOutput: true},
+		{name: "TC2", input: "INTEGER[3]", expectedOutput: true},
+		{name: "TC3", input: "STRUCT(a INTEGER[])", expectedOutput: false},
+		{name: "TC4", input: "MAP(VARCHAR, INTEGER[])", expectedOutput: false},
+		{name: "TC5", input: "VARCHAR", expectedOutput: false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			actual := tc.input.IsList()
+			if actual != tc.expectedOutput {
+				t.Fatalf("expected: %v but got: %v", tc.expectedOutput, actual)
+			}
+		})
+	}
+}
+
+func TestIsString(t *testing.T) {
+	tests := []struct {
+		name           string
+		input          ColumnType
+		expectedOutput bool
+	}{
+		{name: "TC1", input: "VARCHAR", expectedOutput: true},
+		{name: "TC2", input: "VARCHAR(10)", expectedOutput: true},
+		{name: "TC3", input: "text", expectedOutput: true},
+		{name: "TC4", input: "VARCHAR[]", expectedOutput: false},
+		{name: "TC5", input: "ENUM('a', 'b')", expectedOutput: false},
+		{name: "TC6", input: "BIGINT", expectedOutput: false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			actual := tc.input.IsString()
+			if actual != tc.expectedOutput {
+				t.Fatalf("expected: %v but got: %v", tc.expectedOutput, actual)
+			}
+		})
+	}
+}
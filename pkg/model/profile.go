@@ -0,0 +1,162 @@
+package model
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Column names, types and statistics of a table
+type TableProfile struct {
+	Rows    int64            `json:"rows"`
+	Columns []*ColumnProfile `json:"columns"`
+}
+
+// Statistics of a column. Statistics which do not apply to the column type are not set.
+type ColumnProfile struct {
+	ColumnDesc
+	NullCount int64 `json:"null_count"`
+	// Approximate number of distinct non NULL values
+	DistinctCount int64 `json:"distinct_count"`
+	// Min and max values as strings. Not set for nested columns.
+	Min *string `json:"min"`
+	Max *string `json:"max"`
+	// Mean and sample standard deviation of numeric columns
+	Mean   *float64 `json:"mean"`
+	StdDev *float64 `json:"stddev"`
+	// Most frequent non NULL values, most frequent first
+	TopValues []*ValueCount `json:"top_values"`
+	// Distribution of the lengths of VARCHAR columns
+	Length *LengthStats `json:"length"`
+}
+
+type ValueCount struct {
+	Value string `json:"value"`
+	Count int64  `json:"count"`
+}
+
+// Min, quartiles and max of the lengths of the values. The quartiles are approximate.
+type LengthStats struct {
+	Min    int64   `json:"min"`
+	P25    int64   `json:"p25"`
+	Median int64   `json:"median"`
+	P75    int64   `json:"p75"`
+	Max    int64   `json:"max"`
+	Mean   float64 `json:"mean"`
+}
+
+const maxProfileValueLen = 30
+
+var profileHeaders = []string{"COLUMN NAME", "COLUMN TYPE", "NULLS", "DISTINCT", "MIN", "MAX", "MEAN", "STDDEV", "LENGTH MIN/P25/P50/P75/MAX", "TOP VALUES"}
+
+// Returns the profile as a text table
+func (p *TableProfile) String() string {
+	rows := p.rows()
+	widths := make([]int, len(profileHeaders))
+	for _, row := range append([][]string{profileHeaders}, rows...) {
+		for i, cell := range row {
+			widths[i] = max(widths[i], len(cell))
+		}
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("ROWS: %d\n\n", p.Rows))
+	writeRow := func(row []string) {
+		cells := make([]string, len(row))
+		for i, cell := range row {
+			cells[i] = fmt.Sprintf("%-*s", widths[i]+2, cell)
+		}
+		sb.WriteString(strings.TrimRight(strings.Join(cells, "| "), " "))
+		sb.WriteString("\n")
+	}
+
+	writeRow(profileHeaders)
+	separators := make([]string, len(widths))
+	for i, width := range widths {
+		// the cells after the first are preceded by a space
+		separators[i] = strings.Repeat("=", width+2+min(i, 1))
+	}
+	sb.WriteString(strings.Join(separators, "|"))
+	sb.WriteString("\n")
+	for _, row := range rows {
+		writeRow(row)
+	}
+
+	return sb.String()
+}
+
+// Returns the profile as a markdown table
+func (p *TableProfile) Markdown() string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Rows: %d\n\n", p.Rows))
+	writeRow := func(row []string) {
+		sb.WriteString("|")
+		for _, cell := range row {
+			sb.WriteString(" ")
+			sb.WriteString(strings.ReplaceAll(cell, "|", `\|`))
+			sb.WriteString(" |")
+		}
+		sb.WriteString("\n")
+	}
+
+	writeRow(profileHeaders)
+	sb.WriteString(strings.Repeat("| --- ", len(profileHeaders)))
+	sb.WriteString("|\n")
+	for _, row := range p.rows() {
+		writeRow(row)
+	}
+
+	return sb.String()
+}
+
+func (p *TableProfile) rows() [][]string {
+	rows := make([][]string, 0, len(p.Columns))
+	for _, col := range p.Columns {
+		topValues := make([]string, 0, len(col.TopValues))
+		for _, topValue := range col.TopValues {
+			topValues = append(topValues, fmt.Sprintf("%s (%d)", truncate(topValue.Value), topValue.Count))
+		}
+
+		length := ""
+		if col.Length != nil {
+			length = fmt.Sprintf("%d/%d/%d/%d/%d", col.Length.Min, col.Length.P25, col.Length.Median, col.Length.P75, col.Length.Max)
+		}
+
+		rows = append(rows, []string{
+			col.ColName,
+			string(col.ColType),
+			strconv.FormatInt(col.NullCount, 10),
+			strconv.FormatInt(col.DistinctCount, 10),
+			formatString(col.Min),
+			formatString(col.Max),
+			formatFloat(col.Mean),
+			formatFloat(col.StdDev),
+			length,
+			strings.Join(topValues, ", "),
+		})
+	}
+	return rows
+}
+
+func formatString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return truncate(*s)
+}
+
+func formatFloat(f *float64) string {
+	if f == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*f, 'g', 6, 64)
+}
+
+// Truncates long values to keep the table readable
+func truncate(s string) string {
+	s = strings.ReplaceAll(s, "\n", " ")
+	if r := []rune(s); len(r) > maxProfileValueLen {
+		return string(r[:maxProfileValueLen-3]) + "..."
+	}
+	return s
+}
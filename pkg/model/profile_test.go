@@ -0,0 +1,63 @@
+package model
+
+import (
+	"testing"
+)
+
+func TestTableProfile(t *testing.T) {
+	minValue, maxValue, mean, stddev := "4.3", "7.9", 5.843333333, 0.828066128
+	profile := &TableProfile{
+		Rows: 150,
+		Columns: []*ColumnProfile{
+			{
+				ColumnDesc: ColumnDesc{ColName: "sepal_length", ColType: "DOUBLE"},
+				Min:        &minValue, Max: &maxValue, Mean: &mean, StdDev: &stddev, DistinctCount: 35,
+				TopValues: []*ValueCount{{Value: "5", Count: 10}, {Value: "5.1", Count: 9}},
+			},
+			{
+				ColumnDesc: ColumnDesc{ColName: "notes", ColType: "VARCHAR"},
+				NullCount:  148, DistinctCount: 2,
+				TopValues: []*ValueCount{{Value: "a|b", Count: 1}, {Value: "a very long note which is truncated", Count: 1}},
+				Length:    &LengthStats{Min: 3, P25: 3, Median: 3, P75: 35, Max: 35, Mean: 19},
+			},
+		},
+	}
+
+	tests := []struct {
+		name           string
+		render         func() string
+		expectedOutput string
+	}{
+		{
+			name:   "TC1",
+			render: profile.String,
+			expectedOutput: `ROWS: 150
+
+COLUMN NAME   | COLUMN TYPE  | NULLS  | DISTINCT  | MIN  | MAX  | MEAN     | STDDEV    | LENGTH MIN/P25/P50/P75/MAX  | TOP VALUES
+==============|==============|========|===========|======|======|==========|===========|=============================|==============================================
+sepal_length  | DOUBLE       | 0      | 35        | 4.3  | 7.9  | 5.84333  | 0.828066  |                             | 5 (10), 5.1 (9)
+notes         | VARCHAR      | 148    | 2         |      |      |          |           | 3/3/3/35/35                 | a|b (1), a very long note which is t... (1)
+`,
+		},
+		{
+			name:   "TC2",
+			render: profile.Markdown,
+			expectedOutput: `Rows: 150
+
+| COLUMN NAME | COLUMN TYPE | NULLS | DISTINCT | MIN | MAX | MEAN | STDDEV | LENGTH MIN/P25/P50/P75/MAX | TOP VALUES |
+| --- | --- | --- | --- | --- | --- | --- | --- | --- | --- |
+| sepal_length | DOUBLE | 0 | 35 | 4.3 | 7.9 | 5.84333 | 0.828066 |  | 5 (10), 5.1 (9) |
+| notes | VARCHAR | 148 | 2 |  |  |  |  | 3/3/3/35/35 | a\|b (1), a very long note which is t... (1) |
+`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			actual := tc.render()
+			if actual != tc.expectedOutput {
+				t.Fatalf("expected:\n%s\nbut got:\n%s", tc.expectedOutput, actual)
+			}
+		})
+	}
+}
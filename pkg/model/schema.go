@@ -0,0 +1,238 @@
+package model
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hbbtekademy/go-fileconv/pkg/param"
+	"gopkg.in/yaml.v3"
+)
+
+// Columns of a table in the json and yaml describe formats
+type Schema struct {
+	Columns []*ColumnDesc `json:"columns" yaml:"columns"`
+}
+
+// Loads the schema from a json or yaml file in the json and yaml describe formats.
+// The file is parsed as json if it has a .json extension, otherwise as yaml. Unknown keys are rejected.
+func LoadSchema(path string) (*Schema, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	schema := &Schema{}
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		err = decoder.Decode(schema)
+	} else {
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		err = decoder.Decode(schema)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed parsing schema file: %s. error: %w", path, err)
+	}
+
+	if err := schema.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid schema file: %s. error: %w", path, err)
+	}
+
+	return schema, nil
+}
+
+// Validates that the schema has columns with unique names and valid DuckDB types
+func (s *Schema) Validate() error {
+	if len(s.Columns) == 0 {
+		return fmt.Errorf("no columns")
+	}
+
+	names := map[string]bool{}
+	for i, col := range s.Columns {
+		if col == nil || col.ColName == "" {
+			return fmt.Errorf("column %d has no name", i+1)
+		}
+		if names[strings.ToLower(col.ColName)] {
+			return fmt.Errorf("duplicate column name: %s", col.ColName)
+		}
+		names[strings.ToLower(col.ColName)] = true
+
+		if col.ColType == "" {
+			return fmt.Errorf("column %q has no type", col.ColName)
+		}
+		if err := ValidateType(string(col.ColType)); err != nil {
+			return fmt.Errorf("invalid type of column %q. error: %w", col.ColName, err)
+		}
+	}
+
+	return nil
+}
+
+// Returns the columns of the schema as the columns or types read params of csv and json files
+func (s *Schema) ParamColumns() param.Columns {
+	columns := make(param.Columns, 0, len(s.Columns))
+	for _, col := range s.Columns {
+		columns = append(columns, param.Column{Name: col.ColName, Type: string(col.ColType)})
+	}
+	return columns
+}
+
+// Column of a BigQuery table schema
+type BigQueryField struct {
+	Name   string           `json:"name"`
+	Type   string           `json:"type"`
+	Mode   string           `json:"mode"`
+	Fields []*BigQueryField `json:"fields,omitempty"`
+}
+
+// Returns the columns as a json Schema
+func (t *TableDesc) JSON() (string, error) {
+	b, err := json.MarshalIndent(&Schema{Columns: t.ColumnDescs}, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(b) + "\n", nil
+}
+
+// Returns the columns as a yaml Schema
+func (t *TableDesc) YAML() (string, error) {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	err := enc.Encode(&Schema{Columns: t.ColumnDescs})
+	if err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// Returns the CREATE TABLE statement of a table with the columns
+func (t *TableDesc) DDL(table string) string {
+	cols := make([]string, 0, len(t.ColumnDescs))
+	for _, colDesc := range t.ColumnDescs {
+		cols = append(cols, fmt.Sprintf("  %s %s", param.QuoteIdent(colDesc.ColName), colDesc.ColType))
+	}
+
+	return fmt.Sprintf("CREATE TABLE %s (\n%s\n);\n", param.QuoteIdent(table), strings.Join(cols, ",\n"))
+}
+
+// Returns the value of the --columns flag of the CLI reading the columns, e.g. a:BIGINT,b:VARCHAR.
+// Columns with a comma in their name or type are quoted.
+func (t *TableDesc) ColumnsFlag() string {
+	cols := make([]string, 0, len(t.ColumnDescs))
+	for _, colDesc := range t.ColumnDescs {
+		cols = append(cols, fmt.Sprintf("%s:%s", colDesc.ColName, colDesc.ColType))
+	}
+
+	// the flag is parsed as a csv record
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Write(cols)
+	w.Flush()
+	return buf.String()
+}
+
+// Returns the columns as a BigQuery table schema.
+// STRUCT columns are RECORD columns, LIST columns are REPEATED and MAP columns are REPEATED RECORD columns of their keys and values.
+func (t *TableDesc) BigQuerySchema() ([]*BigQueryField, error) {
+	fields := make([]*BigQueryField, 0, len(t.ColumnDescs))
+	for _, colDesc := range t.ColumnDescs {
+		dataType, err := colDesc.ColType.Parse()
+		if err != nil {
+			return nil, err
+		}
+
+		field, err := bigQueryField(colDesc.ColName, dataType)
+		if err != nil {
+			return nil, fmt.Errorf("unsupported type of column %q. error: %w", colDesc.ColName, err)
+		}
+		fields = append(fields, field)
+	}
+
+	return fields, nil
+}
+
+func bigQueryField(name string, dataType *DataType) (*BigQueryField, error) {
+	switch dataType.Name {
+	case TypeList, TypeArray:
+		if dataType.Elem.Name == TypeList || dataType.Elem.Name == TypeArray {
+			return nil, fmt.Errorf("bigquery does not support nested lists: %s", dataType)
+		}
+		field, err := bigQueryField(name, dataType.Elem)
+		if err != nil {
+			return nil, err
+		}
+		field.Mode = "REPEATED"
+		return field, nil
+
+	case TypeStruct:
+		fields := make([]*BigQueryField, 0, len(dataType.Fields))
+		for _, f := range dataType.Fields {
+			field, err := bigQueryField(f.Name, f.Type)
+			if err != nil {
+				return nil, err
+			}
+			fields = append(fields, field)
+		}
+		return &BigQueryField{Name: name, Type: "RECORD", Mode: "NULLABLE", Fields: fields}, nil
+
+	case TypeMap:
+		key, err := bigQueryField("key", dataType.Key)
+		if err != nil {
+			return nil, err
+		}
+		value, err := bigQueryField("value", dataType.Value)
+		if err != nil {
+			return nil, err
+		}
+		return &BigQueryField{Name: name, Type: "RECORD", Mode: "REPEATED", Fields: []*BigQueryField{key, value}}, nil
+
+	case TypeDecimal:
+		if dataType.Precision-dataType.Scale <= 29 && dataType.Scale <= 9 {
+			return &BigQueryField{Name: name, Type: "NUMERIC", Mode: "NULLABLE"}, nil
+		}
+		return &BigQueryField{Name: name, Type: "BIGNUMERIC", Mode: "NULLABLE"}, nil
+	}
+
+	bqType, ok := bigQueryTypes[dataType.Name]
+	if !ok {
+		return nil, fmt.Errorf("no bigquery type for: %s", dataType)
+	}
+	return &BigQueryField{Name: name, Type: bqType, Mode: "NULLABLE"}, nil
+}
+
+var bigQueryTypes = map[string]string{
+	"BOOLEAN":                  "BOOL",
+	"TINYINT":                  "INT64",
+	"SMALLINT":                 "INT64",
+	"INTEGER":                  "INT64",
+	"BIGINT":                   "INT64",
+	"UTINYINT":                 "INT64",
+	"USMALLINT":                "INT64",
+	"UINTEGER":                 "INT64",
+	"UBIGINT":                  "BIGNUMERIC",
+	"HUGEINT":                  "BIGNUMERIC",
+	"UHUGEINT":                 "BIGNUMERIC",
+	"FLOAT":                    "FLOAT64",
+	"DOUBLE":                   "FLOAT64",
+	"VARCHAR":                  "STRING",
+	"UUID":                     "STRING",
+	"ENUM":                     "STRING",
+	"BLOB":                     "BYTES",
+	"BIT":                      "BYTES",
+	"DATE":                     "DATE",
+	"TIME":                     "TIME",
+	"TIMESTAMP":                "DATETIME",
+	"TIMESTAMP_S":              "DATETIME",
+	"TIMESTAMP_MS":             "DATETIME",
+	"TIMESTAMP_NS":             "DATETIME",
+	"TIMESTAMP WITH TIME ZONE": "TIMESTAMP",
+	"INTERVAL":                 "INTERVAL",
+	"JSON":                     "JSON",
+}
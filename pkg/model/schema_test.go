@@ -0,0 +1,302 @@
+package model
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/hbbtekademy/go-fileconv/pkg/param"
+)
+
+var schemaTestDesc = &TableDesc{
+	ColumnDescs: []*ColumnDesc{
+		{ColName: "id", ColType: "BIGINT"},
+		{ColName: "price", ColType: "DECIMAL(10,2)"},
+		{ColName: "address", ColType: "STRUCT(city VARCHAR, zip VARCHAR)"},
+		{ColName: "tags", ColType: "VARCHAR[]"},
+		{ColName: "created at", ColType: "TIMESTAMP WITH TIME ZONE"},
+	},
+}
+
+func TestTableDescFormats(t *testing.T) {
+	tests := []struct {
+		name           string
+		format         func() (string, error)
+		expectedOutput string
+	}{
+		{
+			name:   "TC1",
+			format: schemaTestDesc.JSON,
+			expectedOutput: `{
+  "columns": [
+    {
+      "column_name": "id",
+      "column_type": "BIGINT"
+    },
+    {
+      "column_name": "price",
+      "column_type": "DECIMAL(10,2)"
+    },
+    {
+      "column_name": "address",
+      "column_type": "STRUCT(city VARCHAR, zip VARCHAR)"
+    },
+    {
+      "column_name": "tags",
+      "column_type": "VARCHAR[]"
+    },
+    {
+      "column_name": "created at",
+      "column_type": "TIMESTAMP WITH TIME ZONE"
+    }
+  ]
+}
+`,
+		},
+		{
+			name:   "TC2",
+			format: schemaTestDesc.YAML,
+			expectedOutput: `columns:
+  - column_name: id
+    column_type: BIGINT
+  - column_name: price
+    column_type: DECIMAL(10,2)
+  - column_name: address
+    column_type: STRUCT(city VARCHAR, zip VARCHAR)
+  - column_name: tags
+    column_type: VARCHAR[]
+  - column_name: created at
+    column_type: TIMESTAMP WITH TIME ZONE
+`,
+		},
+		{
+			name:   "TC3",
+			format: func() (string, error) { return schemaTestDesc.DDL("orders"), nil },
+			expectedOutput: `CREATE TABLE "orders" (
+  "id" BIGINT,
+  "price" DECIMAL(10,2),
+  "address" STRUCT(city VARCHAR, zip VARCHAR),
+  "tags" VARCHAR[],
+  "created at" TIMESTAMP WITH TIME ZONE
+);
+`,
+		},
+		{
+			name:           "TC4",
+			format:         func() (string, error) { return schemaTestDesc.ColumnsFlag(), nil },
+			expectedOutput: `id:BIGINT,"price:DECIMAL(10,2)","address:STRUCT(city VARCHAR, zip VARCHAR)",tags:VARCHAR[],created at:TIMESTAMP WITH TIME ZONE` + "\n",
+		},
+		{
+			name: "TC5",
+			format: func() (string, error) {
+				fields, err := schemaTestDesc.BigQuerySchema()
+				if err != nil {
+					return "", err
+				}
+				b, err := json.Marshal(fields)
+				return string(b), err
+			},
+			expectedOutput: `[{"name":"id","type":"INT64","mode":"NULLABLE"},{"name":"price","type":"NUMERIC","mode":"NULLABLE"},` +
+				`{"name":"address","type":"RECORD","mode":"NULLABLE","fields":[{"name":"city","type":"STRING","mode":"NULLABLE"},{"name":"zip","type":"STRING","mode":"NULLABLE"}]},` +
+				`{"name":"tags","type":"STRING","mode":"REPEATED"},{"name":"created at","type":"TIMESTAMP","mode":"NULLABLE"}]`,
+		},
+		{
+			name: "TC6",
+			format: func() (string, error) {
+				schema, err := schemaTestDesc.JSONSchema("orders")
+				if err != nil {
+					return "", err
+				}
+				b, err := json.Marshal(schema)
+				return string(b), err
+			},
+			expectedOutput: `{"$schema":"https://json-schema.org/draft/2020-12/schema","title":"orders","type":["object"],"properties":{` +
+				`"id":{"type":["integer","null"]},"price":{"type":["number","null"]},` +
+				`"address":{"type":["object","null"],"properties":{"city":{"type":["string","null"]},"zip":{"type":["string","null"]}},"required":["city","zip"]},` +
+				`"tags":{"type":["array","null"],"items":{"type":["string","null"]}},"created at":{"type":["string","null"],"format":"date-time"}},` +
+				`"required":["id","price","address","tags","created at"]}`,
+		},
+		{
+			name: "TC7",
+			format: func() (string, error) {
+				tableDesc := &TableDesc{
+					ColumnDescs: []*ColumnDesc{
+						{ColName: "id", ColType: "UINTEGER"},
+						{ColName: "status", ColType: "ENUM('new', 'shipped')"},
+						{ColName: "items", ColType: "STRUCT(sku VARCHAR, qty INTEGER)[]"},
+						{ColName: "labels", ColType: "MAP(VARCHAR, VARCHAR)"},
+						{ColName: "created_at", ColType: "TIMESTAMP WITH TIME ZONE"},
+					},
+				}
+				schema, err := tableDesc.AvroSchema("orders-2024")
+				if err != nil {
+					return "", err
+				}
+				b, err := json.Marshal(schema)
+				return string(b), err
+			},
+			expectedOutput: `{"type":"record","name":"orders_2024","fields":[` +
+				`{"name":"id","type":["null","long"],"default":null},` +
+				`{"name":"status","type":["null",{"name":"orders_2024_status","symbols":["new","shipped"],"type":"enum"}],"default":null},` +
+				`{"name":"items","type":["null",{"items":["null",{"type":"record","name":"orders_2024_items","fields":[` +
+				`{"name":"sku","type":["null","string"],"default":null},{"name":"qty","type":["null","int"],"default":null}]}],"type":"array"}],"default":null},` +
+				`{"name":"labels","type":["null",{"type":"map","values":["null","string"]}],"default":null},` +
+				`{"name":"created_at","type":["null",{"logicalType":"timestamp-micros","type":"long"}],"default":null}]}`,
+		},
+		{
+			name: "TC8",
+			format: func() (string, error) {
+				tableDesc := &TableDesc{
+					ColumnDescs: []*ColumnDesc{
+						{ColName: "Sepal Length", ColType: "DOUBLE"},
+						{ColName: "Sepal_Length", ColType: "DOUBLE"},
+						{ColName: "Sepal.Length", ColType: "DOUBLE"},
+						{ColName: "1st", ColType: "VARCHAR"},
+						{ColName: "address", ColType: `STRUCT("zip code" VARCHAR)`},
+					},
+				}
+				schema, err := tableDesc.AvroSchema("iris")
+				if err != nil {
+					return "", err
+				}
+				b, err := json.Marshal(schema)
+				return string(b), err
+			},
+			expectedOutput: `{"type":"record","name":"iris","fields":[` +
+				`{"name":"Sepal_Length_2","doc":"Sepal Length","type":["null","double"],"default":null},` +
+				`{"name":"Sepal_Length","type":["null","double"],"default":null},` +
+				`{"name":"Sepal_Length_3","doc":"Sepal.Length","type":["null","double"],"default":null},` +
+				`{"name":"_st","doc":"1st","type":["null","string"],"default":null},` +
+				`{"name":"address","type":["null",{"type":"record","name":"iris_address","fields":[` +
+				`{"name":"zip_code","doc":"zip code","type":["null","string"],"default":null}]}],"default":null}]}`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := tc.format()
+			if err != nil {
+				t.Fatalf("failed formatting table desc. error: %v", err)
+			}
+
+			if actual != tc.expectedOutput {
+				t.Fatalf("expected:\n%s\nbut got:\n%s", tc.expectedOutput, actual)
+			}
+		})
+	}
+}
+
+func TestBigQuerySchemaErr(t *testing.T) {
+	tableDesc := &TableDesc{
+		ColumnDescs: []*ColumnDesc{
+			{ColName: "matrix", ColType: "DOUBLE[][]"},
+		},
+	}
+
+	_, err := tableDesc.BigQuerySchema()
+	expectedErr := `unsupported type of column "matrix". error: bigquery does not support nested lists: DOUBLE[][]`
+	if err == nil || err.Error() != expectedErr {
+		t.Fatalf("expected error: %s but got: %v", expectedErr, err)
+	}
+}
+
+func TestAvroSchemaErr(t *testing.T) {
+	tests := []struct {
+		name        string
+		colDesc     *ColumnDesc
+		expectedErr string
+	}{
+		{
+			name:        "TC1",
+			colDesc:     &ColumnDesc{ColName: "counts", ColType: "MAP(INTEGER, BIGINT)"},
+			expectedErr: `unsupported type of column "counts". error: avro maps must have VARCHAR keys: MAP(INTEGER, BIGINT)`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tableDesc := &TableDesc{ColumnDescs: []*ColumnDesc{tc.colDesc}}
+			_, err := tableDesc.AvroSchema("source")
+			if err == nil || err.Error() != tc.expectedErr {
+				t.Fatalf("expected error: %s but got: %v", tc.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestLoadSchema(t *testing.T) {
+	expectedColumns := param.Columns{
+		{Name: "sepal_length", Type: "DOUBLE"},
+		{Name: "sepal_width", Type: "DOUBLE"},
+		{Name: "petal_length", Type: "DECIMAL(4,1)"},
+		{Name: "petal_width", Type: "FLOAT"},
+		{Name: "species", Type: "VARCHAR"},
+	}
+
+	tests := []struct {
+		name       string
+		schemaFile string
+	}{
+		{name: "TC1", schemaFile: "../../testdata/schema/iris_schema.yaml"},
+		{name: "TC2", schemaFile: "../../testdata/schema/iris_schema.json"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			schema, err := LoadSchema(tc.schemaFile)
+			if err != nil {
+				t.Fatalf("failed loading schema. error: %v", err)
+			}
+
+			if !reflect.DeepEqual(schema.ParamColumns(), expectedColumns) {
+				t.Fatalf("expected: %v but got: %v", expectedColumns, schema.ParamColumns())
+			}
+		})
+	}
+}
+
+func TestLoadSchemaErr(t *testing.T) {
+	tests := []struct {
+		name        string
+		schema      string
+		expectedErr string
+	}{
+		{
+			name:        "TC1",
+			schema:      "columns:\n  - column_name: a\n    column_type: BIGNT\n",
+			expectedErr: `invalid type of column "a". error: invalid type: BIGNT. error: unknown type: BIGNT`,
+		},
+		{
+			name:        "TC2",
+			schema:      "columns:\n  - column_name: a\n    column_type: BIGINT\n  - column_name: A\n    column_type: VARCHAR\n",
+			expectedErr: "duplicate column name: A",
+		},
+		{
+			name:        "TC3",
+			schema:      "columns:\n  - column_name: a\n",
+			expectedErr: `column "a" has no type`,
+		},
+		{
+			name:        "TC4",
+			schema:      "columns: []\n",
+			expectedErr: "no columns",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			schemaFile := filepath.Join(t.TempDir(), "schema.yaml")
+			if err := os.WriteFile(schemaFile, []byte(tc.schema), 0644); err != nil {
+				t.Fatalf("failed writing schema file. error: %v", err)
+			}
+
+			_, err := LoadSchema(schemaFile)
+			expectedErr := "invalid schema file: " + schemaFile + ". error: " + tc.expectedErr
+			if err == nil || err.Error() != expectedErr {
+				t.Fatalf("expected error: %s but got: %v", expectedErr, err)
+			}
+		})
+	}
+}
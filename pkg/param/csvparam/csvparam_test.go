@@ -62,6 +62,27 @@ func TestReadParams(t *testing.T) {
 			},
 			expectedOutput: ",auto_type_candidates = ['col1','col2'],columns = {'col1': 'BIGINT','col2': 'VARCHAR'},force_not_null = ['col1'],names = ['col1'],nullstr = ['nul'],types = {'col1': 'VARCHAR'}",
 		},
+		{
+			name: "TC4",
+			params: []ReadParam{
+				WithColumns(param.Columns{
+					{Name: "o'brien", Type: "VARCHAR"},
+				}),
+				WithDelim("'"),
+				WithNames([]string{"select", "a'); DROP TABLE t; --"}),
+				WithNullStrings([]string{"it's null"}),
+			},
+			expectedOutput: ",columns = {'o''brien': 'VARCHAR'},delim = '''',names = ['select','a''); DROP TABLE t; --'],nullstr = ['it''s null']",
+		},
+		{
+			name: "TC5",
+			params: []ReadParam{
+				WithHeader(true),
+				WithRejects("rejects.csv"),
+				WithMaxRejects(10),
+			},
+			expectedOutput: ",header = true,store_rejects = true",
+		},
 	}
 
 	for _, tc := range tests {
@@ -75,3 +96,43 @@ func TestReadParams(t *testing.T) {
 		})
 	}
 }
+
+func TestWriteParams(t *testing.T) {
+	tests := []struct {
+		name           string
+		params         []WriteParam
+		expectedOutput string
+	}{
+		{
+			name:           "TC1",
+			params:         []WriteParam{},
+			expectedOutput: "(FORMAT CSV)",
+		},
+		{
+			name: "TC2",
+			params: []WriteParam{
+				WithWriteDelim("|"),
+				WithWriteQuote("`"),
+				WithWriteEscape("`"),
+				WithWriteHeader(false),
+				WithWriteNullStr("NULL"),
+				WithWriteDateformat("%d/%m/%Y"),
+				WithWriteTimestampformat("%A, %-d %B %Y"),
+				WithForceQuote("col1", "col2"),
+				WithWriteCompression(param.Gzip),
+			},
+			expectedOutput: "(FORMAT CSV,DELIMITER '|',QUOTE '`',ESCAPE '`',HEADER false,NULLSTR 'NULL',DATEFORMAT '%d/%m/%Y',TIMESTAMPFORMAT '%A, %-d %B %Y',FORCE_QUOTE (\"col1\",\"col2\"),COMPRESSION 'gzip')",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			params := NewWriteParams(tc.params...)
+			actualOutput := params.Params()
+
+			if actualOutput != tc.expectedOutput {
+				t.Fatalf("expected:\n%s\nbut got:\n%s", tc.expectedOutput, actualOutput)
+			}
+		})
+	}
+}
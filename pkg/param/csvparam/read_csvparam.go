@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/hbbtekademy/go-fileconv/pkg/param"
+	"github.com/hbbtekademy/go-fileconv/pkg/param/transformparam"
 )
 
 // Parameters for reading a CSV file
@@ -38,6 +39,9 @@ type ReadParams struct {
 	types              param.Columns
 	unionByName        bool
 	describe           bool
+	transform          *transformparam.TransformParams
+	rejects            string
+	maxRejects         int64
 }
 
 type ReadParam func(*ReadParams)
@@ -66,6 +70,7 @@ const (
 	dfltTimestampformat  string            = ""
 	dfltUnionByName      bool              = false
 	dfltDescribe         bool              = false
+	dfltMaxRejects       int64             = -1
 )
 
 func WithAllVarchar(allVarchar bool) ReadParam {
@@ -242,6 +247,29 @@ func WithDescribe(describe bool) ReadParam {
 	}
 }
 
+// Transform the rows read from the file before they are written.
+// See transformparam for the available transformations.
+func WithTransform(options ...transformparam.TransformParam) ReadParam {
+	return func(rp *ReadParams) {
+		rp.transform = transformparam.NewTransformParams(options...)
+	}
+}
+
+// Write the rows which cannot be read to the rejects file with their file name, line number, column and error
+// instead of failing the conversion. The rejects file is written as json if it has a .json extension, otherwise as csv.
+func WithRejects(rejects string) ReadParam {
+	return func(rp *ReadParams) {
+		rp.rejects = rejects
+	}
+}
+
+// Fail the conversion if more than maxRejects rows are rejected. -1 for no limit.
+func WithMaxRejects(maxRejects int64) ReadParam {
+	return func(rp *ReadParams) {
+		rp.maxRejects = maxRejects
+	}
+}
+
 // https://duckdb.org/docs/data/csv/overview#parameters
 func NewReadParams(params ...ReadParam) *ReadParams {
 	csvReadParams := &ReadParams{
@@ -274,6 +302,8 @@ func NewReadParams(params ...ReadParam) *ReadParams {
 		types:              param.Columns{},
 		unionByName:        dfltUnionByName,
 		describe:           dfltDescribe,
+		transform:          transformparam.NewTransformParams(),
+		maxRejects:         dfltMaxRejects,
 	}
 
 	for _, param := range params {
@@ -297,31 +327,31 @@ func (p *ReadParams) Params() string {
 		params = append(params, "auto_detect = false")
 	}
 	if len(p.autoTypeCandidates) > 0 {
-		params = append(params, fmt.Sprintf("auto_type_candidates = ['%s']", strings.Join(p.autoTypeCandidates, "','")))
+		params = append(params, fmt.Sprintf("auto_type_candidates = %s", param.ListLiteral(p.autoTypeCandidates)))
 	}
 	if len(p.columns) > 0 {
 		params = append(params, fmt.Sprintf("columns = %s", p.columns.Format(true)))
 	}
 	if p.compression != dfltCompression {
-		params = append(params, fmt.Sprintf("compression = '%s'", p.compression))
+		params = append(params, fmt.Sprintf("compression = %s", param.QuoteLiteral(string(p.compression))))
 	}
 	if p.dateformat != dfltDateformat {
-		params = append(params, fmt.Sprintf("dateformat = '%s'", p.dateformat))
+		params = append(params, fmt.Sprintf("dateformat = %s", param.QuoteLiteral(p.dateformat)))
 	}
 	if p.decimalSeparator != dfltDecimalSeparator {
-		params = append(params, fmt.Sprintf("decimal_separator = '%s'", p.decimalSeparator))
+		params = append(params, fmt.Sprintf("decimal_separator = %s", param.QuoteLiteral(p.decimalSeparator)))
 	}
 	if p.delim != dfltDelim {
-		params = append(params, fmt.Sprintf("delim = '%s'", p.delim))
+		params = append(params, fmt.Sprintf("delim = %s", param.QuoteLiteral(p.delim)))
 	}
 	if p.escape != dfltEscape {
-		params = append(params, fmt.Sprintf("escape = '%s'", p.escape))
+		params = append(params, fmt.Sprintf("escape = %s", param.QuoteLiteral(p.escape)))
 	}
 	if p.filename {
 		params = append(params, "filename = true")
 	}
 	if len(p.forceNotNull) > 0 {
-		params = append(params, fmt.Sprintf("force_not_null = ['%s']", strings.Join(p.forceNotNull, "','")))
+		params = append(params, fmt.Sprintf("force_not_null = %s", param.ListLiteral(p.forceNotNull)))
 	}
 	if p.header {
 		params = append(params, "header = true")
@@ -336,10 +366,10 @@ func (p *ReadParams) Params() string {
 		params = append(params, fmt.Sprintf("max_line_size = %d", p.maxLineSize))
 	}
 	if len(p.names) > 0 {
-		params = append(params, fmt.Sprintf("names = ['%s']", strings.Join(p.names, "','")))
+		params = append(params, fmt.Sprintf("names = %s", param.ListLiteral(p.names)))
 	}
 	if p.newLine != dfltNewLine {
-		params = append(params, fmt.Sprintf("new_line = '%s'", p.newLine))
+		params = append(params, fmt.Sprintf("new_line = %s", param.QuoteLiteral(p.newLine)))
 	}
 	if p.normalizeNames {
 		params = append(params, "normalize_names = true")
@@ -348,13 +378,13 @@ func (p *ReadParams) Params() string {
 		params = append(params, "null_padding = true")
 	}
 	if len(p.nullStr) > 0 {
-		params = append(params, fmt.Sprintf("nullstr = ['%s']", strings.Join(p.nullStr, "','")))
+		params = append(params, fmt.Sprintf("nullstr = %s", param.ListLiteral(p.nullStr)))
 	}
 	if p.parallel {
 		params = append(params, "parallel = true")
 	}
 	if p.quote != dfltQuote {
-		params = append(params, fmt.Sprintf("quote = '%s'", p.quote))
+		params = append(params, fmt.Sprintf("quote = %s", param.QuoteLiteral(p.quote)))
 	}
 	if p.sampleSize != dfltSampleSize {
 		params = append(params, fmt.Sprintf("sample_size = %d", p.sampleSize))
@@ -363,7 +393,7 @@ func (p *ReadParams) Params() string {
 		params = append(params, fmt.Sprintf("skip = %d", p.skip))
 	}
 	if p.timestampformat != dfltTimestampformat {
-		params = append(params, fmt.Sprintf("timestampformat = '%s'", p.timestampformat))
+		params = append(params, fmt.Sprintf("timestampformat = %s", param.QuoteLiteral(p.timestampformat)))
 	}
 	if len(p.types) > 0 {
 		params = append(params, fmt.Sprintf("types = %s", p.types.Format(true)))
@@ -371,6 +401,9 @@ func (p *ReadParams) Params() string {
 	if p.unionByName {
 		params = append(params, "union_by_name = true")
 	}
+	if p.rejects != "" {
+		params = append(params, "store_rejects = true")
+	}
 
 	prefix := ""
 	if len(params) > 0 {
@@ -384,6 +417,18 @@ func (p *ReadParams) GetDescribe() bool {
 	return p.describe
 }
 
+func (p *ReadParams) GetTransform() *transformparam.TransformParams {
+	return p.transform
+}
+
 func (p *ReadParams) GetSampleSize() int64 {
 	return p.sampleSize
 }
+
+func (p *ReadParams) GetRejects() string {
+	return p.rejects
+}
+
+func (p *ReadParams) GetMaxRejects() int64 {
+	return p.maxRejects
+}
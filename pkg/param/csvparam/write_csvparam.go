@@ -0,0 +1,158 @@
+package csvparam
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hbbtekademy/go-fileconv/pkg/param"
+)
+
+// Parameters for writing a CSV file
+type WriteParams struct {
+	delim             string
+	quote             string
+	escape            string
+	header            bool
+	nullStr           string
+	dateformat        string
+	timestampformat   string
+	forceQuote        []string
+	compression       param.Compression
+	keepPartialOutput bool
+}
+
+type WriteParam func(*WriteParams)
+
+const (
+	dfltWriteDelim           string            = ","
+	dfltWriteQuote           string            = `"`
+	dfltWriteEscape          string            = `"`
+	dfltWriteHeader          bool              = true
+	dfltWriteNullStr         string            = ""
+	dfltWriteDateformat      string            = ""
+	dfltWriteTimestampformat string            = ""
+	dfltWriteCompression     param.Compression = param.AutoCompression
+	dfltKeepPartialOutput    bool              = false
+)
+
+func WithWriteDelim(delim string) WriteParam {
+	return func(wp *WriteParams) {
+		wp.delim = delim
+	}
+}
+
+func WithWriteQuote(quote string) WriteParam {
+	return func(wp *WriteParams) {
+		wp.quote = quote
+	}
+}
+
+func WithWriteEscape(escape string) WriteParam {
+	return func(wp *WriteParams) {
+		wp.escape = escape
+	}
+}
+
+func WithWriteHeader(header bool) WriteParam {
+	return func(wp *WriteParams) {
+		wp.header = header
+	}
+}
+
+func WithWriteNullStr(nullStr string) WriteParam {
+	return func(wp *WriteParams) {
+		wp.nullStr = nullStr
+	}
+}
+
+func WithWriteDateformat(dateformat string) WriteParam {
+	return func(wp *WriteParams) {
+		wp.dateformat = dateformat
+	}
+}
+
+func WithWriteTimestampformat(timestampformat string) WriteParam {
+	return func(wp *WriteParams) {
+		wp.timestampformat = timestampformat
+	}
+}
+
+func WithForceQuote(forceQuote ...string) WriteParam {
+	return func(wp *WriteParams) {
+		wp.forceQuote = forceQuote
+	}
+}
+
+func WithWriteCompression(compression param.Compression) WriteParam {
+	return func(wp *WriteParams) {
+		wp.compression = compression
+	}
+}
+
+// Keep the temp output of a failed conversion for debugging instead of removing it
+func WithKeepPartialOutput(keepPartialOutput bool) WriteParam {
+	return func(wp *WriteParams) {
+		wp.keepPartialOutput = keepPartialOutput
+	}
+}
+
+// https://duckdb.org/docs/sql/statements/copy#csv-options
+func NewWriteParams(params ...WriteParam) *WriteParams {
+	csvWriteParams := &WriteParams{
+		delim:             dfltWriteDelim,
+		quote:             dfltWriteQuote,
+		escape:            dfltWriteEscape,
+		header:            dfltWriteHeader,
+		nullStr:           dfltWriteNullStr,
+		dateformat:        dfltWriteDateformat,
+		timestampformat:   dfltWriteTimestampformat,
+		forceQuote:        []string{},
+		compression:       dfltWriteCompression,
+		keepPartialOutput: dfltKeepPartialOutput,
+	}
+
+	for _, param := range params {
+		param(csvWriteParams)
+	}
+
+	return csvWriteParams
+}
+
+// Returns formatted parameters for writing CSV file
+func (p *WriteParams) Params() string {
+	params := []string{"FORMAT CSV"}
+
+	if p.delim != dfltWriteDelim {
+		params = append(params, fmt.Sprintf("DELIMITER %s", param.QuoteLiteral(p.delim)))
+	}
+	if p.quote != dfltWriteQuote {
+		params = append(params, fmt.Sprintf("QUOTE %s", param.QuoteLiteral(p.quote)))
+	}
+	if p.escape != dfltWriteEscape {
+		params = append(params, fmt.Sprintf("ESCAPE %s", param.QuoteLiteral(p.escape)))
+	}
+	if !p.header {
+		params = append(params, "HEADER false")
+	}
+	if p.nullStr != dfltWriteNullStr {
+		params = append(params, fmt.Sprintf("NULLSTR %s", param.QuoteLiteral(p.nullStr)))
+	}
+	if p.dateformat != dfltWriteDateformat {
+		params = append(params, fmt.Sprintf("DATEFORMAT %s", param.QuoteLiteral(p.dateformat)))
+	}
+	if p.timestampformat != dfltWriteTimestampformat {
+		params = append(params, fmt.Sprintf("TIMESTAMPFORMAT %s", param.QuoteLiteral(p.timestampformat)))
+	}
+	if len(p.forceQuote) > 0 {
+		params = append(params, fmt.Sprintf("FORCE_QUOTE (%s)", param.QuoteIdents(p.forceQuote)))
+	}
+	if p.compression != dfltWriteCompression {
+		params = append(params, fmt.Sprintf("COMPRESSION %s", param.QuoteLiteral(string(p.compression))))
+	}
+
+	return fmt.Sprintf("(%s)", strings.Join(params, ","))
+}
+
+func (p *WriteParams) GetKeepPartialOutput() bool {
+	return p.keepPartialOutput
+}
@@ -0,0 +1,199 @@
+package jsonparam
+
+import "fmt"
+
+type ArrayMode string
+
+const (
+	// Keep arrays as LIST columns
+	KeepArrays ArrayMode = "keep"
+	// Explode arrays into one row per element with an index column
+	ExplodeArrays ArrayMode = "explode"
+	// Spread arrays into one column per element up to the longest array
+	SpreadArrays ArrayMode = "spread"
+)
+
+type Collision string
+
+const (
+	// Fail if a flattened column name clashes with another column
+	CollisionError Collision = "error"
+	// Rename the flattened column by appending the separator and a number
+	CollisionRename Collision = "rename"
+)
+
+// Options for flattening nested json
+type FlattenConfig struct {
+	separator string
+	maxDepth  int
+	arrayMode ArrayMode
+	collision Collision
+}
+
+type FlattenOption func(*FlattenConfig)
+
+const (
+	dfltFlattenSeparator string    = "_"
+	dfltFlattenMaxDepth  int       = -1
+	dfltArrayMode        ArrayMode = KeepArrays
+	dfltCollision        Collision = CollisionError
+)
+
+/*
+Separator joining the names of the nested columns, e.g. a_b_c.
+Default "_"
+*/
+func WithFlattenSeparator(separator string) FlattenOption {
+	return func(fc *FlattenConfig) {
+		fc.separator = separator
+	}
+}
+
+/*
+Maximum nesting depth which is flattened. Deeper STRUCT and LIST values are kept as nested columns.
+Default -1, no limit
+*/
+func WithFlattenMaxDepth(maxDepth int) FlattenOption {
+	return func(fc *FlattenConfig) {
+		fc.maxDepth = maxDepth
+	}
+}
+
+/*
+How arrays are flattened. Can be one of ('keep', 'explode', 'spread').
+Exploded arrays add a <name>_index column with the zero based index of the element.
+Spread arrays are written to the <name>_0 to <name>_n columns.
+Default 'keep'
+*/
+func WithArrayMode(arrayMode ArrayMode) FlattenOption {
+	return func(fc *FlattenConfig) {
+		fc.arrayMode = arrayMode
+	}
+}
+
+/*
+How a flattened column name clashing with another column is handled. Can be one of ('error', 'rename').
+Default 'error'
+*/
+func WithCollision(collision Collision) FlattenOption {
+	return func(fc *FlattenConfig) {
+		fc.collision = collision
+	}
+}
+
+func NewFlattenConfig(options ...FlattenOption) *FlattenConfig {
+	config := &FlattenConfig{
+		separator: dfltFlattenSeparator,
+		maxDepth:  dfltFlattenMaxDepth,
+		arrayMode: dfltArrayMode,
+		collision: dfltCollision,
+	}
+
+	for _, option := range options {
+		option(config)
+	}
+
+	return config
+}
+
+// Validates the flatten options
+func (c *FlattenConfig) Validate() error {
+	if c.separator == "" {
+		return fmt.Errorf("flatten separator must not be empty")
+	}
+
+	switch c.arrayMode {
+	case KeepArrays, ExplodeArrays, SpreadArrays:
+	default:
+		return fmt.Errorf("invalid array mode: %s. must be one of (keep, explode, spread)", c.arrayMode)
+	}
+
+	switch c.collision {
+	case CollisionError, CollisionRename:
+	default:
+		return fmt.Errorf("invalid collision: %s. must be one of (error, rename)", c.collision)
+	}
+
+	return nil
+}
+
+func (c *FlattenConfig) GetSeparator() string {
+	return c.separator
+}
+
+func (c *FlattenConfig) GetMaxDepth() int {
+	return c.maxDepth
+}
+
+func (c *FlattenConfig) GetArrayMode() ArrayMode {
+	return c.arrayMode
+}
+
+func (c *FlattenConfig) GetCollision() Collision {
+	return c.collision
+}
+
+// Options for rebuilding nested json from flattened columns
+type UnflattenConfig struct {
+	separator string
+	groups    []string
+}
+
+type UnflattenOption func(*UnflattenConfig)
+
+/*
+Separator joining the names of the flattened columns, e.g. a_b_c.
+Default "_"
+*/
+func WithUnflattenSeparator(separator string) UnflattenOption {
+	return func(uc *UnflattenConfig) {
+		uc.separator = separator
+	}
+}
+
+/*
+Flattened names of the nested objects to rebuild, e.g. address and address_geo for the address_geo_lat column.
+Only the columns of the groups are nested. By default the prefixes shared by two or more columns are nested,
+e.g. address for address_city and address_zip but not first for first_name.
+*/
+func WithUnflattenGroups(groups ...string) UnflattenOption {
+	return func(uc *UnflattenConfig) {
+		uc.groups = groups
+	}
+}
+
+func NewUnflattenConfig(options ...UnflattenOption) *UnflattenConfig {
+	config := &UnflattenConfig{
+		separator: dfltFlattenSeparator,
+		groups:    []string{},
+	}
+
+	for _, option := range options {
+		option(config)
+	}
+
+	return config
+}
+
+// Validates the unflatten options
+func (c *UnflattenConfig) Validate() error {
+	if c.separator == "" {
+		return fmt.Errorf("unflatten separator must not be empty")
+	}
+
+	for _, group := range c.groups {
+		if group == "" {
+			return fmt.Errorf("unflatten group must not be empty")
+		}
+	}
+
+	return nil
+}
+
+func (c *UnflattenConfig) GetSeparator() string {
+	return c.separator
+}
+
+func (c *UnflattenConfig) GetGroups() []string {
+	return c.groups
+}
@@ -1,6 +1,7 @@
 package jsonparam
 
 import (
+	"reflect"
 	"testing"
 
 	"github.com/hbbtekademy/go-fileconv/pkg/param"
@@ -44,7 +45,15 @@ func TestReadParams(t *testing.T) {
 					{Name: "key2", Type: "VARCHAR"},
 				}),
 			},
-			expectedOutput: ",columns = {key1: 'INT',key2: 'VARCHAR'}",
+			expectedOutput: `,columns = {"key1": 'INT',"key2": 'VARCHAR'}`,
+		},
+		{
+			name: "TC4",
+			params: []ReadParam{
+				WithFormat(NewlineDelimited),
+				WithRejects("rejects.json"),
+			},
+			expectedOutput: ",format = 'newline_delimited',ignore_errors = true",
 		},
 	}
 
@@ -59,3 +68,300 @@ func TestReadParams(t *testing.T) {
 		})
 	}
 }
+
+func TestWriteParams(t *testing.T) {
+	tests := []struct {
+		name           string
+		params         []WriteParam
+		expectedOutput string
+	}{
+		{
+			name:           "TC1",
+			params:         []WriteParam{},
+			expectedOutput: "(FORMAT JSON)",
+		},
+		{
+			name: "TC2",
+			params: []WriteParam{
+				WithWriteFormat(Array),
+				WithWriteCompression(param.Zstd),
+				WithWriteDateFormat("%d/%m/%Y"),
+				WithWriteTimestampFormat("%d/%m/%Y %H:%M"),
+				WithNestedAsString(true),
+			},
+			expectedOutput: "(FORMAT JSON,ARRAY true,COMPRESSION 'zstd',DATEFORMAT '%d/%m/%Y',TIMESTAMPFORMAT '%d/%m/%Y %H:%M')",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			params := NewWriteParams(tc.params...)
+			actualOutput := params.Params()
+
+			if actualOutput != tc.expectedOutput {
+				t.Fatalf("expected: %s\nbut got: %s", tc.expectedOutput, actualOutput)
+			}
+		})
+	}
+}
+
+func TestFlattenConfigValidate(t *testing.T) {
+	tests := []struct {
+		name        string
+		options     []FlattenOption
+		expectedErr string
+	}{
+		{
+			name:        "TC1",
+			options:     []FlattenOption{WithFlattenSeparator("."), WithArrayMode(ExplodeArrays), WithCollision(CollisionRename)},
+			expectedErr: "",
+		},
+		{
+			name:        "TC2",
+			options:     []FlattenOption{WithFlattenSeparator("")},
+			expectedErr: "flatten separator must not be empty",
+		},
+		{
+			name:        "TC3",
+			options:     []FlattenOption{WithArrayMode("zip")},
+			expectedErr: "invalid array mode: zip. must be one of (keep, explode, spread)",
+		},
+		{
+			name:        "TC4",
+			options:     []FlattenOption{WithCollision("skip")},
+			expectedErr: "invalid collision: skip. must be one of (error, rename)",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := NewFlattenConfig(tc.options...).Validate()
+			if tc.expectedErr == "" {
+				if err != nil {
+					t.Fatalf("expected no error but got: %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tc.expectedErr {
+				t.Fatalf("expected error: %s but got: %v", tc.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestUnflattenConfigValidate(t *testing.T) {
+	tests := []struct {
+		name        string
+		options     []UnflattenOption
+		expectedErr string
+	}{
+		{
+			name:        "TC1",
+			options:     []UnflattenOption{WithUnflattenSeparator("."), WithUnflattenGroups("address", "address.geo")},
+			expectedErr: "",
+		},
+		{
+			name:        "TC2",
+			options:     []UnflattenOption{WithUnflattenSeparator("")},
+			expectedErr: "unflatten separator must not be empty",
+		},
+		{
+			name:        "TC3",
+			options:     []UnflattenOption{WithUnflattenGroups("address", "")},
+			expectedErr: "unflatten group must not be empty",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := NewUnflattenConfig(tc.options...).Validate()
+			if tc.expectedErr == "" {
+				if err != nil {
+					t.Fatalf("expected no error but got: %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tc.expectedErr {
+				t.Fatalf("expected error: %s but got: %v", tc.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestParsePath(t *testing.T) {
+	tests := []struct {
+		name             string
+		path             string
+		expectedSegments []PathSegment
+		expectedErr      string
+	}{
+		{
+			name:             "TC1",
+			path:             "$.data.items[*]",
+			expectedSegments: []PathSegment{{Key: "data"}, {Key: "items"}, {Wildcard: true}},
+		},
+		{
+			name:             "TC2",
+			path:             `$['my key']["items.all"][*].id`,
+			expectedSegments: []PathSegment{{Key: "my key"}, {Key: "items.all"}, {Wildcard: true}, {Key: "id"}},
+		},
+		{
+			name:             "TC3",
+			path:             "meta.generated_at",
+			expectedSegments: []PathSegment{{Key: "meta"}, {Key: "generated_at"}},
+		},
+		{
+			name:        "TC4",
+			path:        "$.data..items",
+			expectedErr: "invalid path: $.data..items. missing key at 7",
+		},
+		{
+			name:        "TC5",
+			path:        "$.data['items",
+			expectedErr: "invalid path: $.data['items. unterminated key at 6",
+		},
+		{
+			name:        "TC6",
+			path:        "$.data[0]",
+			expectedErr: `invalid path: $.data[0]. unexpected "[0]" at 6`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			segments, err := ParsePath(tc.path)
+			if tc.expectedErr != "" {
+				if err == nil || err.Error() != tc.expectedErr {
+					t.Fatalf("expected error: %s but got: %v", tc.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("failed parsing path. error: %v", err)
+			}
+			if !reflect.DeepEqual(segments, tc.expectedSegments) {
+				t.Fatalf("expected: %v but got: %v", tc.expectedSegments, segments)
+			}
+		})
+	}
+}
+
+func TestParseJsonSchema(t *testing.T) {
+	tests := []struct {
+		name            string
+		schema          string
+		expectedColumns param.Columns
+		expectedErr     string
+	}{
+		{
+			name: "TC1",
+			schema: `{
+  "type": "object",
+  "properties": {
+    "id": {"type": "integer"},
+    "status": {"type": "string", "enum": ["new", "shipped"]},
+    "created_at": {"type": "string", "format": "date-time"},
+    "total": {"type": ["number", "null"]},
+    "customer": {
+      "type": "object",
+      "properties": {
+        "name": {"type": "string"},
+        "birth date": {"type": "string", "format": "date"}
+      }
+    },
+    "items": {"type": "array", "items": {"$ref": "#/definitions/item"}},
+    "labels": {"type": "object", "additionalProperties": {"type": "string"}},
+    "extra": {}
+  },
+  "definitions": {
+    "item": {"type": "object", "properties": {"sku": {"type": "string"}, "qty": {"type": "integer"}}}
+  }
+}`,
+			expectedColumns: param.Columns{
+				{Name: "id", Type: "BIGINT"},
+				{Name: "status", Type: "VARCHAR"},
+				{Name: "created_at", Type: "TIMESTAMP"},
+				{Name: "total", Type: "DOUBLE"},
+				{Name: "customer", Type: `STRUCT(name VARCHAR, "birth date" DATE)`},
+				{Name: "items", Type: "STRUCT(sku VARCHAR, qty BIGINT)[]"},
+				{Name: "labels", Type: "MAP(VARCHAR, VARCHAR)"},
+				{Name: "extra", Type: "JSON"},
+			},
+		},
+		{
+			name: "TC2",
+			schema: `{
+  "properties": {
+    "code": {"enum": [1, 2, 3]},
+    "note": {"anyOf": [{"type": "string"}, {"type": "null"}]},
+    "value": {"oneOf": [{"type": "string"}, {"type": "integer"}]},
+    "tags": {"type": "array"},
+    "flag": {"const": true}
+  }
+}`,
+			expectedColumns: param.Columns{
+				{Name: "code", Type: "BIGINT"},
+				{Name: "note", Type: "VARCHAR"},
+				{Name: "value", Type: "JSON"},
+				{Name: "tags", Type: "JSON[]"},
+				{Name: "flag", Type: "BOOLEAN"},
+			},
+		},
+		{
+			name:        "TC3",
+			schema:      `{"type": "string"}`,
+			expectedErr: "the root of the json schema must be an object, or an array of objects, with properties",
+		},
+		{
+			name:        "TC4",
+			schema:      `{"properties": {"a": {"$ref": "#/$defs/node"}}, "$defs": {"node": {"properties": {"child": {"$ref": "#/$defs/node"}}}}}`,
+			expectedErr: `failed getting type of property "a". error: failed getting type of property "child". error: recursive $ref #/$defs/node is not supported`,
+		},
+		{
+			name:        "TC5",
+			schema:      `{"properties": {"a": {"$ref": "other.json#/a"}}}`,
+			expectedErr: `failed getting type of property "a". error: unsupported $ref: other.json#/a. only references to the $defs and definitions of the schema are supported`,
+		},
+		{
+			name:        "TC6",
+			schema:      `{"properties": {"a": {"type": "string"}, "A": {"type": "string"}}}`,
+			expectedErr: "duplicate column name: A",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			columns, err := ParseJsonSchema([]byte(tc.schema))
+			if tc.expectedErr != "" {
+				if err == nil || err.Error() != tc.expectedErr {
+					t.Fatalf("expected error: %s but got: %v", tc.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("failed parsing json schema. error: %v", err)
+			}
+			if !reflect.DeepEqual(columns, tc.expectedColumns) {
+				t.Fatalf("expected: %v but got: %v", tc.expectedColumns, columns)
+			}
+		})
+	}
+}
+
+func TestLoadJsonSchema(t *testing.T) {
+	columns, err := LoadJsonSchema("../../../testdata/schema/iris_jsonschema.json")
+	if err != nil {
+		t.Fatalf("failed loading json schema. error: %v", err)
+	}
+
+	expected := param.Columns{
+		{Name: "sepalLength", Type: "DOUBLE"},
+		{Name: "sepalWidth", Type: "DOUBLE"},
+		{Name: "petalLength", Type: "DOUBLE"},
+		{Name: "petalWidth", Type: "DOUBLE"},
+		{Name: "species", Type: "VARCHAR"},
+	}
+	if !reflect.DeepEqual(columns, expected) {
+		t.Fatalf("expected: %v but got: %v", expected, columns)
+	}
+}
@@ -0,0 +1,474 @@
+package jsonparam
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/hbbtekademy/go-fileconv/pkg/model"
+	"github.com/hbbtekademy/go-fileconv/pkg/param"
+)
+
+// Keywords of a JSON Schema describing the type of a value. The other keywords are ignored.
+type jsonSchema struct {
+	Type                 schemaTypes            `json:"type"`
+	Format               string                 `json:"format"`
+	Enum                 []any                  `json:"enum"`
+	Const                json.RawMessage        `json:"const"`
+	Properties           schemaProperties       `json:"properties"`
+	AdditionalProperties *jsonSchema            `json:"additionalProperties"`
+	Items                *schemaItems           `json:"items"`
+	Ref                  string                 `json:"$ref"`
+	AnyOf                []*jsonSchema          `json:"anyOf"`
+	OneOf                []*jsonSchema          `json:"oneOf"`
+	AllOf                []*jsonSchema          `json:"allOf"`
+	Defs                 map[string]*jsonSchema `json:"$defs"`
+	Definitions          map[string]*jsonSchema `json:"definitions"`
+
+	// Set for the true and false schemas
+	boolean *bool
+}
+
+// Type keyword, either a single type or a list of types
+type schemaTypes []string
+
+// Property of an object, in the order of the schema
+type schemaProperty struct {
+	name   string
+	schema *jsonSchema
+}
+
+type schemaProperties []*schemaProperty
+
+// Items keyword, either the schema of all elements or the schemas of the elements of a tuple
+type schemaItems struct {
+	schema *jsonSchema
+	tuple  []*jsonSchema
+}
+
+/*
+Loads the columns from a JSON Schema file describing the json records, so that the column types don't depend on the sampled records.
+The properties of the root object are the columns. For an array of records, the properties of its items are the columns.
+Types are mapped as:
+  - string to VARCHAR, or to TIMESTAMP, DATE, TIME and UUID for the date-time, date, time and uuid formats
+  - integer to BIGINT, number to DOUBLE and boolean to BOOLEAN
+  - object to STRUCT of its properties, or to MAP(VARCHAR, <type>) if it only has additionalProperties
+  - array to LIST of its items
+  - enum and const to VARCHAR for strings and to the type of their values otherwise
+  - values which can have several types to JSON
+
+Local $ref references to $defs and definitions are resolved. anyOf and oneOf with a single non null schema are read as the schema.
+*/
+func LoadJsonSchema(path string) (param.Columns, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	columns, err := ParseJsonSchema(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid json schema file: %s. error: %w", path, err)
+	}
+	return columns, nil
+}
+
+// Returns the columns of the JSON Schema document. See LoadJsonSchema.
+func ParseJsonSchema(data []byte) (param.Columns, error) {
+	root := &jsonSchema{}
+	if err := json.Unmarshal(data, root); err != nil {
+		return nil, fmt.Errorf("failed parsing json schema. error: %w", err)
+	}
+
+	c := &schemaConverter{root: root, resolving: map[string]bool{}}
+	schema, err := c.resolve(root)
+	if err != nil {
+		return nil, err
+	}
+	// a file with an array of records
+	if schema.hasType("array") && schema.Items != nil && schema.Items.schema != nil {
+		schema, err = c.resolve(schema.Items.schema)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(schema.Properties) == 0 {
+		return nil, fmt.Errorf("the root of the json schema must be an object, or an array of objects, with properties")
+	}
+
+	columns := &model.Schema{}
+	for _, property := range schema.Properties {
+		dataType, err := c.dataType(property.schema)
+		if err != nil {
+			return nil, fmt.Errorf("failed getting type of property %q. error: %w", property.name, err)
+		}
+		columns.Columns = append(columns.Columns, &model.ColumnDesc{ColName: property.name, ColType: model.ColumnType(dataType.String())})
+	}
+
+	if err := columns.Validate(); err != nil {
+		return nil, err
+	}
+	return columns.ParamColumns(), nil
+}
+
+// Converts the schemas of a document to DuckDB types
+type schemaConverter struct {
+	root *jsonSchema
+	// $ref references being resolved, to reject recursive schemas
+	resolving map[string]bool
+}
+
+func (c *schemaConverter) dataType(schema *jsonSchema) (*model.DataType, error) {
+	if schema.Ref != "" {
+		if c.resolving[schema.Ref] {
+			return nil, fmt.Errorf("recursive $ref %s is not supported", schema.Ref)
+		}
+		ref, err := c.ref(schema.Ref)
+		if err != nil {
+			return nil, err
+		}
+		c.resolving[schema.Ref] = true
+		defer delete(c.resolving, schema.Ref)
+		return c.dataType(ref)
+	}
+
+	if schema.boolean != nil {
+		return jsonType(), nil
+	}
+
+	types := schema.nonNullTypes()
+	switch {
+	case len(types) > 1:
+		if len(types) == 2 && schema.hasType("integer") && schema.hasType("number") {
+			return &model.DataType{Name: "DOUBLE"}, nil
+		}
+		return jsonType(), nil
+
+	case len(types) == 1:
+		return c.typeOf(types[0], schema)
+
+	case len(schema.Type) > 0:
+		// only null values
+		return jsonType(), nil
+
+	case schema.Enum != nil || schema.Const != nil:
+		return enumType(schema)
+
+	case schema.Properties != nil || schema.AdditionalProperties != nil:
+		return c.typeOf("object", schema)
+
+	case schema.Items != nil:
+		return c.typeOf("array", schema)
+
+	case len(schema.AllOf) > 0:
+		return c.allOfType(schema.AllOf)
+
+	case len(schema.AnyOf) > 0:
+		return c.anyOfType(schema.AnyOf)
+
+	case len(schema.OneOf) > 0:
+		return c.anyOfType(schema.OneOf)
+	}
+
+	// any value
+	return jsonType(), nil
+}
+
+// Returns the DuckDB type of the values of the json type kind
+func (c *schemaConverter) typeOf(kind string, schema *jsonSchema) (*model.DataType, error) {
+	switch kind {
+	case "string":
+		if name, ok := stringFormats[schema.Format]; ok {
+			return &model.DataType{Name: name}, nil
+		}
+		return &model.DataType{Name: "VARCHAR"}, nil
+
+	case "integer":
+		return &model.DataType{Name: "BIGINT"}, nil
+
+	case "number":
+		return &model.DataType{Name: "DOUBLE"}, nil
+
+	case "boolean":
+		return &model.DataType{Name: "BOOLEAN"}, nil
+
+	case "object":
+		if len(schema.Properties) > 0 {
+			fields := make([]*model.Field, 0, len(schema.Properties))
+			for _, property := range schema.Properties {
+				fieldType, err := c.dataType(property.schema)
+				if err != nil {
+					return nil, fmt.Errorf("failed getting type of property %q. error: %w", property.name, err)
+				}
+				fields = append(fields, &model.Field{Name: property.name, Type: fieldType})
+			}
+			return &model.DataType{Name: model.TypeStruct, Fields: fields}, nil
+		}
+		if schema.AdditionalProperties != nil && schema.AdditionalProperties.boolean == nil {
+			valueType, err := c.dataType(schema.AdditionalProperties)
+			if err != nil {
+				return nil, err
+			}
+			return &model.DataType{Name: model.TypeMap, Key: &model.DataType{Name: "VARCHAR"}, Value: valueType}, nil
+		}
+		if len(schema.AllOf) > 0 {
+			return c.allOfType(schema.AllOf)
+		}
+		// objects with any keys
+		return jsonType(), nil
+
+	case "array":
+		elemType := jsonType()
+		if schema.Items != nil && schema.Items.schema != nil {
+			var err error
+			elemType, err = c.dataType(schema.Items.schema)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return &model.DataType{Name: model.TypeList, Elem: elemType}, nil
+	}
+
+	return nil, fmt.Errorf("unsupported type: %s", kind)
+}
+
+// Returns the type of the values of any of the schemas. Null schemas are skipped, so nullable values get the type of their schema.
+func (c *schemaConverter) anyOfType(schemas []*jsonSchema) (*model.DataType, error) {
+	var dataType *model.DataType
+	for _, schema := range schemas {
+		resolved, err := c.resolve(schema)
+		if err != nil {
+			return nil, err
+		}
+		if len(resolved.Type) > 0 && len(resolved.nonNullTypes()) == 0 {
+			continue
+		}
+
+		alt, err := c.dataType(schema)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case dataType == nil || dataType.String() == alt.String():
+			dataType = alt
+		case isNumeric(dataType) && isNumeric(alt):
+			dataType = &model.DataType{Name: "DOUBLE"}
+		default:
+			return jsonType(), nil
+		}
+	}
+
+	if dataType == nil {
+		return jsonType(), nil
+	}
+	return dataType, nil
+}
+
+// Returns the type of the values of all the schemas. The properties of object schemas are merged.
+func (c *schemaConverter) allOfType(schemas []*jsonSchema) (*model.DataType, error) {
+	if len(schemas) == 1 {
+		return c.dataType(schemas[0])
+	}
+
+	merged := &model.DataType{Name: model.TypeStruct}
+	names := map[string]bool{}
+	for _, schema := range schemas {
+		dataType, err := c.dataType(schema)
+		if err != nil {
+			return nil, err
+		}
+		if dataType.Name != model.TypeStruct {
+			return nil, fmt.Errorf("allOf is only supported for object schemas with properties")
+		}
+		for _, field := range dataType.Fields {
+			if !names[strings.ToLower(field.Name)] {
+				names[strings.ToLower(field.Name)] = true
+				merged.Fields = append(merged.Fields, field)
+			}
+		}
+	}
+	return merged, nil
+}
+
+// Returns the schema referenced by the schema if it has a $ref
+func (c *schemaConverter) resolve(schema *jsonSchema) (*jsonSchema, error) {
+	for depth := 0; schema.Ref != ""; depth++ {
+		if depth > len(c.root.Defs)+len(c.root.Definitions) {
+			return nil, fmt.Errorf("recursive $ref %s is not supported", schema.Ref)
+		}
+		ref, err := c.ref(schema.Ref)
+		if err != nil {
+			return nil, err
+		}
+		schema = ref
+	}
+	return schema, nil
+}
+
+// Returns the schema of a local reference to the $defs or definitions of the document
+func (c *schemaConverter) ref(ref string) (*jsonSchema, error) {
+	var defs map[string]*jsonSchema
+	name := ""
+	switch {
+	case strings.HasPrefix(ref, "#/$defs/"):
+		defs, name = c.root.Defs, strings.TrimPrefix(ref, "#/$defs/")
+	case strings.HasPrefix(ref, "#/definitions/"):
+		defs, name = c.root.Definitions, strings.TrimPrefix(ref, "#/definitions/")
+	default:
+		return nil, fmt.Errorf("unsupported $ref: %s. only references to the $defs and definitions of the schema are supported", ref)
+	}
+
+	// json pointer escapes
+	name = strings.ReplaceAll(strings.ReplaceAll(name, "~1", "/"), "~0", "~")
+	schema, ok := defs[name]
+	if !ok {
+		return nil, fmt.Errorf("$ref not found: %s", ref)
+	}
+	return schema, nil
+}
+
+func (s *jsonSchema) hasType(jsonType string) bool {
+	for _, t := range s.Type {
+		if t == jsonType {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *jsonSchema) nonNullTypes() []string {
+	types := []string{}
+	for _, t := range s.Type {
+		if t != "null" {
+			types = append(types, t)
+		}
+	}
+	return types
+}
+
+// Returns VARCHAR for string values, and the type of the values otherwise
+func enumType(schema *jsonSchema) (*model.DataType, error) {
+	values := schema.Enum
+	if schema.Const != nil {
+		var value any
+		if err := json.Unmarshal(schema.Const, &value); err != nil {
+			return nil, err
+		}
+		values = []any{value}
+	}
+
+	var dataType *model.DataType
+	for _, value := range values {
+		var valueType *model.DataType
+		switch v := value.(type) {
+		case nil:
+			continue
+		case string:
+			valueType = &model.DataType{Name: "VARCHAR"}
+		case bool:
+			valueType = &model.DataType{Name: "BOOLEAN"}
+		case float64:
+			valueType = &model.DataType{Name: "DOUBLE"}
+			if v == float64(int64(v)) {
+				valueType = &model.DataType{Name: "BIGINT"}
+			}
+		default:
+			return jsonType(), nil
+		}
+
+		switch {
+		case dataType == nil || dataType.Name == valueType.Name:
+			dataType = valueType
+		case isNumeric(dataType) && isNumeric(valueType):
+			dataType = &model.DataType{Name: "DOUBLE"}
+		default:
+			return jsonType(), nil
+		}
+	}
+
+	if dataType == nil {
+		return jsonType(), nil
+	}
+	return dataType, nil
+}
+
+func isNumeric(dataType *model.DataType) bool {
+	return dataType.Name == "BIGINT" || dataType.Name == "DOUBLE"
+}
+
+func jsonType() *model.DataType {
+	return &model.DataType{Name: "JSON"}
+}
+
+// DuckDB types of the formats of string values
+var stringFormats = map[string]string{
+	"date-time": "TIMESTAMP",
+	"date":      "DATE",
+	"time":      "TIME",
+	"uuid":      "UUID",
+}
+
+func (s *jsonSchema) UnmarshalJSON(data []byte) error {
+	var boolean bool
+	if err := json.Unmarshal(data, &boolean); err == nil {
+		s.boolean = &boolean
+		return nil
+	}
+
+	// decodes the keywords without calling this method again
+	type keywords jsonSchema
+	return json.Unmarshal(data, (*keywords)(s))
+}
+
+func (t *schemaTypes) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*t = schemaTypes{single}
+		return nil
+	}
+
+	var types []string
+	if err := json.Unmarshal(data, &types); err != nil {
+		return fmt.Errorf("type must be a string or an array of strings")
+	}
+	*t = types
+	return nil
+}
+
+// Decodes the properties in the order of the document
+func (p *schemaProperties) UnmarshalJSON(data []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+	if token != json.Delim('{') {
+		return fmt.Errorf("properties must be an object")
+	}
+
+	properties := schemaProperties{}
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return err
+		}
+		schema := &jsonSchema{}
+		if err := decoder.Decode(schema); err != nil {
+			return err
+		}
+		properties = append(properties, &schemaProperty{name: token.(string), schema: schema})
+	}
+
+	*p = properties
+	return nil
+}
+
+func (i *schemaItems) UnmarshalJSON(data []byte) error {
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("[")) {
+		return json.Unmarshal(data, &i.tuple)
+	}
+
+	i.schema = &jsonSchema{}
+	return json.Unmarshal(data, i.schema)
+}
@@ -0,0 +1,61 @@
+package jsonparam
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Segment of a JSONPath-like path, either the key of an object or [*] for the elements of an array
+type PathSegment struct {
+	Key      string
+	Wildcard bool
+}
+
+/*
+Parses a JSONPath-like path of object keys and array wildcards, e.g. $.data.items[*] or $['my key'].items[*].
+Keys are separated by dots or given in brackets with single or double quotes. The leading $ is optional.
+*/
+func ParsePath(path string) ([]PathSegment, error) {
+	s := strings.TrimSpace(path)
+	segments := []PathSegment{}
+
+	start := 0
+	if strings.HasPrefix(s, "$") {
+		start = 1
+	}
+	for i := start; i < len(s); {
+		switch {
+		case strings.HasPrefix(s[i:], "[*]"):
+			segments = append(segments, PathSegment{Wildcard: true})
+			i += 3
+
+		case strings.HasPrefix(s[i:], "['") || strings.HasPrefix(s[i:], `["`):
+			quote := s[i+1 : i+2]
+			end := strings.Index(s[i+2:], quote+"]")
+			if end < 0 {
+				return nil, fmt.Errorf("invalid path: %s. unterminated key at %d", path, i)
+			}
+			segments = append(segments, PathSegment{Key: s[i+2 : i+2+end]})
+			i += end + 4
+
+		case s[i] == '.' || i == 0:
+			if s[i] == '.' {
+				i++
+			}
+			end := strings.IndexAny(s[i:], ".[")
+			if end < 0 {
+				end = len(s) - i
+			}
+			if end == 0 {
+				return nil, fmt.Errorf("invalid path: %s. missing key at %d", path, i)
+			}
+			segments = append(segments, PathSegment{Key: s[i : i+end]})
+			i += end
+
+		default:
+			return nil, fmt.Errorf("invalid path: %s. unexpected %q at %d", path, s[i:], i)
+		}
+	}
+
+	return segments, nil
+}
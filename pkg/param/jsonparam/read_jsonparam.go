@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/hbbtekademy/go-fileconv/pkg/param"
+	"github.com/hbbtekademy/go-fileconv/pkg/param/transformparam"
 )
 
 type Format string
@@ -42,7 +43,13 @@ type ReadParams struct {
 	timestampformat  string
 	unionByName      bool
 	flatten          bool
+	flattenConfig    *FlattenConfig
 	describe         bool
+	transform        *transformparam.TransformParams
+	rejects          string
+	maxRejects       int64
+	recordsPath      string
+	recordsCarry     []string
 }
 
 type ReadParam func(*ReadParams)
@@ -64,6 +71,7 @@ const (
 	dfltUnionByName     bool              = false
 	dfltFlatten         bool              = false
 	dfltDescribe        bool              = false
+	dfltMaxRejects      int64             = -1
 )
 
 /*
@@ -258,6 +266,16 @@ func WithFlatten(flatten bool) ReadParam {
 	}
 }
 
+/*
+Options for flattening nested json. Only used if the json is flattened.
+See the FlattenOption functions for the available options.
+*/
+func WithFlattenConfig(options ...FlattenOption) ReadParam {
+	return func(jp *ReadParams) {
+		jp.flattenConfig = NewFlattenConfig(options...)
+	}
+}
+
 /*
 Describe the file columns.
 Default false
@@ -268,6 +286,61 @@ func WithDescribe(describe bool) ReadParam {
 	}
 }
 
+/*
+Transform the rows read from the file before they are written.
+See transformparam for the available transformations.
+*/
+func WithTransform(options ...transformparam.TransformParam) ReadParam {
+	return func(jp *ReadParams) {
+		jp.transform = transformparam.NewTransformParams(options...)
+	}
+}
+
+/*
+Write the malformed lines, and the lines with a value which cannot be converted to the type of its column of the columns param,
+to the rejects file with their file name, line number, column and error
+instead of failing the conversion (only possible when format is 'newline_delimited').
+The rejects file is written as json if it has a .json extension, otherwise as csv.
+*/
+func WithRejects(rejects string) ReadParam {
+	return func(jp *ReadParams) {
+		jp.rejects = rejects
+	}
+}
+
+/*
+Fail the conversion if more than maxRejects lines are rejected.
+Default -1, no limit
+*/
+func WithMaxRejects(maxRejects int64) ReadParam {
+	return func(jp *ReadParams) {
+		jp.maxRejects = maxRejects
+	}
+}
+
+/*
+JSONPath-like path of the array of records inside the json documents, e.g. $.data.items[*].
+Each element of the array is read as a row, with the keys of object elements as the columns.
+The format must match the documents, e.g. AutoFormat for a file with a single json object.
+Every document is read as one json value, so the maximum object size is raised to the uncompressed size of the largest local file.
+Default empty, the documents are the records
+*/
+func WithRecordsPath(recordsPath string) ReadParam {
+	return func(jp *ReadParams) {
+		jp.recordsPath = recordsPath
+	}
+}
+
+/*
+Paths of the fields of the json documents copied onto every record read with the records path, e.g. $.meta.generated_at.
+The columns are named after the keys of the path joined with _, e.g. meta_generated_at.
+*/
+func WithRecordsCarry(fields ...string) ReadParam {
+	return func(jp *ReadParams) {
+		jp.recordsCarry = fields
+	}
+}
+
 // https://duckdb.org/docs/data/json/overview#parameters
 func NewReadParams(params ...ReadParam) *ReadParams {
 	jsonParams := &ReadParams{
@@ -287,7 +360,11 @@ func NewReadParams(params ...ReadParam) *ReadParams {
 		timestampformat:  dfltTimestampFormat,
 		unionByName:      dfltUnionByName,
 		flatten:          dfltFlatten,
+		flattenConfig:    NewFlattenConfig(),
 		describe:         dfltDescribe,
+		transform:        transformparam.NewTransformParams(),
+		maxRejects:       dfltMaxRejects,
+		recordsCarry:     []string{},
 	}
 
 	for _, param := range params {
@@ -306,7 +383,7 @@ func (p *ReadParams) Params() string {
 	}
 
 	if p.compression != dfltCompression {
-		params = append(params, fmt.Sprintf("compression = '%s'", p.compression))
+		params = append(params, fmt.Sprintf("compression = %s", param.QuoteLiteral(string(p.compression))))
 	}
 
 	if p.convStr2Int {
@@ -314,7 +391,7 @@ func (p *ReadParams) Params() string {
 	}
 
 	if p.dateformat != dfltDateFormat {
-		params = append(params, fmt.Sprintf("dateformat = '%s'", p.dateformat))
+		params = append(params, fmt.Sprintf("dateformat = %s", param.QuoteLiteral(p.dateformat)))
 	}
 
 	if p.filename {
@@ -322,14 +399,15 @@ func (p *ReadParams) Params() string {
 	}
 
 	if p.format != dfltFormat {
-		params = append(params, fmt.Sprintf("format = '%s'", p.format))
+		params = append(params, fmt.Sprintf("format = %s", param.QuoteLiteral(string(p.format))))
 	}
 
 	if p.hivePartitioning {
 		params = append(params, "hive_partitioning = true")
 	}
 
-	if p.ignoreErrors {
+	// rejected lines are skipped by the scan and captured separately
+	if p.ignoreErrors || p.rejects != "" {
 		params = append(params, "ignore_errors = true")
 	}
 
@@ -342,7 +420,7 @@ func (p *ReadParams) Params() string {
 	}
 
 	if p.records != dfltRecords {
-		params = append(params, fmt.Sprintf("records = '%s'", p.records))
+		params = append(params, fmt.Sprintf("records = %s", param.QuoteLiteral(string(p.records))))
 	}
 
 	if p.sampleSize != dfltSampleSize {
@@ -350,7 +428,7 @@ func (p *ReadParams) Params() string {
 	}
 
 	if p.timestampformat != dfltTimestampFormat {
-		params = append(params, fmt.Sprintf("timestampformat = '%s'", p.timestampformat))
+		params = append(params, fmt.Sprintf("timestampformat = %s", param.QuoteLiteral(p.timestampformat)))
 	}
 
 	if p.unionByName {
@@ -369,14 +447,54 @@ func (p *ReadParams) Params() string {
 	return prefix + strings.Join(params, ",")
 }
 
+func (p *ReadParams) GetColumns() param.Columns {
+	return p.columns
+}
+
+func (p *ReadParams) GetCompression() param.Compression {
+	return p.compression
+}
+
+func (p *ReadParams) GetMaxObjSize() uint64 {
+	return p.maxObjSize
+}
+
 func (p *ReadParams) GetFlatten() bool {
 	return p.flatten
 }
 
+func (p *ReadParams) GetFlattenConfig() *FlattenConfig {
+	return p.flattenConfig
+}
+
 func (p *ReadParams) GetDescribe() bool {
 	return p.describe
 }
 
+func (p *ReadParams) GetTransform() *transformparam.TransformParams {
+	return p.transform
+}
+
 func (p *ReadParams) GetSampleSize() uint64 {
 	return p.sampleSize
 }
+
+func (p *ReadParams) GetFormat() Format {
+	return p.format
+}
+
+func (p *ReadParams) GetRejects() string {
+	return p.rejects
+}
+
+func (p *ReadParams) GetMaxRejects() int64 {
+	return p.maxRejects
+}
+
+func (p *ReadParams) GetRecordsPath() string {
+	return p.recordsPath
+}
+
+func (p *ReadParams) GetRecordsCarry() []string {
+	return p.recordsCarry
+}
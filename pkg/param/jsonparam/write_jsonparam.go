@@ -0,0 +1,175 @@
+package jsonparam
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hbbtekademy/go-fileconv/pkg/param"
+)
+
+// Parameters for writing a JSON file
+type WriteParams struct {
+	format            Format
+	compression       param.Compression
+	dateformat        string
+	timestampformat   string
+	nestedAsString    bool
+	unflatten         bool
+	unflattenConfig   *UnflattenConfig
+	keepPartialOutput bool
+}
+
+type WriteParam func(*WriteParams)
+
+const (
+	dfltWriteFormat          Format            = NewlineDelimited
+	dfltWriteCompression     param.Compression = param.AutoCompression
+	dfltWriteDateformat      string            = ""
+	dfltWriteTimestampformat string            = ""
+	dfltNestedAsString       bool              = false
+	dfltUnflatten            bool              = false
+	dfltKeepPartialOutput    bool              = false
+)
+
+/*
+Can be one of ('newline_delimited', 'array').
+Default 'newline_delimited'
+
+https://duckdb.org/docs/sql/statements/copy#json-options
+*/
+func WithWriteFormat(format Format) WriteParam {
+	return func(wp *WriteParams) {
+		wp.format = format
+	}
+}
+
+/*
+The compression type for the output file.
+By default this will be detected automatically from the file extension.
+
+https://duckdb.org/docs/sql/statements/copy#json-options
+*/
+func WithWriteCompression(compression param.Compression) WriteParam {
+	return func(wp *WriteParams) {
+		wp.compression = compression
+	}
+}
+
+/*
+Specifies the date format to use when writing dates.
+
+https://duckdb.org/docs/sql/functions/dateformat
+*/
+func WithWriteDateFormat(dateformat string) WriteParam {
+	return func(wp *WriteParams) {
+		wp.dateformat = dateformat
+	}
+}
+
+/*
+Specifies the date format to use when writing timestamps.
+
+https://duckdb.org/docs/sql/functions/dateformat
+*/
+func WithWriteTimestampFormat(timestampformat string) WriteParam {
+	return func(wp *WriteParams) {
+		wp.timestampformat = timestampformat
+	}
+}
+
+/*
+Write nested STRUCT, LIST and MAP columns as JSON encoded strings instead of nested objects.
+Default false
+*/
+func WithNestedAsString(nestedAsString bool) WriteParam {
+	return func(wp *WriteParams) {
+		wp.nestedAsString = nestedAsString
+	}
+}
+
+/*
+Rebuild nested objects from flattened columns before writing, e.g. the address_city and address_zip columns
+are written to the city and zip keys of an address object.
+Default false
+*/
+func WithUnflatten(unflatten bool) WriteParam {
+	return func(wp *WriteParams) {
+		wp.unflatten = unflatten
+	}
+}
+
+/*
+Options for rebuilding nested objects. Only used if unflatten is set.
+See the UnflattenOption functions for the available options.
+*/
+func WithUnflattenConfig(options ...UnflattenOption) WriteParam {
+	return func(wp *WriteParams) {
+		wp.unflattenConfig = NewUnflattenConfig(options...)
+	}
+}
+
+// Keep the temp output of a failed conversion for debugging instead of removing it
+func WithKeepPartialOutput(keepPartialOutput bool) WriteParam {
+	return func(wp *WriteParams) {
+		wp.keepPartialOutput = keepPartialOutput
+	}
+}
+
+// https://duckdb.org/docs/sql/statements/copy#json-options
+func NewWriteParams(params ...WriteParam) *WriteParams {
+	jsonWriteParams := &WriteParams{
+		format:            dfltWriteFormat,
+		compression:       dfltWriteCompression,
+		dateformat:        dfltWriteDateformat,
+		timestampformat:   dfltWriteTimestampformat,
+		nestedAsString:    dfltNestedAsString,
+		unflatten:         dfltUnflatten,
+		unflattenConfig:   NewUnflattenConfig(),
+		keepPartialOutput: dfltKeepPartialOutput,
+	}
+
+	for _, param := range params {
+		param(jsonWriteParams)
+	}
+
+	return jsonWriteParams
+}
+
+// Returns formatted parameters for writing JSON file
+func (p *WriteParams) Params() string {
+	params := []string{"FORMAT JSON"}
+
+	if p.format == Array {
+		params = append(params, "ARRAY true")
+	}
+
+	if p.compression != dfltWriteCompression {
+		params = append(params, fmt.Sprintf("COMPRESSION %s", param.QuoteLiteral(string(p.compression))))
+	}
+
+	if p.dateformat != dfltWriteDateformat {
+		params = append(params, fmt.Sprintf("DATEFORMAT %s", param.QuoteLiteral(p.dateformat)))
+	}
+
+	if p.timestampformat != dfltWriteTimestampformat {
+		params = append(params, fmt.Sprintf("TIMESTAMPFORMAT %s", param.QuoteLiteral(p.timestampformat)))
+	}
+
+	return fmt.Sprintf("(%s)", strings.Join(params, ","))
+}
+
+func (p *WriteParams) GetNestedAsString() bool {
+	return p.nestedAsString
+}
+
+func (p *WriteParams) GetUnflatten() bool {
+	return p.unflatten
+}
+
+func (p *WriteParams) GetUnflattenConfig() *UnflattenConfig {
+	return p.unflattenConfig
+}
+
+func (p *WriteParams) GetKeepPartialOutput() bool {
+	return p.keepPartialOutput
+}
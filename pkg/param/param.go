@@ -1,10 +1,5 @@
 package param
 
-import (
-	"fmt"
-	"strings"
-)
-
 type Compression string
 
 const (
@@ -26,19 +21,10 @@ func (c Columns) Format(quoteKeys bool) string {
 		return ""
 	}
 
-	cols := []string{}
-
+	fields := make([]StructField, 0, len(c))
 	for _, col := range c {
-		if quoteKeys {
-			cols = append(cols, fmt.Sprintf("'%s': '%s'", col.Name, col.Type))
-		} else {
-			cols = append(cols, fmt.Sprintf("%s: '%s'", col.Name, col.Type))
-		}
+		fields = append(fields, StructField{Key: col.Name, Value: col.Type})
 	}
 
-	var sb strings.Builder
-	sb.WriteString("{")
-	sb.WriteString(strings.Join(cols, ","))
-	sb.WriteString("}")
-	return sb.String()
+	return StructLiteral(fields, quoteKeys)
 }
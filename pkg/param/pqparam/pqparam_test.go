@@ -21,7 +21,7 @@ func TestWriteParams(t *testing.T) {
 					WithOverwriteOrIgnore(true),
 					WithPartitionBy("col1", "col2")),
 			},
-			expectedOutput: "(FORMAT PARQUET,PARTITION_BY (col1,col2),OVERWRITE_OR_IGNORE 1,FILENAME_PATTERN 'output_{uuid}')",
+			expectedOutput: `(FORMAT PARQUET,PARTITION_BY ("col1","col2"),OVERWRITE_OR_IGNORE 1,FILENAME_PATTERN 'output_{uuid}')`,
 		},
 		{
 			name: "TC3",
@@ -2,6 +2,8 @@ package pqparam
 
 import (
 	"strings"
+
+	"github.com/hbbtekademy/go-fileconv/pkg/param/transformparam"
 )
 
 type ReadParams struct {
@@ -10,6 +12,8 @@ type ReadParams struct {
 	fileRowNum     bool
 	unionByName    bool
 	hivePartition  bool
+	describe       bool
+	transform      *transformparam.TransformParams
 }
 
 type ReadParam func(*ReadParams)
@@ -20,6 +24,7 @@ const (
 	dfltFileRowNum     bool = false
 	dfltUnionByName    bool = false
 	dfltHivePartition  bool = false
+	dfltDescribe       bool = false
 )
 
 func WithBinaryAsString(binaryAsString bool) ReadParam {
@@ -52,6 +57,20 @@ func WithHivePartition(hivePartition bool) ReadParam {
 	}
 }
 
+func WithDescribe(describe bool) ReadParam {
+	return func(p *ReadParams) {
+		p.describe = describe
+	}
+}
+
+// Transform the rows read from the file before they are written.
+// See transformparam for the available transformations.
+func WithTransform(options ...transformparam.TransformParam) ReadParam {
+	return func(p *ReadParams) {
+		p.transform = transformparam.NewTransformParams(options...)
+	}
+}
+
 func NewReadParams(params ...ReadParam) *ReadParams {
 	pqParameters := &ReadParams{
 		binaryAsString: dfltBinaryAsString,
@@ -59,6 +78,8 @@ func NewReadParams(params ...ReadParam) *ReadParams {
 		fileRowNum:     dfltFileRowNum,
 		unionByName:    dfltUnionByName,
 		hivePartition:  dfltHivePartition,
+		describe:       dfltDescribe,
+		transform:      transformparam.NewTransformParams(),
 	}
 
 	for _, param := range params {
@@ -97,3 +118,11 @@ func (p *ReadParams) Params() string {
 	}
 	return prefix + strings.Join(params, ",")
 }
+
+func (p *ReadParams) GetDescribe() bool {
+	return p.describe
+}
+
+func (p *ReadParams) GetTransform() *transformparam.TransformParams {
+	return p.transform
+}
@@ -3,6 +3,8 @@ package pqparam
 import (
 	"fmt"
 	"strings"
+
+	"github.com/hbbtekademy/go-fileconv/pkg/param"
 )
 
 type Compression string
@@ -52,14 +54,16 @@ type WriteParams struct {
 	rowGroupSize        int64
 	hivePartitionConfig *hivePartitionConfig
 	perThreadOutput     bool
+	keepPartialOutput   bool
 }
 
 type WriteParam func(*WriteParams)
 
 const (
-	dfltCompression     Compression = "snappy"
-	dfltRowGroupSize    int64       = 122880
-	dfltPerThreadOutput bool        = false
+	dfltCompression       Compression = "snappy"
+	dfltRowGroupSize      int64       = 122880
+	dfltPerThreadOutput   bool        = false
+	dfltKeepPartialOutput bool        = false
 )
 
 func WithCompression(compression Compression) WriteParam {
@@ -94,11 +98,19 @@ func WithPerThreadOutput(perThreadOutput bool) WriteParam {
 	}
 }
 
+// Keep the temp output of a failed conversion for debugging instead of removing it
+func WithKeepPartialOutput(keepPartialOutput bool) WriteParam {
+	return func(p *WriteParams) {
+		p.keepPartialOutput = keepPartialOutput
+	}
+}
+
 func NewWriteParams(params ...WriteParam) *WriteParams {
 	pqParameters := &WriteParams{
-		compression:     dfltCompression,
-		rowGroupSize:    dfltRowGroupSize,
-		perThreadOutput: dfltPerThreadOutput,
+		compression:       dfltCompression,
+		rowGroupSize:      dfltRowGroupSize,
+		perThreadOutput:   dfltPerThreadOutput,
+		keepPartialOutput: dfltKeepPartialOutput,
 	}
 
 	p := WithHivePartitionConfig()
@@ -115,7 +127,7 @@ func (p *WriteParams) Params() string {
 	params := []string{"FORMAT PARQUET"}
 
 	if p.compression != dfltCompression {
-		params = append(params, fmt.Sprintf("COMPRESSION '%s'", p.compression))
+		params = append(params, fmt.Sprintf("COMPRESSION %s", param.QuoteLiteral(string(p.compression))))
 	}
 
 	if p.rowGroupSize != dfltRowGroupSize {
@@ -123,7 +135,7 @@ func (p *WriteParams) Params() string {
 	}
 
 	if len(p.hivePartitionConfig.partitionBy) > 0 {
-		params = append(params, fmt.Sprintf("PARTITION_BY (%s)", strings.Join(p.hivePartitionConfig.partitionBy, ",")))
+		params = append(params, fmt.Sprintf("PARTITION_BY (%s)", param.QuoteIdents(p.hivePartitionConfig.partitionBy)))
 	}
 
 	if p.perThreadOutput {
@@ -135,8 +147,23 @@ func (p *WriteParams) Params() string {
 	}
 
 	if p.hivePartitionConfig.filenamePattern != dfltFilenamePattern {
-		params = append(params, fmt.Sprintf("FILENAME_PATTERN '%s'", p.hivePartitionConfig.filenamePattern))
+		params = append(params, fmt.Sprintf("FILENAME_PATTERN %s", param.QuoteLiteral(p.hivePartitionConfig.filenamePattern)))
 	}
 
 	return fmt.Sprintf("(%s)", strings.Join(params, ","))
 }
+
+// Returns true if the parquet output is written to multiple files in a directory
+// i.e. the output is hive partitioned or written per thread
+func (p *WriteParams) IsMultiFileOutput() bool {
+	return len(p.hivePartitionConfig.partitionBy) > 0 || p.perThreadOutput
+}
+
+// Returns true if files may be written into an existing non empty output directory
+func (p *WriteParams) GetOverwriteOrIgnore() bool {
+	return p.hivePartitionConfig.overwriteOrIgnore != 0
+}
+
+func (p *WriteParams) GetKeepPartialOutput() bool {
+	return p.keepPartialOutput
+}
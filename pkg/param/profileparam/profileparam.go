@@ -0,0 +1,35 @@
+package profileparam
+
+// Parameters for profiling the columns of a source
+type ProfileParams struct {
+	topK int
+}
+
+type ProfileParam func(*ProfileParams)
+
+const (
+	dfltTopK int = 5
+)
+
+// Number of most frequent values reported for each column. Default 5. No values are reported if 0.
+func WithTopK(topK int) ProfileParam {
+	return func(pp *ProfileParams) {
+		pp.topK = topK
+	}
+}
+
+func NewProfileParams(params ...ProfileParam) *ProfileParams {
+	profileParams := &ProfileParams{
+		topK: dfltTopK,
+	}
+
+	for _, param := range params {
+		param(profileParams)
+	}
+
+	return profileParams
+}
+
+func (p *ProfileParams) GetTopK() int {
+	return p.topK
+}
@@ -0,0 +1,34 @@
+package profileparam
+
+import (
+	"testing"
+)
+
+func TestProfileParams(t *testing.T) {
+	tests := []struct {
+		name         string
+		params       []ProfileParam
+		expectedTopK int
+	}{
+		{
+			name:         "TC1",
+			params:       []ProfileParam{},
+			expectedTopK: 5,
+		},
+		{
+			name:         "TC2",
+			params:       []ProfileParam{WithTopK(0)},
+			expectedTopK: 0,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			params := NewProfileParams(tc.params...)
+
+			if params.GetTopK() != tc.expectedTopK {
+				t.Fatalf("expected top k: %d but got: %d", tc.expectedTopK, params.GetTopK())
+			}
+		})
+	}
+}
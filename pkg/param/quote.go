@@ -0,0 +1,55 @@
+package param
+
+import "strings"
+
+// Returns s as a SQL string literal. Single quotes within s are escaped by doubling them.
+//
+//	QuoteLiteral("o'brien.csv") // 'o''brien.csv'
+func QuoteLiteral(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// Returns s as a SQL quoted identifier. Double quotes within s are escaped by doubling them.
+//
+//	QuoteIdent(`a "b"`) // "a ""b"""
+func QuoteIdent(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, `""`) + `"`
+}
+
+// Returns a comma separated list of the quoted identifiers
+func QuoteIdents(idents []string) string {
+	quoted := make([]string, 0, len(idents))
+	for _, ident := range idents {
+		quoted = append(quoted, QuoteIdent(ident))
+	}
+	return strings.Join(quoted, ",")
+}
+
+// Returns a SQL list literal of the string values, e.g. ['a','b']
+func ListLiteral(values []string) string {
+	quoted := make([]string, 0, len(values))
+	for _, value := range values {
+		quoted = append(quoted, QuoteLiteral(value))
+	}
+	return "[" + strings.Join(quoted, ",") + "]"
+}
+
+// Field of a SQL struct literal
+type StructField struct {
+	Key   string
+	Value string
+}
+
+// Returns a SQL struct literal of the string values, e.g. {'a': 'x','b': 'y'}.
+// If quoteKeys is true the keys are string literals, otherwise they are quoted identifiers.
+func StructLiteral(fields []StructField, quoteKeys bool) string {
+	quoted := make([]string, 0, len(fields))
+	for _, field := range fields {
+		key := QuoteIdent(field.Key)
+		if quoteKeys {
+			key = QuoteLiteral(field.Key)
+		}
+		quoted = append(quoted, key+": "+QuoteLiteral(field.Value))
+	}
+	return "{" + strings.Join(quoted, ",") + "}"
+}
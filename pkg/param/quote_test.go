@@ -0,0 +1,77 @@
+package param
+
+import "testing"
+
+func TestQuoteLiteral(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{name: "TC1", input: "data.csv", expected: `'data.csv'`},
+		{name: "TC2", input: "o'brien.csv", expected: `'o''brien.csv'`},
+		{name: "TC3", input: "x'); DROP TABLE t; --", expected: `'x''); DROP TABLE t; --'`},
+		{name: "TC4", input: "", expected: `''`},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			actual := QuoteLiteral(tc.input)
+			if actual != tc.expected {
+				t.Fatalf("expected: %s but got: %s", tc.expected, actual)
+			}
+		})
+	}
+}
+
+func TestQuoteIdent(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{name: "TC1", input: "col1", expected: `"col1"`},
+		{name: "TC2", input: "select", expected: `"select"`},
+		{name: "TC3", input: `a "b"`, expected: `"a ""b"""`},
+		{name: "TC4", input: `x" FROM t; --`, expected: `"x"" FROM t; --"`},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			actual := QuoteIdent(tc.input)
+			if actual != tc.expected {
+				t.Fatalf("expected: %s but got: %s", tc.expected, actual)
+			}
+		})
+	}
+}
+
+func TestListAndStructLiterals(t *testing.T) {
+	tests := []struct {
+		name     string
+		actual   string
+		expected string
+	}{
+		{name: "TC1", actual: ListLiteral([]string{"a", "b'c"}), expected: `['a','b''c']`},
+		{name: "TC2", actual: ListLiteral([]string{}), expected: `[]`},
+		{name: "TC3", actual: QuoteIdents([]string{"a", `b"c`}), expected: `"a","b""c"`},
+		{
+			name:     "TC4",
+			actual:   StructLiteral([]StructField{{Key: "o'brien", Value: "VARCHAR"}, {Key: "b", Value: "INT"}}, true),
+			expected: `{'o''brien': 'VARCHAR','b': 'INT'}`,
+		},
+		{
+			name:     "TC5",
+			actual:   StructLiteral([]StructField{{Key: `a"b`, Value: "VARCHAR"}}, false),
+			expected: `{"a""b": 'VARCHAR'}`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.actual != tc.expected {
+				t.Fatalf("expected: %s but got: %s", tc.expected, tc.actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,220 @@
+package transformparam
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hbbtekademy/go-fileconv/pkg/model"
+	"github.com/hbbtekademy/go-fileconv/pkg/param"
+	"gopkg.in/yaml.v3"
+)
+
+// Mapping of the source columns to the output columns.
+// Source columns without a column mapping are written unchanged.
+//
+//	columns:
+//	  - name: Sepal Length
+//	    rename: sepal_length
+//	    cast: DECIMAL(4,1)
+//	    default: 0
+//	computed:
+//	  - name: petal_ratio
+//	    expr: '"Petal Length" / "Petal Width"'
+//	    cast: DOUBLE
+type Mapping struct {
+	Columns  []ColumnMapping  `json:"columns,omitempty" yaml:"columns,omitempty"`
+	Computed []ComputedColumn `json:"computed,omitempty" yaml:"computed,omitempty"`
+}
+
+// Mapping of a source column
+type ColumnMapping struct {
+	// Name of the source column
+	Name string `json:"name" yaml:"name"`
+	// Name of the output column. Defaults to the source column name.
+	Rename string `json:"rename,omitempty" yaml:"rename,omitempty"`
+	// DuckDB type the column is cast to, e.g. BIGINT or DECIMAL(10,2)
+	Cast string `json:"cast,omitempty" yaml:"cast,omitempty"`
+	// Value written when the column is NULL. Must be a string, number or bool.
+	Default any `json:"default,omitempty" yaml:"default,omitempty"`
+}
+
+// Output column computed from a SQL expression on the source columns
+type ComputedColumn struct {
+	// Name of the output column
+	Name string `json:"name" yaml:"name"`
+	// SQL expression computing the column, e.g. price * quantity
+	Expr string `json:"expr" yaml:"expr"`
+	// DuckDB type the computed value is cast to
+	Cast string `json:"cast,omitempty" yaml:"cast,omitempty"`
+}
+
+// Loads the mapping from a JSON file if the file has a .json extension, otherwise from a YAML file.
+// Unknown keys in the file are rejected.
+func LoadMapping(path string) (*Mapping, error) {
+	mapping := &Mapping{}
+	err := loadSpec(path, "mapping", mapping)
+	if err != nil {
+		return nil, err
+	}
+
+	return mapping, nil
+}
+
+// Decodes the JSON file if it has a .json extension, otherwise the YAML file, into spec rejecting unknown keys
+func loadSpec(path string, kind string, spec any) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		err = decoder.Decode(spec)
+	} else {
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		err = decoder.Decode(spec)
+	}
+	if err != nil {
+		return fmt.Errorf("failed parsing %s file: %s. error: %w", kind, path, err)
+	}
+
+	return nil
+}
+
+// Validates that the mapped columns exist in the source table and that the output column names are unique
+func (m *Mapping) Validate(tableDesc *model.TableDesc) error {
+	mapped := map[string]bool{}
+	for _, col := range m.Columns {
+		if err := validateColumn(tableDesc, col.Name, "mapping"); err != nil {
+			return err
+		}
+		if mapped[strings.ToLower(col.Name)] {
+			return fmt.Errorf("column %q is mapped more than once", col.Name)
+		}
+		mapped[strings.ToLower(col.Name)] = true
+
+		if _, err := defaultLiteral(col.Default); err != nil {
+			return fmt.Errorf("invalid default for column %q. error: %w", col.Name, err)
+		}
+		if err := validateCast(col.Cast); err != nil {
+			return fmt.Errorf("invalid cast of column %q. error: %w", col.Name, err)
+		}
+	}
+
+	for _, col := range m.Computed {
+		if col.Name == "" || col.Expr == "" {
+			return fmt.Errorf("computed column %q must have a name and an expr", col.Name)
+		}
+		if err := validateCast(col.Cast); err != nil {
+			return fmt.Errorf("invalid cast of computed column %q. error: %w", col.Name, err)
+		}
+	}
+
+	outputCols := map[string]bool{}
+	for _, col := range m.outputColumns(tableDesc) {
+		if outputCols[strings.ToLower(col)] {
+			return fmt.Errorf("duplicate output column %q", col)
+		}
+		outputCols[strings.ToLower(col)] = true
+	}
+
+	return nil
+}
+
+// Returns the select on the source query with the mapping applied.
+// The columns are written in the order of the source table followed by the computed columns.
+func (m *Mapping) Query(query string, tableDesc *model.TableDesc) string {
+	cols := make([]string, 0, len(tableDesc.ColumnDescs)+len(m.Computed))
+
+	for _, colDesc := range tableDesc.ColumnDescs {
+		col := m.column(colDesc.ColName)
+		if col == nil {
+			cols = append(cols, param.QuoteIdent(colDesc.ColName))
+			continue
+		}
+
+		expr := param.QuoteIdent(colDesc.ColName)
+		literal, _ := defaultLiteral(col.Default)
+		if col.Cast != "" {
+			expr = fmt.Sprintf("CAST(%s AS %s)", expr, col.Cast)
+			// the default is cast as well, so that it does not widen the column type
+			if literal != "" {
+				literal = fmt.Sprintf("CAST(%s AS %s)", literal, col.Cast)
+			}
+		}
+		if literal != "" {
+			expr = fmt.Sprintf("COALESCE(%s, %s)", expr, literal)
+		}
+		cols = append(cols, fmt.Sprintf("%s AS %s", expr, param.QuoteIdent(col.outputName())))
+	}
+
+	for _, col := range m.Computed {
+		expr := fmt.Sprintf("(%s)", col.Expr)
+		if col.Cast != "" {
+			expr = fmt.Sprintf("CAST(%s AS %s)", expr, col.Cast)
+		}
+		cols = append(cols, fmt.Sprintf("%s AS %s", expr, param.QuoteIdent(col.Name)))
+	}
+
+	return fmt.Sprintf("SELECT %s FROM (%s)", strings.Join(cols, ","), query)
+}
+
+// Returns the mapping of the source column, or nil if the column is not mapped
+func (m *Mapping) column(name string) *ColumnMapping {
+	for i := range m.Columns {
+		if strings.EqualFold(m.Columns[i].Name, name) {
+			return &m.Columns[i]
+		}
+	}
+	return nil
+}
+
+func (m *Mapping) outputColumns(tableDesc *model.TableDesc) []string {
+	cols := make([]string, 0, len(tableDesc.ColumnDescs)+len(m.Computed))
+	for _, colDesc := range tableDesc.ColumnDescs {
+		if col := m.column(colDesc.ColName); col != nil {
+			cols = append(cols, col.outputName())
+		} else {
+			cols = append(cols, colDesc.ColName)
+		}
+	}
+	for _, col := range m.Computed {
+		cols = append(cols, col.Name)
+	}
+	return cols
+}
+
+func (c *ColumnMapping) outputName() string {
+	if c.Rename != "" {
+		return c.Rename
+	}
+	return c.Name
+}
+
+// Validates that the cast is a DuckDB type, since it is written into the query as is
+func validateCast(cast string) error {
+	if cast == "" {
+		return nil
+	}
+	return model.ValidateType(cast)
+}
+
+// Returns the default value as a SQL literal, or an empty string if there is no default
+func defaultLiteral(value any) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return param.QuoteLiteral(v), nil
+	case bool, int, int64, uint64, float64:
+		return fmt.Sprint(v), nil
+	default:
+		return "", fmt.Errorf("unsupported default value: %v. must be a string, number or bool", v)
+	}
+}
@@ -0,0 +1,198 @@
+package transformparam
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hbbtekademy/go-fileconv/pkg/model"
+)
+
+func TestLoadMapping(t *testing.T) {
+	tests := []struct {
+		name            string
+		mappingFile     string
+		expectedMapping *Mapping
+	}{
+		{
+			name:        "TC1",
+			mappingFile: "../../../testdata/mapping/iris_mapping.yaml",
+			expectedMapping: &Mapping{
+				Columns: []ColumnMapping{
+					{Name: "species", Rename: "iris_species", Default: "unknown"},
+					{Name: "sepal_length", Cast: "DECIMAL(4,1)", Default: 0},
+				},
+				Computed: []ComputedColumn{
+					{Name: "petal_ratio", Expr: "petal_length / petal_width", Cast: "DOUBLE"},
+				},
+			},
+		},
+		{
+			name:        "TC2",
+			mappingFile: "../../../testdata/mapping/iris_mapping.json",
+			expectedMapping: &Mapping{
+				Columns: []ColumnMapping{
+					{Name: "species", Rename: "iris_species", Default: "unknown"},
+					{Name: "sepal_length", Cast: "DECIMAL(4,1)", Default: float64(0)},
+				},
+				Computed: []ComputedColumn{
+					{Name: "petal_ratio", Expr: "petal_length / petal_width", Cast: "DOUBLE"},
+				},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mapping, err := LoadMapping(tc.mappingFile)
+			if err != nil {
+				t.Fatalf("failed loading mapping. error: %v", err)
+			}
+
+			if !reflect.DeepEqual(tc.expectedMapping, mapping) {
+				t.Fatalf("expected: %+v but got: %+v", tc.expectedMapping, mapping)
+			}
+		})
+	}
+}
+
+func TestMappingQuery(t *testing.T) {
+	tableDesc := &model.TableDesc{
+		ColumnDescs: []*model.ColumnDesc{
+			{ColName: "Order ID", ColType: "BIGINT"},
+			{ColName: "price", ColType: "VARCHAR"},
+			{ColName: "qty", ColType: "BIGINT"},
+		},
+	}
+
+	tests := []struct {
+		name           string
+		mapping        *Mapping
+		expectedOutput string
+	}{
+		{
+			name:           "TC1",
+			mapping:        &Mapping{},
+			expectedOutput: `SELECT "Order ID","price","qty" FROM (SELECT * FROM t)`,
+		},
+		{
+			name: "TC2",
+			mapping: &Mapping{
+				Columns: []ColumnMapping{
+					{Name: "order id", Rename: "order_id"},
+					{Name: "price", Cast: "DECIMAL(10,2)", Default: 0},
+					{Name: "qty", Default: "n/a's"},
+				},
+				Computed: []ComputedColumn{
+					{Name: "total", Expr: "price * qty", Cast: "DOUBLE"},
+				},
+			},
+			expectedOutput: `SELECT "Order ID" AS "order_id",COALESCE(CAST("price" AS DECIMAL(10,2)), CAST(0 AS DECIMAL(10,2))) AS "price",COALESCE("qty", 'n/a''s') AS "qty",CAST((price * qty) AS DOUBLE) AS "total" FROM (SELECT * FROM t)`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			actualOutput := tc.mapping.Query("SELECT * FROM t", tableDesc)
+
+			if actualOutput != tc.expectedOutput {
+				t.Fatalf("expected:\n%s\nbut got:\n%s", tc.expectedOutput, actualOutput)
+			}
+		})
+	}
+}
+
+func TestMappingValidate(t *testing.T) {
+	tableDesc := &model.TableDesc{
+		ColumnDescs: []*model.ColumnDesc{
+			{ColName: "price", ColType: "VARCHAR"},
+			{ColName: "qty", ColType: "BIGINT"},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		mapping     *Mapping
+		expectedErr string
+	}{
+		{
+			name: "TC1",
+			mapping: &Mapping{
+				Columns: []ColumnMapping{
+					{Name: "Price", Rename: "unit_price"},
+				},
+				Computed: []ComputedColumn{
+					{Name: "price", Expr: "price * qty"},
+				},
+			},
+			expectedErr: "",
+		},
+		{
+			name: "TC2",
+			mapping: &Mapping{
+				Columns: []ColumnMapping{
+					{Name: "prcie", Cast: "DOUBLE"},
+				},
+			},
+			expectedErr: `unknown column "prcie" in mapping. did you mean "price"? available columns: price, qty`,
+		},
+		{
+			name: "TC3",
+			mapping: &Mapping{
+				Columns: []ColumnMapping{
+					{Name: "price", Rename: "qty"},
+				},
+			},
+			expectedErr: `duplicate output column "qty"`,
+		},
+		{
+			name: "TC4",
+			mapping: &Mapping{
+				Columns: []ColumnMapping{
+					{Name: "price", Default: []string{"0"}},
+				},
+			},
+			expectedErr: `invalid default for column "price". error: unsupported default value: [0]. must be a string, number or bool`,
+		},
+		{
+			name: "TC5",
+			mapping: &Mapping{
+				Computed: []ComputedColumn{
+					{Name: "total"},
+				},
+			},
+			expectedErr: `computed column "total" must have a name and an expr`,
+		},
+		{
+			name: "TC6",
+			mapping: &Mapping{
+				Columns: []ColumnMapping{
+					{Name: "price", Cast: "DOUBLE) AS price, current_setting('home_directory') AS home, CAST(1 AS INT"},
+				},
+			},
+			expectedErr: `invalid cast of column "price". error: invalid type: DOUBLE) AS price, current_setting('home_directory') AS home, CAST(1 AS INT. error: unexpected ") AS price, current_setting('home_directory') AS home, CAST(1 AS INT" at 6`,
+		},
+		{
+			name: "TC7",
+			mapping: &Mapping{
+				Computed: []ComputedColumn{
+					{Name: "total", Expr: "price * qty", Cast: "DOUBEL"},
+				},
+			},
+			expectedErr: `invalid cast of computed column "total". error: invalid type: DOUBEL. error: unknown type: DOUBEL`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.mapping.Validate(tableDesc)
+
+			actualErr := ""
+			if err != nil {
+				actualErr = err.Error()
+			}
+			if actualErr != tc.expectedErr {
+				t.Fatalf("expected error: %s but got: %s", tc.expectedErr, actualErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,226 @@
+package transformparam
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hbbtekademy/go-fileconv/pkg/model"
+	"github.com/hbbtekademy/go-fileconv/pkg/param"
+)
+
+type Check string
+
+const (
+	NotNull       Check = "not_null"
+	Unique        Check = "unique"
+	AllowedValues Check = "allowed_values"
+	Regex         Check = "regex"
+	Range         Check = "range"
+	RowCount      Check = "row_count"
+)
+
+type Severity string
+
+const (
+	// A failed rule fails the conversion before any rows are written
+	SeverityError Severity = "error"
+	// A failed rule is reported and the rows are written
+	SeverityWarn Severity = "warn"
+)
+
+// Data quality rules checked on the rows to write, after the other transformations are applied.
+//
+//	rules:
+//	  - check: not_null
+//	    column: species
+//	  - check: allowed_values
+//	    column: species
+//	    values: [Iris-setosa, Iris-versicolor, Iris-virginica]
+//	  - check: range
+//	    column: sepal_length
+//	    min: 0
+//	    max: 10
+//	    severity: warn
+//	  - check: row_count
+//	    min: 1
+type Rules struct {
+	Rules []Rule `json:"rules" yaml:"rules"`
+}
+
+type Rule struct {
+	// Name of the rule in the report. Defaults to the check and the column, e.g. not_null(species)
+	Name string `json:"name,omitempty" yaml:"name,omitempty"`
+	// One of not_null, unique, allowed_values, regex, range, row_count
+	Check Check `json:"check" yaml:"check"`
+	// Column checked by the rule. Not used by row_count.
+	Column string `json:"column,omitempty" yaml:"column,omitempty"`
+	// Allowed values of the column for allowed_values. Must be strings, numbers or bools.
+	Values []any `json:"values,omitempty" yaml:"values,omitempty"`
+	// Regular expression the whole column value must match for regex
+	Pattern string `json:"pattern,omitempty" yaml:"pattern,omitempty"`
+	// Inclusive bounds of the column value for range, or of the number of rows for row_count
+	Min *float64 `json:"min,omitempty" yaml:"min,omitempty"`
+	Max *float64 `json:"max,omitempty" yaml:"max,omitempty"`
+	// error or warn. Defaults to error.
+	Severity Severity `json:"severity,omitempty" yaml:"severity,omitempty"`
+}
+
+// Loads the rules from a JSON file if the file has a .json extension, otherwise from a YAML file.
+// Unknown keys in the file are rejected.
+func LoadRules(path string) (*Rules, error) {
+	rules := &Rules{}
+	err := loadSpec(path, "rules", rules)
+	if err != nil {
+		return nil, err
+	}
+
+	return rules, nil
+}
+
+// Validates the rules and that the checked columns exist in the table
+func (r *Rules) Validate(tableDesc *model.TableDesc) error {
+	names := map[string]bool{}
+	for i := range r.Rules {
+		rule := &r.Rules[i]
+		if err := rule.validate(tableDesc); err != nil {
+			return fmt.Errorf("invalid rule %q. error: %w", rule.GetName(), err)
+		}
+
+		if names[rule.GetName()] {
+			return fmt.Errorf("duplicate rule name %q", rule.GetName())
+		}
+		names[rule.GetName()] = true
+	}
+
+	return nil
+}
+
+// Returns the query counting the rows of table failing the rule, or the number of rows of table for row_count
+func (r *Rule) Query(table string) string {
+	return fmt.Sprintf("SELECT %s FROM %s", r.failedExpr(), param.QuoteIdent(table))
+}
+
+func (r *Rule) GetName() string {
+	if r.Name != "" {
+		return r.Name
+	}
+	if r.Check == RowCount {
+		return string(r.Check)
+	}
+	return fmt.Sprintf("%s(%s)", r.Check, r.Column)
+}
+
+func (r *Rule) GetSeverity() Severity {
+	if r.Severity == "" {
+		return SeverityError
+	}
+	return r.Severity
+}
+
+// Returns true if the rule passes given the value selected by its query
+func (r *Rule) Passed(value int64) bool {
+	if r.Check != RowCount {
+		return value == 0
+	}
+
+	return (r.Min == nil || float64(value) >= *r.Min) && (r.Max == nil || float64(value) <= *r.Max)
+}
+
+func (r *Rule) validate(tableDesc *model.TableDesc) error {
+	switch r.GetSeverity() {
+	case SeverityError, SeverityWarn:
+	default:
+		return fmt.Errorf("unknown severity: %s. must be one of error, warn", r.Severity)
+	}
+
+	switch r.Check {
+	case NotNull, Unique, AllowedValues, Regex, Range:
+		if r.Column == "" {
+			return fmt.Errorf("%s must have a column", r.Check)
+		}
+		if err := validateColumn(tableDesc, r.Column, "rule"); err != nil {
+			return err
+		}
+	case RowCount:
+		if r.Column != "" {
+			return fmt.Errorf("%s does not check a column", r.Check)
+		}
+	default:
+		return fmt.Errorf("unknown check: %s. must be one of %s, %s, %s, %s, %s, %s",
+			r.Check, NotNull, Unique, AllowedValues, Regex, Range, RowCount)
+	}
+
+	switch r.Check {
+	case AllowedValues:
+		if len(r.Values) == 0 {
+			return fmt.Errorf("%s must have values", r.Check)
+		}
+		for _, value := range r.Values {
+			if _, err := valueLiteral(value); err != nil {
+				return err
+			}
+		}
+	case Regex:
+		if r.Pattern == "" {
+			return fmt.Errorf("%s must have a pattern", r.Check)
+		}
+	case Range, RowCount:
+		return r.validateBounds()
+	}
+
+	return nil
+}
+
+func (r *Rule) validateBounds() error {
+	if r.Min == nil && r.Max == nil {
+		return fmt.Errorf("%s must have a min or a max", r.Check)
+	}
+	if r.Min != nil && r.Max != nil && *r.Min > *r.Max {
+		return fmt.Errorf("min: %v is greater than max: %v", *r.Min, *r.Max)
+	}
+	return nil
+}
+
+// Returns the aggregate expression counting the rows failing the rule
+func (r *Rule) failedExpr() string {
+	col := param.QuoteIdent(r.Column)
+
+	switch r.Check {
+	case NotNull:
+		return fmt.Sprintf("count(*) FILTER (WHERE %s IS NULL)", col)
+	case Unique:
+		return fmt.Sprintf("count(%s) - count(DISTINCT %s)", col, col)
+	case AllowedValues:
+		values := make([]string, 0, len(r.Values))
+		for _, value := range r.Values {
+			literal, _ := valueLiteral(value)
+			values = append(values, literal)
+		}
+		return fmt.Sprintf("count(*) FILTER (WHERE %s NOT IN (%s))", col, strings.Join(values, ","))
+	case Regex:
+		return fmt.Sprintf("count(*) FILTER (WHERE NOT regexp_full_match(CAST(%s AS VARCHAR), %s))", col, param.QuoteLiteral(r.Pattern))
+	case Range:
+		conds := []string{}
+		if r.Min != nil {
+			conds = append(conds, fmt.Sprintf("%s < %v", col, *r.Min))
+		}
+		if r.Max != nil {
+			conds = append(conds, fmt.Sprintf("%s > %v", col, *r.Max))
+		}
+		return fmt.Sprintf("count(*) FILTER (WHERE %s)", strings.Join(conds, " OR "))
+	default:
+		return "count(*)"
+	}
+}
+
+// Returns the value as a SQL literal
+func valueLiteral(value any) (string, error) {
+	switch v := value.(type) {
+	case string:
+		return param.QuoteLiteral(v), nil
+	case bool, int, int64, uint64, float64:
+		return fmt.Sprint(v), nil
+	default:
+		return "", fmt.Errorf("unsupported value: %v. must be a string, number or bool", v)
+	}
+}
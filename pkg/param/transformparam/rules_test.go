@@ -0,0 +1,193 @@
+package transformparam
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hbbtekademy/go-fileconv/pkg/model"
+)
+
+func TestLoadRules(t *testing.T) {
+	zero, one, ten := float64(0), float64(1), float64(10)
+	expectedRules := &Rules{
+		Rules: []Rule{
+			{Check: NotNull, Column: "species"},
+			{Name: "known_species", Check: AllowedValues, Column: "species",
+				Values: []any{"Iris-setosa", "Iris-versicolor", "Iris-virginica"}},
+			{Check: Range, Column: "sepal_length", Min: &zero, Max: &ten, Severity: SeverityWarn},
+			{Check: RowCount, Min: &one},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		rulesFile string
+	}{
+		{
+			name:      "TC1",
+			rulesFile: "../../../testdata/rules/iris_rules.yaml",
+		},
+		{
+			name:      "TC2",
+			rulesFile: "../../../testdata/rules/iris_rules.json",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			rules, err := LoadRules(tc.rulesFile)
+			if err != nil {
+				t.Fatalf("failed loading rules. error: %v", err)
+			}
+
+			if !reflect.DeepEqual(expectedRules, rules) {
+				t.Fatalf("expected: %+v but got: %+v", expectedRules, rules)
+			}
+		})
+	}
+}
+
+func TestRuleQuery(t *testing.T) {
+	zero, ten := float64(0), float64(10)
+
+	tests := []struct {
+		name           string
+		rule           Rule
+		expectedOutput string
+	}{
+		{
+			name:           "TC1",
+			rule:           Rule{Check: NotNull, Column: "Order ID"},
+			expectedOutput: `SELECT count(*) FILTER (WHERE "Order ID" IS NULL) FROM "stage"`,
+		},
+		{
+			name:           "TC2",
+			rule:           Rule{Check: Unique, Column: "id"},
+			expectedOutput: `SELECT count("id") - count(DISTINCT "id") FROM "stage"`,
+		},
+		{
+			name:           "TC3",
+			rule:           Rule{Check: AllowedValues, Column: "status", Values: []any{"new", "it's done", 1, true}},
+			expectedOutput: `SELECT count(*) FILTER (WHERE "status" NOT IN ('new','it''s done',1,true)) FROM "stage"`,
+		},
+		{
+			name:           "TC4",
+			rule:           Rule{Check: Regex, Column: "email", Pattern: `[^@]+@[^@]+`},
+			expectedOutput: `SELECT count(*) FILTER (WHERE NOT regexp_full_match(CAST("email" AS VARCHAR), '[^@]+@[^@]+')) FROM "stage"`,
+		},
+		{
+			name:           "TC5",
+			rule:           Rule{Check: Range, Column: "price", Min: &zero, Max: &ten},
+			expectedOutput: `SELECT count(*) FILTER (WHERE "price" < 0 OR "price" > 10) FROM "stage"`,
+		},
+		{
+			name:           "TC6",
+			rule:           Rule{Check: RowCount, Min: &ten},
+			expectedOutput: `SELECT count(*) FROM "stage"`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			actualOutput := tc.rule.Query("stage")
+
+			if actualOutput != tc.expectedOutput {
+				t.Fatalf("expected:\n%s\nbut got:\n%s", tc.expectedOutput, actualOutput)
+			}
+		})
+	}
+}
+
+func TestRuleResult(t *testing.T) {
+	one, ten := float64(1), float64(10)
+
+	tests := []struct {
+		name     string
+		rule     Rule
+		value    int64
+		expected bool
+	}{
+		{name: "TC1", rule: Rule{Check: NotNull, Column: "id"}, value: 0, expected: true},
+		{name: "TC2", rule: Rule{Check: NotNull, Column: "id"}, value: 3, expected: false},
+		{name: "TC3", rule: Rule{Check: RowCount, Min: &one, Max: &ten}, value: 10, expected: true},
+		{name: "TC4", rule: Rule{Check: RowCount, Min: &one}, value: 0, expected: false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if actual := tc.rule.Passed(tc.value); actual != tc.expected {
+				t.Fatalf("expected passed: %t for value: %d but got: %t", tc.expected, tc.value, actual)
+			}
+		})
+	}
+}
+
+func TestRulesValidate(t *testing.T) {
+	tableDesc := &model.TableDesc{
+		ColumnDescs: []*model.ColumnDesc{
+			{ColName: "price", ColType: "DOUBLE"},
+			{ColName: "qty", ColType: "BIGINT"},
+		},
+	}
+	one, ten := float64(1), float64(10)
+
+	tests := []struct {
+		name        string
+		rules       *Rules
+		expectedErr string
+	}{
+		{
+			name: "TC1",
+			rules: &Rules{Rules: []Rule{
+				{Check: NotNull, Column: "Price"},
+				{Check: Range, Column: "qty", Min: &one, Severity: SeverityWarn},
+				{Check: RowCount, Max: &ten},
+			}},
+			expectedErr: "",
+		},
+		{
+			name:        "TC2",
+			rules:       &Rules{Rules: []Rule{{Check: Unique, Column: "prcie"}}},
+			expectedErr: `invalid rule "unique(prcie)". error: unknown column "prcie" in rule. did you mean "price"? available columns: price, qty`,
+		},
+		{
+			name:        "TC3",
+			rules:       &Rules{Rules: []Rule{{Check: "between", Column: "price"}}},
+			expectedErr: `invalid rule "between(price)". error: unknown check: between. must be one of not_null, unique, allowed_values, regex, range, row_count`,
+		},
+		{
+			name:        "TC4",
+			rules:       &Rules{Rules: []Rule{{Check: Range, Column: "price", Min: &ten, Max: &one}}},
+			expectedErr: `invalid rule "range(price)". error: min: 10 is greater than max: 1`,
+		},
+		{
+			name:        "TC5",
+			rules:       &Rules{Rules: []Rule{{Check: AllowedValues, Column: "qty", Values: []any{[]int{1}}}}},
+			expectedErr: `invalid rule "allowed_values(qty)". error: unsupported value: [1]. must be a string, number or bool`,
+		},
+		{
+			name:        "TC6",
+			rules:       &Rules{Rules: []Rule{{Check: NotNull, Column: "qty", Severity: "fatal"}}},
+			expectedErr: `invalid rule "not_null(qty)". error: unknown severity: fatal. must be one of error, warn`,
+		},
+		{
+			name:        "TC7",
+			rules:       &Rules{Rules: []Rule{{Check: NotNull, Column: "qty"}, {Check: NotNull, Column: "qty"}}},
+			expectedErr: `duplicate rule name "not_null(qty)"`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.rules.Validate(tableDesc)
+
+			actualErr := ""
+			if err != nil {
+				actualErr = err.Error()
+			}
+			if actualErr != tc.expectedErr {
+				t.Fatalf("expected error: %s but got: %s", tc.expectedErr, actualErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,270 @@
+package transformparam
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hbbtekademy/go-fileconv/pkg/model"
+	"github.com/hbbtekademy/go-fileconv/pkg/param"
+)
+
+// Parameters for transforming the rows read from the source before they are written
+type TransformParams struct {
+	selectCols  []string
+	exclude     []string
+	where       string
+	limit       int64
+	mapping     *Mapping
+	rules       *Rules
+	rulesReport string
+}
+
+type TransformParam func(*TransformParams)
+
+const (
+	dfltWhere string = ""
+	dfltLimit int64  = 0
+)
+
+// Columns to write, in the given order. All columns are written by default.
+func WithSelect(cols ...string) TransformParam {
+	return func(tp *TransformParams) {
+		tp.selectCols = cols
+	}
+}
+
+// Columns not to write
+func WithExclude(cols ...string) TransformParam {
+	return func(tp *TransformParams) {
+		tp.exclude = cols
+	}
+}
+
+// SQL boolean expression filtering the rows to write, e.g. species = 'setosa' AND petal_length > 1.5
+func WithWhere(where string) TransformParam {
+	return func(tp *TransformParams) {
+		tp.where = where
+	}
+}
+
+// Maximum number of rows to write. All rows are written if limit is 0.
+func WithLimit(limit int64) TransformParam {
+	return func(tp *TransformParams) {
+		tp.limit = limit
+	}
+}
+
+// Rename, cast and compute the output columns. The other transformations apply to the mapped columns.
+func WithMapping(mapping *Mapping) TransformParam {
+	return func(tp *TransformParams) {
+		tp.mapping = mapping
+	}
+}
+
+// Data quality rules checked on the transformed rows before any rows are written.
+// The conversion fails without writing any rows if a rule with severity error fails.
+func WithRules(rules *Rules) TransformParam {
+	return func(tp *TransformParams) {
+		tp.rules = rules
+	}
+}
+
+// Path of the json report of the data quality rules. No report is written by default.
+func WithRulesReport(path string) TransformParam {
+	return func(tp *TransformParams) {
+		tp.rulesReport = path
+	}
+}
+
+func NewTransformParams(params ...TransformParam) *TransformParams {
+	transformParams := &TransformParams{
+		selectCols: []string{},
+		exclude:    []string{},
+		where:      dfltWhere,
+		limit:      dfltLimit,
+	}
+
+	for _, param := range params {
+		param(transformParams)
+	}
+
+	return transformParams
+}
+
+// Returns true if no transformation is applied to the source rows
+func (p *TransformParams) IsEmpty() bool {
+	return p.mapping == nil && !p.filtersRows()
+}
+
+// Returns true if columns or rows of the source are selected, excluded, filtered or limited
+func (p *TransformParams) filtersRows() bool {
+	return len(p.selectCols) > 0 || len(p.exclude) > 0 || p.where != dfltWhere || p.limit != dfltLimit
+}
+
+// Validates that the selected and excluded columns exist in the source table
+func (p *TransformParams) Validate(tableDesc *model.TableDesc) error {
+	if p.limit < 0 {
+		return fmt.Errorf("invalid limit: %d. limit must not be negative", p.limit)
+	}
+
+	for _, col := range p.selectCols {
+		if err := validateColumn(tableDesc, col, "select"); err != nil {
+			return err
+		}
+	}
+
+	for _, col := range p.exclude {
+		if err := validateColumn(tableDesc, col, "exclude"); err != nil {
+			return err
+		}
+	}
+
+	if len(p.selectCols) > 0 && len(p.selectedColumns()) == 0 ||
+		len(p.selectCols) == 0 && len(p.exclude) >= len(tableDesc.ColumnDescs) {
+		return fmt.Errorf("exclude removes all the columns")
+	}
+
+	return nil
+}
+
+// Returns the select on the source query with the transformations applied.
+// If a mapping is set, query must be the mapped query returned by Mapping.Query.
+func (p *TransformParams) Query(query string) string {
+	if !p.filtersRows() {
+		return query
+	}
+
+	var sb strings.Builder
+
+	sb.WriteString("SELECT ")
+	sb.WriteString(p.projection())
+	sb.WriteString(fmt.Sprintf(" FROM (%s)", query))
+
+	if p.where != dfltWhere {
+		sb.WriteString(fmt.Sprintf(" WHERE (%s)", p.where))
+	}
+
+	if p.limit != dfltLimit {
+		sb.WriteString(fmt.Sprintf(" LIMIT %d", p.limit))
+	}
+
+	return sb.String()
+}
+
+func (p *TransformParams) GetSelect() []string {
+	return p.selectCols
+}
+
+func (p *TransformParams) GetExclude() []string {
+	return p.exclude
+}
+
+func (p *TransformParams) GetWhere() string {
+	return p.where
+}
+
+func (p *TransformParams) GetLimit() int64 {
+	return p.limit
+}
+
+func (p *TransformParams) GetMapping() *Mapping {
+	return p.mapping
+}
+
+func (p *TransformParams) GetRules() *Rules {
+	return p.rules
+}
+
+func (p *TransformParams) GetRulesReport() string {
+	return p.rulesReport
+}
+
+func (p *TransformParams) projection() string {
+	if len(p.selectCols) == 0 {
+		if len(p.exclude) == 0 {
+			return "*"
+		}
+		return fmt.Sprintf("* EXCLUDE (%s)", param.QuoteIdents(p.exclude))
+	}
+
+	return param.QuoteIdents(p.selectedColumns())
+}
+
+// Returns the selected columns which are not excluded
+func (p *TransformParams) selectedColumns() []string {
+	cols := []string{}
+	for _, col := range p.selectCols {
+		if !containsFold(p.exclude, col) {
+			cols = append(cols, col)
+		}
+	}
+	return cols
+}
+
+func validateColumn(tableDesc *model.TableDesc, col string, option string) error {
+	names := make([]string, 0, len(tableDesc.ColumnDescs))
+	for _, colDesc := range tableDesc.ColumnDescs {
+		names = append(names, colDesc.ColName)
+	}
+
+	// DuckDB column names are case insensitive
+	if containsFold(names, col) {
+		return nil
+	}
+
+	msg := fmt.Sprintf("unknown column %q in %s.", col, option)
+	if suggestion := closestMatch(names, col); suggestion != "" {
+		msg += fmt.Sprintf(" did you mean %q?", suggestion)
+	}
+
+	return fmt.Errorf("%s available columns: %s", msg, strings.Join(names, ", "))
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns the name closest to value by edit distance, if it is close enough to be a likely typo
+func closestMatch(names []string, value string) string {
+	closest := ""
+	minDist := len(value)/2 + 1
+
+	for _, name := range names {
+		dist := levenshtein(strings.ToLower(name), strings.ToLower(value))
+		if dist < minDist {
+			minDist = dist
+			closest = name
+		}
+	}
+
+	return closest
+}
+
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+
+	return prev[len(rb)]
+}
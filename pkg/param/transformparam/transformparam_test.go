@@ -0,0 +1,116 @@
+package transformparam
+
+import (
+	"testing"
+
+	"github.com/hbbtekademy/go-fileconv/pkg/model"
+)
+
+func TestQuery(t *testing.T) {
+	tests := []struct {
+		name           string
+		params         []TransformParam
+		expectedOutput string
+	}{
+		{
+			name:           "TC1",
+			params:         []TransformParam{},
+			expectedOutput: "SELECT * FROM t",
+		},
+		{
+			name: "TC2",
+			params: []TransformParam{
+				WithExclude("col1", "col 2"),
+				WithWhere("col3 > 1"),
+				WithLimit(10),
+			},
+			expectedOutput: `SELECT * EXCLUDE ("col1","col 2") FROM (SELECT * FROM t) WHERE (col3 > 1) LIMIT 10`,
+		},
+		{
+			name: "TC3",
+			params: []TransformParam{
+				WithSelect("col3", "col1", "col2"),
+				WithExclude("col1"),
+			},
+			expectedOutput: `SELECT "col3","col2" FROM (SELECT * FROM t)`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			params := NewTransformParams(tc.params...)
+			actualOutput := params.Query("SELECT * FROM t")
+
+			if actualOutput != tc.expectedOutput {
+				t.Fatalf("expected:\n%s\nbut got:\n%s", tc.expectedOutput, actualOutput)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tableDesc := &model.TableDesc{
+		ColumnDescs: []*model.ColumnDesc{
+			{ColName: "sepal_length", ColType: "DOUBLE"},
+			{ColName: "species", ColType: "VARCHAR"},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		params      []TransformParam
+		expectedErr string
+	}{
+		{
+			name: "TC1",
+			params: []TransformParam{
+				WithSelect("Species"),
+				WithExclude("sepal_length"),
+				WithLimit(5),
+			},
+			expectedErr: "",
+		},
+		{
+			name: "TC2",
+			params: []TransformParam{
+				WithSelect("sepal_lenght"),
+			},
+			expectedErr: `unknown column "sepal_lenght" in select. did you mean "sepal_length"? available columns: sepal_length, species`,
+		},
+		{
+			name: "TC3",
+			params: []TransformParam{
+				WithExclude("petal_width"),
+			},
+			expectedErr: `unknown column "petal_width" in exclude. available columns: sepal_length, species`,
+		},
+		{
+			name: "TC4",
+			params: []TransformParam{
+				WithExclude("sepal_length", "species"),
+			},
+			expectedErr: "exclude removes all the columns",
+		},
+		{
+			name: "TC5",
+			params: []TransformParam{
+				WithLimit(-1),
+			},
+			expectedErr: "invalid limit: -1. limit must not be negative",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := NewTransformParams(tc.params...).Validate(tableDesc)
+
+			actualErr := ""
+			if err != nil {
+				actualErr = err.Error()
+			}
+			if actualErr != tc.expectedErr {
+				t.Fatalf("expected error: %s but got: %s", tc.expectedErr, actualErr)
+			}
+		})
+	}
+}